@@ -1,27 +1,87 @@
 package main
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/mailer"
+	"github.com/fransiscushermanto/backend/internal/ratelimit"
 	"github.com/fransiscushermanto/backend/internal/repositories"
 	"github.com/fransiscushermanto/backend/internal/server/routes"
 	"github.com/fransiscushermanto/backend/internal/services"
 	"github.com/fransiscushermanto/backend/internal/utils"
 )
 
-func newServiceContainer(cfg *config.AppConfig, db *utils.Database, keys *config.CryptoKeys) *routes.Services {
+func newServiceContainer(cfg *config.AppConfig, db *utils.Database, keys *keyring.KeyRing) *routes.Services {
 	// Repositories
 	appRepo := repositories.NewAppRepository(db, &cfg.LockTimeout)
 	userRepo := repositories.NewUserRepository(db)
 	authRepo := repositories.NewAuthRepository(db)
+	federationRepo := repositories.NewFederationRepository(db)
+	oauthRepo := repositories.NewOAuthRepository(db)
+
+	// Mailer
+	mail, err := mailer.NewMailer(cfg)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to initialize mailer")
+	}
+
+	rateLimiter, err := newRateLimiter(cfg, db)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to initialize rate limiter")
+	}
 
 	// Services
-	appService := services.NewAppService(appRepo, cfg.PrefixApiKey, cfg.SecretKey)
+	appService := services.NewAppService(appRepo, cfg.PrefixApiKey, cfg.SecretKey, cfg.ApiKeyPepper, cfg.AppPurgeDelay())
 	userService := services.NewUserService(userRepo, appService)
-	authService := services.NewAuthService(authRepo, userRepo, userService, keys)
+	authService := services.NewAuthService(authRepo, userRepo, userService, appService, mail, cfg, keys)
+	federationService := services.NewFederationService(federationRepo, appService, authService, cfg)
+	oauthService := services.NewOAuthService(oauthRepo, appService, authService)
 
 	return &routes.Services{
-		AppService:  appService,
-		UserService: userService,
-		AuthService: authService,
+		AppService:        appService,
+		UserService:       userService,
+		AuthService:       authService,
+		FederationService: federationService,
+		OAuthService:      oauthService,
+		RateLimiter:       rateLimiter,
+	}
+}
+
+func newKeyRing(ctx context.Context, cfg *config.AppConfig, db *utils.Database) (*keyring.KeyRing, error) {
+	var store keyring.Store
+
+	switch cfg.KeySource {
+	case keyring.SourceEnv:
+		cryptoKeys, err := config.LoadCryptoKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		store, err = keyring.NewEnvStore(cryptoKeys)
+		if err != nil {
+			return nil, err
+		}
+	case keyring.SourceDir:
+		store = keyring.NewDirStore(cfg.KeysDir)
+	case keyring.SourceDB:
+		store = repositories.NewSigningKeyRepository(db, cfg.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown key source %q", cfg.KeySource)
+	}
+
+	return keyring.New(ctx, store)
+}
+
+func newRateLimiter(cfg *config.AppConfig, db *utils.Database) (*ratelimit.Limiter, error) {
+	switch cfg.RateLimitStore {
+	case ratelimit.StoreMemory:
+		return ratelimit.New(ratelimit.NewMemoryStore()), nil
+	case ratelimit.StoreDB:
+		return ratelimit.New(repositories.NewRateLimitRepository(db)), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
 	}
 }
@@ -1,40 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
-	"sync"
+	"time"
 
 	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/keyring"
 	"github.com/fransiscushermanto/backend/internal/server"
 	"github.com/fransiscushermanto/backend/internal/utils"
 )
 
 func main() {
-	var wg sync.WaitGroup
-	var cfg *config.AppConfig
-	var keys *config.CryptoKeys
-	var cfgErr, keyErr error
-
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		cfg, cfgErr = config.LoadConfig()
-	}()
-
-	go func() {
-		defer wg.Done()
-		keys, keyErr = config.LoadCryptoKeys()
-	}()
-
-	wg.Wait()
-
-	if cfgErr != nil {
-		log.Fatalf("Failed to load configuration: %v", cfgErr)
-	}
-
-	if keyErr != nil {
-		log.Fatalf("Failed to load crypto keys: %v", keyErr)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	utils.SetLogLevel(cfg.LogLevel)
@@ -44,8 +25,7 @@ func main() {
 		Str("env", cfg.Env).
 		Str("log_level", cfg.LogLevel).
 		Int("port", cfg.Port).
-		Str("key_type", keys.PublicKeyInfo()).
-		Bool("keys_valid", keys.IsValid()).
+		Str("key_source", cfg.KeySource).
 		Msg("Application configuration loaded")
 
 	db, err := utils.NewDatabase(cfg.DatabaseURL)
@@ -57,7 +37,34 @@ func main() {
 		utils.Log().Info().Msg("Database connection closed")
 	}()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keys, err := newKeyRing(ctx, cfg, db)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to load signing keys")
+	}
+
+	signingKey, err := keys.SigningKey()
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to load signing keys")
+	}
+
+	utils.Log().Info().
+		Str("kid", signingKey.Kid).
+		Int("keys", len(keys.Keys())).
+		Msg("Signing keys loaded")
+
+	if cfg.KeySource != keyring.SourceEnv {
+		go keys.Watch(ctx, time.Duration(cfg.KeyRefreshInterval)*time.Second)
+	}
+
 	services := newServiceContainer(cfg, db, keys)
+
+	go services.AppService.WatchDeletedApps(ctx, constants.DEFAULT_APP_PURGE_INTERVAL)
+	go services.AuthService.WatchLoginFailures(ctx, constants.DEFAULT_LOGIN_FAILURE_PURGE_INTERVAL)
+	go services.RateLimiter.Watch(ctx, constants.DEFAULT_RATE_LIMIT_PURGE_INTERVAL, cfg.RateLimitWindow())
+
 	apiServer := server.NewAPIServer(cfg, services, keys)
 
 	if err := apiServer.Run(); err != nil {
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories"
+	"github.com/fransiscushermanto/backend/internal/services"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+const usage = `Manage the API keys and scopes of an app.
+
+Usage:
+  appctl list-keys -app <app id>
+  appctl rotate-key -app <app id> [-kind publishable|secret] [-grace <duration>]
+  appctl set-scopes -app <app id> -scopes "<scope> ..."
+
+rotate-key prints the new key once; store it before closing the terminal.
+It rotates the publishable keys unless -kind is secret. The previous keys of
+that kind keep working for -grace (e.g. 24h, at most 168h), so clients can
+switch over first. Without -grace they stop working right away.
+
+set-scopes replaces the scopes the app may request with the
+client_credentials grant. Keep apps:read and apps:write for the app to
+manage itself through the API.
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Log().Fatal().Msgf("Failed to load configuration: %v", err)
+	}
+
+	utils.SetLogLevel(cfg.LogLevel)
+
+	db, err := utils.NewDatabase(cfg.DatabaseURL)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	appRepo := repositories.NewAppRepository(db, &cfg.LockTimeout)
+	appService := services.NewAppService(appRepo, cfg.PrefixApiKey, cfg.SecretKey, cfg.ApiKeyPepper, cfg.AppPurgeDelay())
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+
+	switch command {
+	case "list-keys":
+		flags := flag.NewFlagSet(command, flag.ExitOnError)
+		appID := appIDFlag(flags)
+		flags.Parse(args)
+
+		listKeys(ctx, appService, parseAppID(command, *appID))
+	case "rotate-key":
+		flags := flag.NewFlagSet(command, flag.ExitOnError)
+		appID := appIDFlag(flags)
+		kind := flags.String("kind", string(models.AppApiKeyPublishable), "Kind of key to rotate, publishable or secret")
+		grace := flags.Duration("grace", 0, "How long the previous keys keep working")
+		flags.Parse(args)
+
+		id := parseAppID(command, *appID)
+
+		if *kind != string(models.AppApiKeyPublishable) && *kind != string(models.AppApiKeySecret) {
+			utils.Log().Fatal().Str("kind", *kind).Msg("-kind must be publishable or secret")
+		}
+
+		rotated, err := appService.RotateAPIKey(ctx, id, models.AppApiKeyKind(*kind), *grace)
+		if err != nil {
+			utils.Log().Fatal().Err(err).Str("app_id", id.String()).Msg("Failed to rotate API key")
+		}
+
+		fmt.Printf("%s key: %s\n", rotated.Kind, rotated.APIKey)
+		fmt.Printf("Previous keys revoked at %s\n", rotated.PreviousKeysRevokedAt.Format(time.RFC3339))
+	case "set-scopes":
+		flags := flag.NewFlagSet(command, flag.ExitOnError)
+		appID := appIDFlag(flags)
+		scopes := flags.String("scopes", "", "Space separated scopes to grant")
+		flags.Parse(args)
+
+		id := parseAppID(command, *appID)
+
+		granted, err := appService.SetGrantedScopes(ctx, id, strings.Fields(*scopes))
+		if err != nil {
+			utils.Log().Fatal().Err(err).Str("app_id", id.String()).Msg("Failed to set granted scopes")
+		}
+
+		fmt.Printf("Granted scopes: %s\n", strings.Join(granted, " "))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func appIDFlag(flags *flag.FlagSet) *string {
+	return flags.String("app", "", "ID of the app")
+}
+
+func parseAppID(command string, appID string) uuid.UUID {
+	if appID == "" {
+		utils.Log().Fatal().Msgf("%s requires -app", command)
+	}
+
+	id, err := uuid.Parse(appID)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("-app must be a UUID")
+	}
+
+	return id
+}
+
+func listKeys(ctx context.Context, appService *services.AppService, appID uuid.UUID) {
+	apiKeys, err := appService.ListAPIKeys(ctx, appID)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Str("app_id", appID.String()).Msg("Failed to list API keys")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKEY ID\tKIND\tACTIVE\tCREATED\tLAST USED\tREVOKED")
+
+	for _, apiKey := range apiKeys {
+		keyID := apiKey.KeyID
+		if keyID == "" {
+			keyID = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%s\n",
+			apiKey.ID,
+			keyID,
+			apiKey.Kind,
+			apiKey.Active,
+			formatTime(&apiKey.CreatedAt),
+			formatTime(apiKey.LastUsedAt),
+			formatTime(apiKey.RevokedAt),
+		)
+	}
+
+	w.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
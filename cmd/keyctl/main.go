@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/repositories"
+	"github.com/fransiscushermanto/backend/internal/utils"
+)
+
+const usage = `Manage the token signing keys of the dir and db key sources.
+
+Usage:
+  keyctl list
+  keyctl stage
+  keyctl import -file <pem>
+  keyctl promote -kid <kid> [-at <RFC3339|now>]
+  keyctl retire -kid <kid> [-at <RFC3339|now>]
+
+A rotation without downtime:
+  1. stage    publishes a new key in the JWKS without signing with it.
+  2. promote  once verifiers have refreshed their JWKS cache (1 hour), makes
+              the staged key sign new tokens. The previous key keeps verifying.
+  3. retire   stops the previous key from verifying. By default it is retired
+              once the longest lived token it may have signed (a refresh token
+              with the maximum TTL an app can set) expired.
+
+To move from KEY_SOURCE=env, import the PRIVATE_KEY pem and promote it before
+switching the servers over, so tokens it signed keep verifying.
+
+Running servers pick up changes within KEY_REFRESH_INTERVAL seconds.
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		utils.Log().Fatal().Msgf("Failed to load configuration: %v", err)
+	}
+
+	utils.SetLogLevel(cfg.LogLevel)
+
+	ctx := context.Background()
+
+	var store keyring.Store
+
+	switch cfg.KeySource {
+	case keyring.SourceDir:
+		store = keyring.NewDirStore(cfg.KeysDir)
+	case keyring.SourceDB:
+		db, err := utils.NewDatabase(cfg.DatabaseURL)
+		if err != nil {
+			utils.Log().Fatal().Err(err).Msg("Failed to connect to database")
+		}
+		defer db.Close()
+
+		store = repositories.NewSigningKeyRepository(db, cfg.SecretKey)
+	default:
+		utils.Log().Fatal().Str("key_source", cfg.KeySource).Msg("keyctl requires KEY_SOURCE=dir or KEY_SOURCE=db")
+	}
+
+	ring, err := keyring.New(ctx, store)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to load signing keys")
+	}
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+
+	switch command {
+	case "list":
+		list(ring)
+	case "stage":
+		key, err := ring.Stage(ctx)
+		if err != nil {
+			utils.Log().Fatal().Err(err).Msg("Failed to stage signing key")
+		}
+
+		fmt.Printf("Staged %s\n", key.Kid)
+	case "import":
+		key := readKeyFile(args)
+
+		if err := ring.Add(ctx, key); err != nil {
+			utils.Log().Fatal().Err(err).Str("kid", key.Kid).Msg("Failed to import signing key")
+		}
+
+		fmt.Printf("Imported %s as staged\n", key.Kid)
+	case "promote":
+		kid, at := parseKeyFlags(command, args, time.Now())
+
+		key, err := ring.Promote(ctx, kid, at)
+		if err != nil {
+			utils.Log().Fatal().Err(err).Str("kid", kid).Msg("Failed to promote signing key")
+		}
+
+		fmt.Printf("Promoted %s, signing from %s\n", key.Kid, key.ActivatesAt.Format(time.RFC3339))
+	case "retire":
+		kid, at := parseKeyFlags(command, args, time.Now().Add(constants.MAX_REFRESH_TOKEN_EXPIRY))
+
+		key, err := ring.Retire(ctx, kid, at)
+		if err != nil {
+			utils.Log().Fatal().Err(err).Str("kid", kid).Msg("Failed to retire signing key")
+		}
+
+		fmt.Printf("Retired %s from %s\n", key.Kid, key.RetiresAt.Format(time.RFC3339))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func parseKeyFlags(command string, args []string, defaultAt time.Time) (string, time.Time) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	kid := flags.String("kid", "", "ID of the key")
+	at := flags.String("at", "", "When the change takes effect (RFC3339 or now)")
+	flags.Parse(args)
+
+	if *kid == "" {
+		utils.Log().Fatal().Msgf("%s requires -kid", command)
+	}
+
+	switch *at {
+	case "":
+		return *kid, defaultAt
+	case "now":
+		return *kid, time.Now()
+	}
+
+	parsed, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("-at must be an RFC3339 time or now")
+	}
+
+	return *kid, parsed
+}
+
+func readKeyFile(args []string) *keyring.Key {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "", "PEM file holding a private key, or a public key to only verify with")
+	flags.Parse(args)
+
+	if *file == "" {
+		utils.Log().Fatal().Msg("import requires -file")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Failed to read key file")
+	}
+
+	var key *keyring.Key
+	if strings.Contains(string(data), "-----BEGIN PUBLIC KEY-----") {
+		key, err = keyring.NewKey("", "", string(data))
+	} else {
+		key, err = keyring.NewKey("", string(data), "")
+	}
+
+	if err != nil {
+		utils.Log().Fatal().Err(err).Msg("Invalid key file")
+	}
+
+	key.CreatedAt = time.Now()
+	return key
+}
+
+func list(ring *keyring.KeyRing) {
+	now := time.Now()
+	keys := ring.Keys()
+	signingKey, _ := ring.SigningKey()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KID\tSTATUS\tSIGNING\tCREATED\tACTIVATES\tRETIRES")
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n",
+			key.Kid,
+			key.Status(now),
+			signingKey != nil && signingKey.Kid == key.Kid,
+			formatTime(&key.CreatedAt),
+			formatTime(key.ActivatesAt),
+			formatTime(key.RetiresAt),
+		)
+	}
+
+	w.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -61,7 +61,7 @@ func main() {
 
 func initAppSeeder(cfg *config.AppConfig, db *utils.Database) *seeder.AppSeeder {
 	appRepo := repositories.NewAppRepository(db, &cfg.LockTimeout)
-	appService := services.NewAppService(appRepo, cfg.PrefixApiKey, cfg.SecretKey)
+	appService := services.NewAppService(appRepo, cfg.PrefixApiKey, cfg.SecretKey, cfg.ApiKeyPepper, cfg.AppPurgeDelay())
 	return seeder.NewAppSeeder(db, appService)
 }
 
@@ -3,23 +3,53 @@ package config
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"net"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type AppConfig struct {
-	Env             string   `yaml:"env" env:"APP_ENV"`
-	Port            int      `yaml:"port" env:"APP_PORT"`
-	DatabaseURL     string   `yaml:"database_url" env:"DATABASE_URL"`
-	ShutdownTimeout int      `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
-	LogLevel        string   `yaml:"log_level" env:"LOG_LEVEL"`
-	AllowedOrigins  []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
-	SecretKey       string   `yaml:"secret_key" env:"SECRET_KEY"`
-	PrefixApiKey    string   `yaml:"prefix_api_key" env:"PREFIX_API_KEY"`
-	LockTimeout     int      `yaml:"lock_timeout" env:"LOCK_TIMEOUT"`
-	SSLCertPath     string   `yaml:"ssl_cert_path" env:"SSL_CERT_PATH"`
-	SSLKeyPath      string   `yaml:"ssl_key_path" env:"SSL_KEY_PATH"`
+	Env                     string   `yaml:"env" env:"APP_ENV"`
+	Port                    int      `yaml:"port" env:"APP_PORT"`
+	DatabaseURL             string   `yaml:"database_url" env:"DATABASE_URL"`
+	ShutdownTimeout         int      `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	LogLevel                string   `yaml:"log_level" env:"LOG_LEVEL"`
+	AllowedOrigins          []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	SecretKey               string   `yaml:"secret_key" env:"SECRET_KEY"`
+	PrefixApiKey            string   `yaml:"prefix_api_key" env:"PREFIX_API_KEY"`
+	ApiKeyPepper            string   `yaml:"api_key_pepper" env:"API_KEY_PEPPER"`
+	LockTimeout             int      `yaml:"lock_timeout" env:"LOCK_TIMEOUT"`
+	SSLCertPath             string   `yaml:"ssl_cert_path" env:"SSL_CERT_PATH"`
+	SSLKeyPath              string   `yaml:"ssl_key_path" env:"SSL_KEY_PATH"`
+	PublicURL               string   `yaml:"public_url" env:"PUBLIC_URL"`
+	Issuer                  string   `yaml:"issuer" env:"ISSUER"`
+	MailDriver              string   `yaml:"mail_driver" env:"MAIL_DRIVER"`
+	MailFrom                string   `yaml:"mail_from" env:"MAIL_FROM"`
+	MailFileDir             string   `yaml:"mail_file_dir" env:"MAIL_FILE_DIR"`
+	MailLinkBaseURL         string   `yaml:"mail_link_base_url" env:"MAIL_LINK_BASE_URL"`
+	SMTPHost                string   `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort                int      `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername            string   `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword            string   `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	GoogleJWKSURL           string   `yaml:"google_jwks_url" env:"GOOGLE_JWKS_URL"`
+	KeySource               string   `yaml:"key_source" env:"KEY_SOURCE"`
+	KeysDir                 string   `yaml:"keys_dir" env:"KEYS_DIR"`
+	KeyRefreshInterval      int      `yaml:"key_refresh_interval" env:"KEY_REFRESH_INTERVAL"`
+	AppPurgeDelayHours      int      `yaml:"app_purge_delay_hours" env:"APP_PURGE_DELAY_HOURS"`
+	LoginDelayThreshold     int      `yaml:"login_delay_threshold" env:"LOGIN_DELAY_THRESHOLD"`
+	LoginLockoutThreshold   int      `yaml:"login_lockout_threshold" env:"LOGIN_LOCKOUT_THRESHOLD"`
+	LoginIPLockoutThreshold int      `yaml:"login_ip_lockout_threshold" env:"LOGIN_IP_LOCKOUT_THRESHOLD"`
+	LoginLockoutMinutes     int      `yaml:"login_lockout_minutes" env:"LOGIN_LOCKOUT_MINUTES"`
+	RateLimitStore          string   `yaml:"rate_limit_store" env:"RATE_LIMIT_STORE"`
+	RateLimitWindowSeconds  int      `yaml:"rate_limit_window_seconds" env:"RATE_LIMIT_WINDOW_SECONDS"`
+	RateLimitAuthRequests   int      `yaml:"rate_limit_auth_requests" env:"RATE_LIMIT_AUTH_REQUESTS"`
+	RateLimitAppRequests    int      `yaml:"rate_limit_app_requests" env:"RATE_LIMIT_APP_REQUESTS"`
+	RateLimitUserRequests   int      `yaml:"rate_limit_user_requests" env:"RATE_LIMIT_USER_REQUESTS"`
+	RateLimitAuthFailClosed bool     `yaml:"rate_limit_auth_fail_closed" env:"RATE_LIMIT_AUTH_FAIL_CLOSED"`
+	TrustedProxies          []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
 }
 
 type CryptoKeys struct {
@@ -37,6 +67,46 @@ func LoadConfig() (*AppConfig, error) {
 		PrefixApiKey:    "aik_",
 		SSLCertPath:     "ssl/cert.pem",
 		SSLKeyPath:      "ssl/key.pem",
+		PublicURL:       "https://localhost:8080",
+		MailDriver:      "file",
+		MailFrom:        "no-reply@localhost",
+		MailLinkBaseURL: "http://localhost:3000",
+		SMTPPort:        1025,
+		GoogleJWKSURL:   "https://www.googleapis.com/oauth2/v3/certs",
+		// Signing keys come from "env" (PRIVATE_KEY/PUBLIC_KEY, a single key),
+		// "dir" (KeysDir) or "db" (core.signing_keys). The dir and db sources
+		// are reloaded every KeyRefreshInterval seconds.
+		KeySource:          "env",
+		KeysDir:            "keys",
+		KeyRefreshInterval: 60,
+		// Deleted apps, with their users and tokens, are kept this long so a
+		// deletion can still be undone by hand.
+		AppPurgeDelayHours: 24 * 30,
+		// Failed logins per email are slowed down exponentially from the
+		// delay threshold on and locked out for LoginLockoutMinutes from the
+		// lockout threshold on. Per IP, which may be shared by many users,
+		// they are only locked out, from a higher threshold.
+		LoginDelayThreshold:     3,
+		LoginLockoutThreshold:   10,
+		LoginIPLockoutThreshold: 50,
+		LoginLockoutMinutes:     15,
+		// Requests per RateLimitWindowSeconds: to the auth endpoints per IP,
+		// to /apps/{id} per app and to the signed-in endpoints per user.
+		// The counts are kept in "memory", per replica, or in the "db",
+		// shared by every replica.
+		RateLimitStore:         "memory",
+		RateLimitWindowSeconds: 60,
+		RateLimitAuthRequests:  30,
+		RateLimitAppRequests:   300,
+		RateLimitUserRequests:  120,
+		// When the counts cannot be reached, the auth endpoints refuse
+		// requests rather than let credential stuffing through unlimited;
+		// the others are let through.
+		RateLimitAuthFailClosed: true,
+		// IPs or CIDRs of the reverse proxies in front of the API. Only
+		// their X-Forwarded-For and X-Real-IP headers are believed; with
+		// none, the client address is always the connection's.
+		TrustedProxies: []string{},
 	}
 
 	configPath := os.Getenv("CONFIG_PATH")
@@ -56,10 +126,51 @@ func LoadConfig() (*AppConfig, error) {
 		return nil, fmt.Errorf("environment configuration failed: %w", err)
 	}
 
+	// The token issuer defaults to the public URL the discovery document is served from.
+	if config.Issuer == "" {
+		config.Issuer = config.PublicURL
+	}
+
+	// API key hashes are keyed with the pepper, so changing it invalidates
+	// every key. It must differ from the secret key, which encrypts app names
+	// and client secrets, so one leak does not expose both; only development
+	// falls back to it.
+	if config.ApiKeyPepper == "" && config.Env == "development" {
+		config.ApiKeyPepper = config.SecretKey
+	} else if config.ApiKeyPepper == "" {
+		return nil, fmt.Errorf("API_KEY_PEPPER is not set via config file or environment variable. This is a mandatory setting outside development")
+	} else if config.ApiKeyPepper == config.SecretKey && config.Env != "development" {
+		return nil, fmt.Errorf("API_KEY_PEPPER must differ from SECRET_KEY")
+	}
+
 	if config.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL is not set via config file or environment variable. This is a mandatory setting")
 	}
 
+	positiveSettings := []struct {
+		name  string
+		value int
+	}{
+		{"RATE_LIMIT_WINDOW_SECONDS", config.RateLimitWindowSeconds},
+		{"RATE_LIMIT_AUTH_REQUESTS", config.RateLimitAuthRequests},
+		{"RATE_LIMIT_APP_REQUESTS", config.RateLimitAppRequests},
+		{"RATE_LIMIT_USER_REQUESTS", config.RateLimitUserRequests},
+		{"LOGIN_DELAY_THRESHOLD", config.LoginDelayThreshold},
+		{"LOGIN_LOCKOUT_THRESHOLD", config.LoginLockoutThreshold},
+		{"LOGIN_IP_LOCKOUT_THRESHOLD", config.LoginIPLockoutThreshold},
+		{"LOGIN_LOCKOUT_MINUTES", config.LoginLockoutMinutes},
+	}
+
+	for _, setting := range positiveSettings {
+		if setting.value <= 0 {
+			return nil, fmt.Errorf("%s must be greater than 0, got %d", setting.name, setting.value)
+		}
+	}
+
+	if _, err := config.TrustedProxyNetworks(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -75,12 +186,12 @@ func LoadCryptoKeys() (*CryptoKeys, error) {
 		return nil, fmt.Errorf("PUBLIC_KEY environment variable is required")
 	}
 
-	privateKey, err := parsePrivateKey(privateKeyData)
+	privateKey, err := ParsePrivateKey(privateKeyData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	publicKey, err := parsePublicKey(publicKeyData)
+	publicKey, err := ParsePublicKey(publicKeyData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse public key: %w", err)
 	}
@@ -128,6 +239,53 @@ func (ck *CryptoKeys) PublicKeyInfo() string {
 	return fmt.Sprintf("ECDSA-%d", ck.PublicKey.Curve.Params().BitSize)
 }
 
+func (ac *AppConfig) AppPurgeDelay() time.Duration {
+	return time.Duration(ac.AppPurgeDelayHours) * time.Hour
+}
+
+func (ac *AppConfig) LoginLockoutDuration() time.Duration {
+	return time.Duration(ac.LoginLockoutMinutes) * time.Minute
+}
+
+func (ac *AppConfig) RateLimitWindow() time.Duration {
+	return time.Duration(ac.RateLimitWindowSeconds) * time.Second
+}
+
+// TrustedProxyNetworks parses TrustedProxies; a bare IP is a single-address network.
+func (ac *AppConfig) TrustedProxyNetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(ac.TrustedProxies))
+
+	for _, proxy := range ac.TrustedProxies {
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("TRUSTED_PROXIES: invalid IP %q", proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: invalid CIDR %q: %w", proxy, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
 // Override String method to prevent accidental exposure of AppConfig secrets
 func (ac *AppConfig) String() string {
 	return fmt.Sprintf("AppConfig{Env:%s, Port:%d, LogLevel:%s, [SECRETS REDACTED]}",
@@ -39,6 +39,13 @@ func setConfigFromEnv(config *AppConfig) error {
 				fmt.Fprintf(os.Stderr, "Warning: %s '%s' is not a valid integer, using default %d\n",
 					envTag, envValue, field.Int())
 			}
+		case reflect.Bool:
+			if value, err := strconv.ParseBool(envValue); err == nil {
+				field.SetBool(value)
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: %s '%s' is not a valid boolean, using default %t\n",
+					envTag, envValue, field.Bool())
+			}
 		case reflect.Slice:
 			items := strings.Split(envValue, ",")
 			slice := reflect.MakeSlice(field.Type(), len(items), len(items))
@@ -54,7 +61,7 @@ func setConfigFromEnv(config *AppConfig) error {
 	return nil
 }
 
-func parsePrivateKey(keyData string) (*ecdsa.PrivateKey, error) {
+func ParsePrivateKey(keyData string) (*ecdsa.PrivateKey, error) {
 	if strings.TrimSpace(keyData) == "" {
 		return nil, fmt.Errorf("private key data is empty")
 	}
@@ -100,7 +107,7 @@ func parsePrivateKey(keyData string) (*ecdsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
-func parsePublicKey(keyData string) (*ecdsa.PublicKey, error) {
+func ParsePublicKey(keyData string) (*ecdsa.PublicKey, error) {
 	if strings.TrimSpace(keyData) == "" {
 		return nil, fmt.Errorf("public key data is empty")
 	}
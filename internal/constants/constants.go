@@ -17,4 +17,17 @@ const (
 )
 
 var DEFAULT_JWT_SIGNING_METHOD = jwt.SigningMethodES256
-var DEFAULT_JWT_EXPIRY_HOURS = time.Now().Add(time.Hour * 24)
+var DEFAULT_RESET_PASSWORD_TOKEN_EXPIRY = time.Hour * 24
+var DEFAULT_MAGIC_LINK_EXPIRY = time.Minute * 15
+var DEFAULT_ACCESS_TOKEN_EXPIRY = time.Minute * 30
+var DEFAULT_REFRESH_TOKEN_EXPIRY = time.Hour * 24 * 30
+var MAX_REFRESH_TOKEN_EXPIRY = time.Hour * 24 * 90 // Keep in sync with UpdateAppSettingsRequest
+var DEFAULT_AUTHORIZATION_CODE_EXPIRY = time.Minute
+var DEFAULT_CLIENT_TOKEN_EXPIRY = time.Minute * 15
+var MAX_API_KEY_GRACE_PERIOD = time.Hour * 24 * 7
+var LEGACY_API_KEY_SUNSET = time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC) // Remove AppService.AuthenticateLegacyAPIKey after this
+var DEFAULT_APP_PURGE_INTERVAL = time.Hour
+var DEFAULT_APP_CACHE_TTL = time.Minute * 5
+var DEFAULT_LOGIN_BASE_DELAY = time.Second
+var DEFAULT_LOGIN_FAILURE_PURGE_INTERVAL = time.Hour
+var DEFAULT_RATE_LIMIT_PURGE_INTERVAL = time.Minute * 5
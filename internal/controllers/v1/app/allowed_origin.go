@@ -0,0 +1,141 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	appService "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) CreateAllowedOrigin(w http.ResponseWriter, r *http.Request) {
+	var req models.AllowedOriginRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	allowedOrigin, err := c.appService.CreateAllowedOrigin(r.Context(), appID, &req)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error creating allowed origin")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to allow origin"),
+		}
+
+		switch {
+		case errors.Is(err, utils.ErrNotFound):
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		case errors.Is(err, appService.ErrInvalidOrigin):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Origin must be an http(s) scheme and host without a path, e.g. https://app.example.com or https://*.example.com")
+		case errors.Is(err, appService.ErrOriginExists):
+			errConfig.StatusCode = http.StatusConflict
+			errConfig.Message = utils.StringPointer("Origin already allowed")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, allowedOrigin, nil)
+}
+
+func (c *Controller) GetAllowedOrigins(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	allowedOrigins, err := c.appService.GetAllowedOrigins(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error getting allowed origins")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get allowed origins"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, allowedOrigins, nil)
+}
+
+func (c *Controller) DeleteAllowedOrigin(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	allowedOriginID, err := uuid.Parse(chi.URLParam(r, "allowedOriginID"))
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("Origin not found"),
+		})
+		return
+	}
+
+	err = c.appService.DeleteAllowedOrigin(r.Context(), appID, allowedOriginID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error deleting allowed origin")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to delete origin"),
+		}
+
+		if errors.Is(err, appService.ErrOriginNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("Origin not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	appService "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-playground/validator/v10"
+)
+
+func (c *Controller) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	apiKeys, err := c.appService.ListAPIKeys(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error listing api keys")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to retrieve api keys"),
+		}
+
+		if errors.Is(err, utils.ErrNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, apiKeys, nil)
+}
+
+// RotateAPIKey issues a new publishable or secret key. The body is optional;
+// without a grace period the previous keys of that kind stop working right
+// away.
+func (c *Controller) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req models.RotateAppApiKeyRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	if req.Kind == "" {
+		req.Kind = models.AppApiKeyPublishable
+	}
+
+	rotated, err := c.appService.RotateAPIKey(r.Context(), appID, req.Kind, time.Duration(req.GracePeriodSeconds)*time.Second)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error rotating api key")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to rotate api key"),
+		}
+
+		switch {
+		case errors.Is(err, utils.ErrNotFound):
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		case errors.Is(err, appService.ErrInvalidGracePeriod):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Grace period must be between 0 seconds and 7 days")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, rotated, nil)
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+)
+
+// DeleteApp soft deletes the app behind the API key. The key stops working
+// with it, so this is the last call it can make.
+func (c *Controller) DeleteApp(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	deleted, err := c.appService.DeleteApp(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error deleting app")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to delete app"),
+		}
+
+		if errors.Is(err, utils.ErrNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, deleted, nil)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/fransiscushermanto/backend/internal/models"
@@ -21,3 +22,71 @@ func (c *Controller) GetApps(w http.ResponseWriter, r *http.Request) {
 
 	utils.RespondWithSuccess(w, http.StatusOK, apps, nil)
 }
+
+// CurrentApp describes the app behind a client_credentials token.
+func (c *Controller) CurrentApp(w http.ResponseWriter, r *http.Request) {
+	appID, err := utils.GetAppIDFromContext(r.Context())
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    utils.StringPointer("Invalid token claims"),
+		})
+		return
+	}
+
+	app, err := c.appService.GetApp(r.Context(), appID.String())
+	if err != nil || app == nil {
+		utils.Log().Error().Err(err).Msg("Service error getting current app")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	name, err := c.appService.ParseAppName(string(app.Name))
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Failed to decrypt app name")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get app"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, &models.AppResponse{
+		ID:            app.ID.String(),
+		Name:          name,
+		GrantedScopes: app.GrantedScopes,
+	}, nil)
+}
+
+func (c *Controller) GetApp(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	app, err := c.appService.GetAppDetail(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error getting app")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get app"),
+		}
+
+		if errors.Is(err, utils.ErrNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, app, nil)
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/federation"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+func (c *Controller) CreateIdentityProvider(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateIdentityProviderRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	provider, err := c.federationService.CreateIdentityProvider(r.Context(), appID, &req)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error creating identity provider")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to create identity provider"),
+		}
+
+		switch {
+		case errors.Is(err, utils.ErrNotFound):
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		case errors.Is(err, federation.ErrInvalidProviderName):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Provider must be a lowercase slug and not a built-in provider")
+		case errors.Is(err, federation.ErrInsecureEndpoint):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Issuer must be a public https URL")
+		case errors.Is(err, federation.ErrIdentityProviderExists):
+			errConfig.StatusCode = http.StatusConflict
+			errConfig.Message = utils.StringPointer("Identity provider already exists")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, provider.ToResponse(), nil)
+}
+
+func (c *Controller) GetIdentityProviders(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	providers, err := c.federationService.GetIdentityProviders(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error getting identity providers")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get identity providers"),
+		})
+		return
+	}
+
+	response := make([]*models.IdentityProviderResponse, len(providers))
+	for i, provider := range providers {
+		response[i] = provider.ToResponse()
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, response, nil)
+}
+
+func (c *Controller) DeleteIdentityProvider(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	err = c.federationService.DeleteIdentityProvider(r.Context(), appID, chi.URLParam(r, "provider"))
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error deleting identity provider")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to delete identity provider"),
+		}
+
+		if errors.Is(err, federation.ErrIdentityProviderNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("Identity provider not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
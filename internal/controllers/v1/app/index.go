@@ -1,8 +1,14 @@
 package app
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/fransiscushermanto/backend/internal/services"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 type ControllerOptions struct {
@@ -10,15 +16,43 @@ type ControllerOptions struct {
 }
 
 type Controller struct {
-	appService *services.AppService
-	options    ControllerOptions
+	appService        *services.AppService
+	authService       *services.AuthService
+	federationService *services.FederationService
+	oauthService      *services.OAuthService
+	options           ControllerOptions
 }
 
-func NewController(appService *services.AppService, options ControllerOptions) *Controller {
+func NewController(appService *services.AppService, authService *services.AuthService, federationService *services.FederationService, oauthService *services.OAuthService, options ControllerOptions) *Controller {
 	return &Controller{
-		appService: appService,
-		options:    options,
+		appService:        appService,
+		authService:       authService,
+		federationService: federationService,
+		oauthService:      oauthService,
+		options:           options,
+	}
+}
+
+var errForeignApp = errors.New("app does not belong to the client token")
+
+// authorizedAppID returns the {id} path param once it is checked against the
+// app authenticated by RequireClientAuth, so an app can only manage itself.
+func authorizedAppID(r *http.Request) (uuid.UUID, error) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, err
 	}
+
+	keyAppID, err := utils.GetAppIDFromContext(r.Context())
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if appID != *keyAppID {
+		return uuid.Nil, errForeignApp
+	}
+
+	return appID, nil
 }
 
 var mValidator *validator.Validate = InitValidator()
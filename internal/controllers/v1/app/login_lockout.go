@@ -0,0 +1,61 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-playground/validator/v10"
+)
+
+func (c *Controller) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.UnlockLoginRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	unlocked, err := c.authService.UnlockLogin(r.Context(), appID, &req)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error unlocking login")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to unlock login"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, models.UnlockLoginResponse{Unlocked: unlocked}, nil)
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/oauth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+func (c *Controller) CreateRedirectURI(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRedirectURIRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	redirectURI, err := c.oauthService.CreateRedirectURI(r.Context(), appID, &req)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error creating redirect uri")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to create redirect uri"),
+		}
+
+		switch {
+		case errors.Is(err, utils.ErrNotFound):
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		case errors.Is(err, oauth.ErrInvalidRedirectURI):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Redirect uri must use https, a loopback http address or a reverse-domain custom scheme, without a fragment; a wildcard origin such as https://*.example.com only covers callback_url and redirect_url")
+		case errors.Is(err, oauth.ErrRedirectURIExists):
+			errConfig.StatusCode = http.StatusConflict
+			errConfig.Message = utils.StringPointer("Redirect uri already registered")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusCreated, redirectURI, nil)
+}
+
+func (c *Controller) GetRedirectURIs(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	redirectURIs, err := c.oauthService.GetRedirectURIs(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error getting redirect uris")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get redirect uris"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, redirectURIs, nil)
+}
+
+func (c *Controller) DeleteRedirectURI(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	redirectURIID, err := uuid.Parse(chi.URLParam(r, "redirectURIID"))
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("Redirect uri not found"),
+		})
+		return
+	}
+
+	err = c.oauthService.DeleteRedirectURI(r.Context(), appID, redirectURIID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error deleting redirect uri")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to delete redirect uri"),
+		}
+
+		if errors.Is(err, oauth.ErrRedirectURINotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("Redirect uri not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
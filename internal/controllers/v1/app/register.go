@@ -36,6 +36,7 @@ func (c *Controller) RegisterApp(w http.ResponseWriter, r *http.Request) {
 		}
 
 		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
 	}
 
 	app, err := c.appService.Register(r.Context(), &req)
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	appService "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-playground/validator/v10"
+)
+
+func (c *Controller) GetAppSettings(w http.ResponseWriter, r *http.Request) {
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	settings, err := c.appService.GetSettings(r.Context(), appID)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error getting app settings")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to get app settings"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, settings, nil)
+}
+
+func (c *Controller) UpdateAppSettings(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateAppSettingsRequest
+
+	appID, err := authorizedAppID(r)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("App not found"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		validatorErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		formattedErrors := make(map[string]string)
+		for _, fieldErr := range validatorErrors {
+			formattedErrors[fieldErr.Field()] = utils.GetValidationErrorMessage(fieldErr)
+		}
+
+		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
+		return
+	}
+
+	settings, err := c.appService.UpdateSettings(r.Context(), appID, &req)
+	if err != nil {
+		utils.Log().Error().Err(err).Msg("Service error updating app settings")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to update app settings"),
+		}
+
+		if errors.Is(err, appService.ErrInvalidSettings) {
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("access_token_ttl_seconds must not be longer than refresh_token_ttl_seconds")
+		} else if errors.Is(err, utils.ErrNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("App not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, settings, nil)
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	authService "github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/services/federation"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// The federation state is also kept in a cookie so a callback is only
+// accepted from the browser that started the flow (RFC 6749 §10.12); a state
+// from someone else's flow would otherwise log the victim into their account.
+const (
+	federationStateCookie     = "federation_state"
+	federationStateCookiePath = "/api/v1/federation/"
+)
+
+func setFederationStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     federationStateCookie,
+		Value:    state,
+		Path:     federationStateCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		// Lax: the callback is a cross-site top-level redirect from the provider.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (c *Controller) FederationStart(w http.ResponseWriter, r *http.Request) {
+	var responseType models.AuthResponseType
+
+	federationStartLog := log("FederationStart")
+	provider := chi.URLParam(r, "provider")
+	params := extractAuthQueryParams(r.URL.Query())
+
+	if params.CookieDomain == "" && params.SetCookie {
+		params.CookieDomain = "*." + strings.Split(r.Host, ":")[0]
+	}
+
+	appID, err := uuid.Parse(params.AppID)
+	if err != nil {
+		federationStartLog.Error().Str("app_id", params.AppID).Msg("Missing or Invalid app_id")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusNotFound,
+			Message:    utils.StringPointer("app_id not found"),
+		})
+		return
+	}
+
+	if params.ResponseType == "" {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("response_type is required"),
+		})
+		return
+	}
+
+	if isValidResponseType(params.ResponseType) {
+		responseType = models.AuthResponseType(params.ResponseType)
+	} else {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid response type"),
+		})
+		return
+	}
+
+	if isValid, message := verifyResponseTypeDependentValueExist(responseType, dependentValues{
+		callbackURL: &params.CallbackUrl,
+		redirectURL: &params.RedirectUrl,
+	}); !isValid {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    message,
+		})
+		return
+	}
+
+	if !c.checkRedirectParams(w, r, appID, params) {
+		return
+	}
+
+	authorizeURL, state, err := c.federationService.Start(r.Context(), appID, provider, federation.FederationOptions{
+		ResponseType: responseType,
+		CallbackURL:  params.CallbackUrl,
+		RedirectURL:  params.RedirectUrl,
+		SetCookie:    params.SetCookie,
+		CookieDomain: params.CookieDomain,
+	})
+
+	if err != nil {
+		federationStartLog.Error().Err(err).Str("provider", provider).Msg("Failed to start federation")
+
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Something went wrong"),
+		}
+
+		if errors.Is(err, federation.ErrIdentityProviderNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("Identity provider not found")
+		} else if errors.Is(err, federation.ErrDiscoveryFailed) {
+			errConfig.StatusCode = http.StatusBadGateway
+			errConfig.Message = utils.StringPointer("Identity provider is unavailable")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	setFederationStateCookie(w, state, int(federation.FederationStateTTL.Seconds()))
+	http.Redirect(w, r, authorizeURL, http.StatusFound)
+}
+
+func (c *Controller) FederationCallback(w http.ResponseWriter, r *http.Request) {
+	federationCallbackLog := log("FederationCallback")
+	provider := chi.URLParam(r, "provider")
+
+	stateCookie, err := r.Cookie(federationStateCookie)
+	setFederationStateCookie(w, "", -1)
+
+	if err != nil || subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		federationCallbackLog.Warn().Str("provider", provider).Msg("Federation state does not match the state cookie")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid or expired federation state"),
+		})
+		return
+	}
+
+	loginResponse, options, err := c.federationService.Callback(r.Context(), provider, r.URL.Query(), authService.AuthOptions{
+		IPAddress: clientIP(r),
+		UserAgent: r.UserAgent(),
+	})
+
+	if err != nil {
+		federationCallbackLog.Error().Err(err).Str("provider", provider).Msg("Failed to complete federation")
+
+		if (options.ResponseType == models.AuthResponseCallback || options.ResponseType == models.AuthResponseRedirect) && loginResponse != nil {
+			handleCallbackOrRedirectResponse(w, r, options.ResponseType, loginResponse)
+			return
+		}
+
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Something went wrong"),
+		}
+
+		switch {
+		case errors.Is(err, federation.ErrInvalidState), errors.Is(err, federation.ErrProviderMismatch):
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Invalid or expired federation state")
+		case errors.Is(err, federation.ErrProviderError), errors.Is(err, federation.ErrInvalidIDToken), errors.Is(err, federation.ErrCodeExchangeFailed):
+			errConfig.StatusCode = http.StatusUnauthorized
+			errConfig.Message = utils.StringPointer("Identity provider authentication failed")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeInvalidCredentials}
+		case errors.Is(err, authService.ErrEmailNotVerified):
+			errConfig.StatusCode = http.StatusForbidden
+			errConfig.Message = utils.StringPointer("Please verify your email before logging in")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeEmailNotVerified}
+		default:
+			applyProviderError(&errConfig, err)
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	if options.SetCookie {
+		setAuthCookies(w, loginResponse.AccessToken, loginResponse.ExpiresIn, loginResponse.RefreshToken, loginResponse.RefreshExpiresIn, options.CookieDomain)
+	}
+
+	switch options.ResponseType {
+	case models.AuthResponseRedirect, models.AuthResponseCallback:
+		handleCallbackOrRedirectResponse(w, r, options.ResponseType, loginResponse)
+
+	default:
+		handleJSONResponse(w, loginResponse)
+	}
+}
@@ -2,18 +2,24 @@ package auth
 
 import (
 	authService "github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/services/federation"
+	"github.com/fransiscushermanto/backend/internal/services/oauth"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog"
 )
 
 type Controller struct {
-	authService *authService.AuthService
+	authService       *authService.AuthService
+	federationService *federation.FederationService
+	oauthService      *oauth.OAuthService
 }
 
-func NewController(authService *authService.AuthService) *Controller {
+func NewController(authService *authService.AuthService, federationService *federation.FederationService, oauthService *oauth.OAuthService) *Controller {
 	return &Controller{
-		authService: authService,
+		authService:       authService,
+		federationService: federationService,
+		oauthService:      oauthService,
 	}
 }
 
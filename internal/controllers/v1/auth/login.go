@@ -5,12 +5,12 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/services/auth"
 	"github.com/fransiscushermanto/backend/internal/utils"
-	"github.com/google/uuid"
 )
 
 func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
@@ -28,13 +28,13 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		params.CookieDomain = "*." + strings.Split(r.Host, ":")[0]
 	}
 
-	appID, err := uuid.Parse(params.AppID)
+	appID, err := utils.GetAppIDFromContext(r.Context())
 
 	if err != nil {
-		loginLog.Error().Str("app_id", params.AppID).Msg("Missing or Invalid app_id")
+		loginLog.Error().Err(err).Msg("Context missing app_id")
 		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusNotFound,
-			Message:    utils.StringPointer("app_id not found"),
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
 		})
 		return
 	}
@@ -54,6 +54,11 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 			StatusCode: http.StatusBadRequest,
 			Message:    utils.StringPointer("Invalid response type"),
 		})
+		return
+	}
+
+	if !c.checkRedirectParams(w, r, *appID, params) {
+		return
 	}
 
 	bodyBytes, err := io.ReadAll(r.Body)
@@ -87,10 +92,12 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 	authOptions := auth.AuthOptions{
 		CallbackURL: params.CallbackUrl,
 		RedirectURL: params.RedirectUrl,
+		IPAddress:   clientIP(r),
+		UserAgent:   r.UserAgent(),
 	}
 
 	if loginWithEmailReq.Provider == models.AuthProviderLocal {
-		loginWithEmailReq.AppID = appID
+		loginWithEmailReq.AppID = *appID
 
 		if err := utils.ValidateBodyRequest(loginWithEmailReq); err != nil {
 			loginLog.Error().Err(err).Msg("Local Auth Missing Payload")
@@ -103,10 +110,12 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 
 		if err := mValidator.Struct(loginWithEmailReq); err != nil {
 			loginLog.Error().Err(err).Msg("Local Auth Fields Invalid Value")
-			handleValidationError(w, err)
+			handleValidationError(r.Context(), w, err)
 			return
 		}
 
+		authOptions.DeviceID, authOptions.DeviceName = loginWithEmailReq.DeviceID, loginWithEmailReq.DeviceName
+
 		res, err := c.authService.LoginWithEmail(r.Context(), &loginWithEmailReq, authOptions)
 
 		if err != nil {
@@ -118,8 +127,18 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 			}
 
 			var validationErrors utils.ValidationError
-
-			if errors.As(err, &validationErrors) {
+			var lockedErr *auth.AccountLockedError
+
+			if errors.As(err, &lockedErr) {
+				setRetryAfter(w, lockedErr)
+				errConfig = accountLockedError()
+			} else if errors.Is(err, auth.ErrEmailNotVerified) {
+				errConfig.StatusCode = http.StatusForbidden
+				errConfig.Message = utils.StringPointer("Please verify your email before logging in")
+				errConfig.Meta = &models.ErrorMeta{
+					Code: models.CodeEmailNotVerified,
+				}
+			} else if errors.As(err, &validationErrors) {
 				errConfig.StatusCode = http.StatusUnauthorized
 				errConfig.Message = nil
 				errConfig.Meta = &models.ErrorMeta{
@@ -141,55 +160,92 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		loginResponse = res
 
 	} else if loginWithPasswordlessReq.Provider == models.AuthProviderPasswordless {
-		loginWithPasswordlessReq.AppID = appID
+		loginWithPasswordlessReq.AppID = *appID
 
-		// TODO: remove this check when implemented code for passwordless providers
-		loginLog.Error().Str("provider", string(loginWithPasswordlessReq.Provider)).Msg("Accessing not implemented provider")
-		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusNotImplemented,
-		})
+		if err := utils.ValidateBodyRequest(loginWithPasswordlessReq); err != nil {
+			loginLog.Error().Err(err).Msg("Passwordless Auth Missing Payload")
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+				Message:    utils.StringPointer("Invalid request payload"),
+			})
+			return
+		}
+
+		if err := mValidator.Struct(loginWithPasswordlessReq); err != nil {
+			loginLog.Error().Err(err).Msg("Passwordless Auth Fields Invalid Value")
+			handleValidationError(r.Context(), w, err)
+			return
+		}
+
+		if isValid, message := verifyResponseTypeDependentValueExist(responseType, dependentValues{
+			callbackURL: &params.CallbackUrl,
+			redirectURL: &params.RedirectUrl,
+		}); !isValid {
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+				Message:    message,
+			})
+			return
+		}
+
+		// The magic link carries the original response options so the verify
+		// endpoint can answer the same way the login request asked for.
+		err := c.authService.RequestMagicLink(r.Context(), &loginWithPasswordlessReq, magicLinkParams(queryParams))
+
+		if err != nil {
+			// Never reveal whether the email is registered.
+			loginLog.Error().Err(err).Msg("Failed to request magic link")
+		}
 
-		// TODO: uncomment this check when implemented code for passwordless providers
-		// if err := utils.ValidateBodyRequest(loginWithPasswordlessReq); err != nil {
-		// 	loginLog.Error().Err(err).Msg("Passwordless Auth Missing Payload")
-		// 	utils.RespondWithError(w, models.ApiError{
-		// 		StatusCode: http.StatusBadRequest,
-		// 		Message:    utils.StringPointer("Invalid request payload"),
-		// 	})
-		// 	return
-		// }
-
-		// if err := mValidator.Struct(loginWithPasswordlessReq); err != nil {
-		// 	loginLog.Error().Err(err).Msg("Passwordless Auth Fields Invalid Value")
-		// 	handleValidationError(w, err)
-		// 	return
-		// }
+		utils.RespondWithSuccess(w, http.StatusAccepted, nil, nil)
+		return
 
 	} else if isValidOtherAuthProvider(loginWithOtherProviderReq.Provider) {
-		loginWithOtherProviderReq.AppID = appID
+		loginWithOtherProviderReq.AppID = *appID
 
-		// TODO: remove this check when implemented code for other providers
-		loginLog.Error().Str("provider", string(loginWithOtherProviderReq.Provider)).Msg("Accessing not implemented provider")
-		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusNotImplemented,
-		})
+		if err := utils.ValidateBodyRequest(loginWithOtherProviderReq); err != nil {
+			loginLog.Error().Err(err).Msg("Other Provider Auth Missing Payload")
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusBadRequest,
+				Message:    utils.StringPointer("Invalid request payload"),
+			})
+			return
+		}
 
-		// TODO: uncomment this check when implemented code for other provider
-		// if err := utils.ValidateBodyRequest(loginWithOtherProviderReq); err != nil {
-		// 	loginLog.Error().Err(err).Msg("Other Provider Auth Missing Payload")
-		// 	utils.RespondWithError(w, models.ApiError{
-		// 		StatusCode: http.StatusBadRequest,
-		// 		Message:    utils.StringPointer("Invalid request payload"),
-		// 	})
-		// 	return
-		// }
-
-		// // other than local and passwordless it's asume as other provider
-		// if err := mValidator.Struct(loginWithOtherProviderReq); err != nil {
-		// 	loginLog.Error().Err(err).Msg("Other Provider Auth Fields Invalid Value")
-		// 	handleValidationError(w, err)
-		// 	return
-		// }
+		// other than local and passwordless it's asume as other provider
+		if err := mValidator.Struct(loginWithOtherProviderReq); err != nil {
+			loginLog.Error().Err(err).Msg("Other Provider Auth Fields Invalid Value")
+			handleValidationError(r.Context(), w, err)
+			return
+		}
+
+		authOptions.DeviceID, authOptions.DeviceName = loginWithOtherProviderReq.DeviceID, loginWithOtherProviderReq.DeviceName
+
+		res, err := c.authService.LoginWithProvider(r.Context(), &loginWithOtherProviderReq, authOptions)
+
+		if err != nil {
+			loginLog.Error().Err(err).Str("provider", string(loginWithOtherProviderReq.Provider)).Msg("Failed to login with provider")
+
+			errConfig := models.ApiError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    utils.StringPointer("Something went wrong"),
+			}
+
+			if errors.Is(err, auth.ErrEmailNotVerified) {
+				errConfig.StatusCode = http.StatusForbidden
+				errConfig.Message = utils.StringPointer("Please verify your email before logging in")
+				errConfig.Meta = &models.ErrorMeta{
+					Code: models.CodeEmailNotVerified,
+				}
+			} else {
+				applyProviderError(&errConfig, err)
+			}
+
+			utils.RespondWithError(w, errConfig)
+			return
+		}
+
+		loginResponse = res
 
 	} else {
 		utils.RespondWithError(w, models.ApiError{
@@ -199,9 +255,8 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch {
-	case responseType == models.AuthResponseRedirect:
-	case responseType == models.AuthResponseCallback:
+	switch responseType {
+	case models.AuthResponseRedirect, models.AuthResponseCallback:
 		// Either callbackUrl is provided or responseType is redirect
 		handleCallbackOrRedirectResponse(w, r, responseType, loginResponse)
 
@@ -209,3 +264,17 @@ func (c *Controller) Login(w http.ResponseWriter, r *http.Request) {
 		handleJSONResponse(w, loginResponse)
 	}
 }
+
+func setRetryAfter(w http.ResponseWriter, err *auth.AccountLockedError) {
+	w.Header().Set("Retry-After", strconv.Itoa(err.RetryAfterSeconds()))
+}
+
+func accountLockedError() models.ApiError {
+	return models.ApiError{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    utils.StringPointer("Too many failed login attempts, please try again later"),
+		Meta: &models.ErrorMeta{
+			Code: models.CodeAccountLocked,
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+)
+
+func (c *Controller) Logout(w http.ResponseWriter, r *http.Request) {
+	c.handleLogout(w, r, "Logout", false)
+}
+
+func (c *Controller) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	c.handleLogout(w, r, "LogoutAll", true)
+}
+
+func (c *Controller) handleLogout(w http.ResponseWriter, r *http.Request, method string, allSessions bool) {
+	logoutLog := log(method)
+
+	accessToken, err := utils.GetAccessTokenFromContext(r.Context())
+	if err != nil {
+		logoutLog.Error().Err(err).Msg("Context missing access_token")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal server error"),
+		})
+		return
+	}
+
+	if allSessions {
+		err = c.authService.LogoutAll(r.Context(), accessToken)
+	} else {
+		err = c.authService.Logout(r.Context(), accessToken)
+	}
+
+	if err != nil {
+		logoutLog.Error().Err(err).Msg("Failed to logout")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to logout"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
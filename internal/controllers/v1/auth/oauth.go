@@ -0,0 +1,336 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	authService "github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/services/oauth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Authorize answers with the redirect uri carrying the authorization code
+// rather than a 302, since the credentials are posted from JavaScript.
+func (c *Controller) Authorize(w http.ResponseWriter, r *http.Request) {
+	var loginWithEmailReq models.LoginWithEmailRequest
+	var loginWithOtherProviderReq models.LoginWithOtherProviderRequest
+
+	authorizeLog := log("Authorize")
+	queryParams := r.URL.Query()
+
+	clientID, err := uuid.Parse(queryParams.Get("client_id"))
+	if err != nil {
+		authorizeLog.Error().Str("client_id", queryParams.Get("client_id")).Msg("Missing or Invalid client_id")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("client_id is invalid"),
+		})
+		return
+	}
+
+	authorizeReq := models.AuthorizeRequest{
+		ResponseType:        queryParams.Get("response_type"),
+		ClientID:            clientID,
+		RedirectURI:         queryParams.Get("redirect_uri"),
+		CodeChallenge:       queryParams.Get("code_challenge"),
+		CodeChallengeMethod: queryParams.Get("code_challenge_method"),
+		State:               queryParams.Get("state"),
+	}
+
+	if err := c.oauthService.ValidateAuthorizeRequest(r.Context(), &authorizeReq); err != nil {
+		authorizeLog.Error().Err(err).Str("client_id", clientID.String()).Msg("Invalid authorize request")
+		utils.RespondWithError(w, authorizeError(err))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		authorizeLog.Error().Err(err).Msg("Failed to read the body")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
+		})
+		return
+	}
+	defer r.Body.Close()
+
+	errLoginWithEmailReq := json.Unmarshal(bodyBytes, &loginWithEmailReq)
+	errLoginWithOtherProviderReq := json.Unmarshal(bodyBytes, &loginWithOtherProviderReq)
+
+	if errLoginWithEmailReq != nil && errLoginWithOtherProviderReq != nil {
+		authorizeLog.Error().Err(errLoginWithEmailReq).Err(errLoginWithOtherProviderReq).Msg("Invalid Body JSON")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	var user *models.User
+	var body any
+
+	switch {
+	case loginWithEmailReq.Provider == models.AuthProviderLocal:
+		loginWithEmailReq.AppID = clientID
+		body = loginWithEmailReq
+	case isValidOtherAuthProvider(loginWithOtherProviderReq.Provider):
+		loginWithOtherProviderReq.AppID = clientID
+		body = loginWithOtherProviderReq
+	default:
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := utils.ValidateBodyRequest(body); err != nil {
+		authorizeLog.Error().Err(err).Msg("Missing Payload")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(body); err != nil {
+		authorizeLog.Error().Err(err).Msg("Fields Invalid Value")
+		handleValidationError(r.Context(), w, err)
+		return
+	}
+
+	if loginWithEmailReq.Provider == models.AuthProviderLocal {
+		user, err = c.authService.AuthenticateWithEmail(r.Context(), &loginWithEmailReq, clientIP(r))
+	} else {
+		user, err = c.authService.AuthenticateWithProvider(r.Context(), &loginWithOtherProviderReq)
+	}
+
+	if err != nil {
+		authorizeLog.Error().Err(err).Msg("Failed to authenticate user")
+
+		var lockedErr *authService.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			setRetryAfter(w, lockedErr)
+		}
+
+		utils.RespondWithError(w, authenticationError(err))
+		return
+	}
+
+	res, err := c.oauthService.Authorize(r.Context(), &authorizeReq, user)
+	if err != nil {
+		authorizeLog.Error().Err(err).Msg("Failed to issue authorization code")
+		utils.RespondWithError(w, authorizeError(err))
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, res, nil)
+}
+
+// Token implements the RFC 6749 token endpoint, so both the request
+// (form encoded) and the response body follow the RFC instead of ApiResult.
+func (c *Controller) Token(w http.ResponseWriter, r *http.Request) {
+	tokenLog := log("Token")
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		tokenLog.Error().Err(err).Msg("Invalid form body")
+		utils.RespondWithJSON(w, http.StatusBadRequest, models.OAuthError{Error: "invalid_request"})
+		return
+	}
+
+	req := models.TokenRequest{
+		GrantType:    models.OAuthGrantType(r.PostForm.Get("grant_type")),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+		DeviceID:     r.PostForm.Get("device_id"),
+	}
+
+	req.ClientID, req.ClientSecret = clientCredentials(r, req.ClientID, req.ClientSecret)
+
+	res, err := c.oauthService.Token(r.Context(), &req, authService.AuthOptions{
+		IPAddress: clientIP(r),
+		UserAgent: r.UserAgent(),
+	})
+
+	if err != nil {
+		tokenLog.Error().Err(err).Str("grant_type", string(req.GrantType)).Msg("Failed to issue token")
+		statusCode, oauthErr := tokenError(err)
+		utils.RespondWithJSON(w, statusCode, oauthErr)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// Introspect implements RFC 7662 for resource servers, which authenticate
+// with their app ID and API key like the client_credentials grant.
+func (c *Controller) Introspect(w http.ResponseWriter, r *http.Request) {
+	introspectLog := log("Introspect")
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		introspectLog.Error().Err(err).Msg("Invalid form body")
+		utils.RespondWithJSON(w, http.StatusBadRequest, models.OAuthError{Error: "invalid_request"})
+		return
+	}
+
+	req := models.IntrospectRequest{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+	}
+	req.ClientID, req.ClientSecret = clientCredentials(r, r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"))
+
+	res, err := c.oauthService.Introspect(r.Context(), &req)
+	if err != nil {
+		introspectLog.Error().Err(err).Str("client_id", req.ClientID).Msg("Failed to introspect token")
+		statusCode, oauthErr := tokenError(err)
+		utils.RespondWithJSON(w, statusCode, oauthErr)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, res)
+}
+
+// Revoke implements RFC 7009. It answers 200 with an empty body whether or
+// not the token was valid, so callers cannot probe tokens with it.
+func (c *Controller) Revoke(w http.ResponseWriter, r *http.Request) {
+	revokeLog := log("Revoke")
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		revokeLog.Error().Err(err).Msg("Invalid form body")
+		utils.RespondWithJSON(w, http.StatusBadRequest, models.OAuthError{Error: "invalid_request"})
+		return
+	}
+
+	req := models.RevokeRequest{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+	}
+	req.ClientID, req.ClientSecret = clientCredentials(r, r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"))
+
+	if err := c.oauthService.Revoke(r.Context(), &req); err != nil {
+		revokeLog.Error().Err(err).Str("client_id", req.ClientID).Msg("Failed to revoke token")
+		statusCode, oauthErr := tokenError(err)
+		utils.RespondWithJSON(w, statusCode, oauthErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// clientCredentials prefers the form parameters and falls back to HTTP Basic,
+// whose credentials are form-urlencoded first (RFC 6749 §2.3.1).
+func clientCredentials(r *http.Request, clientID, clientSecret string) (string, string) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return clientID, clientSecret
+	}
+
+	if basicClientID, err := url.QueryUnescape(username); err == nil && clientID == "" {
+		clientID = basicClientID
+	}
+
+	if basicClientSecret, err := url.QueryUnescape(password); err == nil && clientSecret == "" {
+		clientSecret = basicClientSecret
+	}
+
+	return clientID, clientSecret
+}
+
+func authorizeError(err error) models.ApiError {
+	errConfig := models.ApiError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    utils.StringPointer("Something went wrong"),
+	}
+
+	switch {
+	case errors.Is(err, oauth.ErrInvalidClient):
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("client_id is invalid")
+	case errors.Is(err, oauth.ErrInvalidRedirectURI):
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("redirect_uri is not registered for this client")
+	case errors.Is(err, oauth.ErrUnsupportedResponseType):
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("response_type must be code")
+	case errors.Is(err, oauth.ErrInvalidCodeChallenge):
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("code_challenge with code_challenge_method=S256 is required")
+	}
+
+	return errConfig
+}
+
+func authenticationError(err error) models.ApiError {
+	errConfig := models.ApiError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    utils.StringPointer("Something went wrong"),
+	}
+
+	var validationErrors utils.ValidationError
+	var lockedErr *authService.AccountLockedError
+
+	if errors.As(err, &lockedErr) {
+		errConfig = accountLockedError()
+	} else if errors.Is(err, authService.ErrEmailNotVerified) {
+		errConfig.StatusCode = http.StatusForbidden
+		errConfig.Message = utils.StringPointer("Please verify your email before logging in")
+		errConfig.Meta = &models.ErrorMeta{
+			Code: models.CodeEmailNotVerified,
+		}
+	} else if errors.As(err, &validationErrors) {
+		errConfig.StatusCode = http.StatusUnauthorized
+		errConfig.Message = nil
+		errConfig.Meta = &models.ErrorMeta{
+			Code: models.CodeInvalidCredentials,
+		}
+
+		fieldErrors := make(map[string]string)
+		for _, fieldErr := range validationErrors.Fields {
+			fieldErrors[fieldErr.Field] = fieldErr.Message
+		}
+
+		errConfig.Errors = &fieldErrors
+	} else {
+		applyProviderError(&errConfig, err)
+	}
+
+	return errConfig
+}
+
+func tokenError(err error) (int, models.OAuthError) {
+	switch {
+	case errors.Is(err, oauth.ErrInvalidRequest):
+		return http.StatusBadRequest, models.OAuthError{Error: "invalid_request", ErrorDescription: err.Error()}
+	case errors.Is(err, oauth.ErrInvalidClient):
+		return http.StatusUnauthorized, models.OAuthError{Error: "invalid_client", ErrorDescription: err.Error()}
+	case errors.Is(err, oauth.ErrInvalidGrant):
+		return http.StatusBadRequest, models.OAuthError{Error: "invalid_grant", ErrorDescription: err.Error()}
+	case errors.Is(err, oauth.ErrUnauthorizedClient):
+		return http.StatusBadRequest, models.OAuthError{Error: "unauthorized_client", ErrorDescription: err.Error()}
+	case errors.Is(err, oauth.ErrInvalidScope):
+		return http.StatusBadRequest, models.OAuthError{Error: "invalid_scope", ErrorDescription: err.Error()}
+	case errors.Is(err, oauth.ErrUnsupportedGrantType):
+		return http.StatusBadRequest, models.OAuthError{Error: "unsupported_grant_type", ErrorDescription: err.Error()}
+	default:
+		return http.StatusInternalServerError, models.OAuthError{Error: "server_error"}
+	}
+}
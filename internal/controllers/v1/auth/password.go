@@ -2,30 +2,28 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
-	"github.com/google/uuid"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func (c *Controller) ForgetPassword(w http.ResponseWriter, r *http.Request) {
 	var req models.ForgetPasswordRequest
 
 	forgetPasswordLog := log("ForgetPassword")
-	queryParams := r.URL.Query()
-	params := extractAuthQueryParams(queryParams)
 
-	appID, err := uuid.Parse(params.AppID)
+	appID, err := utils.GetAppIDFromContext(r.Context())
 	if err != nil {
-		forgetPasswordLog.Error().Err(err).Msg("Missing or Invalid app_id")
+		forgetPasswordLog.Error().Err(err).Msg("Context missing app_id")
 		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusForbidden,
-			Message:    utils.StringPointer("Forbidden"),
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
 		})
 		return
 	}
-	req.AppID = &appID
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		forgetPasswordLog.Error().Err(err).Msg("Invalid JSON")
@@ -35,6 +33,7 @@ func (c *Controller) ForgetPassword(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	req.AppID = appID
 
 	if err := utils.ValidateBodyRequest(req); err != nil {
 		forgetPasswordLog.Error().Err(err).Msg("Missing required key payload")
@@ -47,7 +46,7 @@ func (c *Controller) ForgetPassword(w http.ResponseWriter, r *http.Request) {
 
 	if err := mValidator.Struct(req); err != nil {
 		forgetPasswordLog.Error().Err(err).Msg("Validation error")
-		handleValidationError(w, err)
+		handleValidationError(r.Context(), w, err)
 		return
 	}
 
@@ -59,3 +58,71 @@ func (c *Controller) ForgetPassword(w http.ResponseWriter, r *http.Request) {
 
 	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
 }
+
+func (c *Controller) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+
+	resetPasswordLog := log("ResetPassword")
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resetPasswordLog.Error().Err(err).Msg("Invalid JSON")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := utils.ValidateBodyRequest(req); err != nil {
+		resetPasswordLog.Error().Err(err).Msg("Missing required key payload")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		resetPasswordLog.Error().Err(err).Msg("Validation error")
+		handleValidationError(r.Context(), w, err)
+		return
+	}
+
+	err := c.authService.ResetPassword(r.Context(), &req)
+
+	if err != nil {
+		resetPasswordLog.Error().Err(err).Msg("Failed to reset password")
+
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to reset password"),
+		}
+
+		var validationErrors utils.ValidationError
+
+		if errors.As(err, &validationErrors) {
+			fieldErrors := make(map[string]string)
+			for _, fieldErr := range validationErrors.Fields {
+				fieldErrors[fieldErr.Field] = fieldErr.Message
+			}
+
+			utils.RespondWithValidationError(w, fieldErrors, nil, nil)
+			return
+		}
+
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Reset password token has expired")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenExpired}
+		} else if isInvalidTokenError(err) {
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Invalid reset password token")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenInvalid}
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	authService "github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (c *Controller) VerifyPasswordless(w http.ResponseWriter, r *http.Request) {
+	var responseType models.AuthResponseType
+
+	verifyPasswordlessLog := log("VerifyPasswordless")
+	queryParams := r.URL.Query()
+	params := extractAuthQueryParams(queryParams)
+
+	if params.CookieDomain == "" && params.SetCookie {
+		params.CookieDomain = "*." + strings.Split(r.Host, ":")[0]
+	}
+
+	token := queryParams.Get("token")
+	if token == "" {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("token is required"),
+		})
+		return
+	}
+
+	if params.ResponseType == "" {
+		responseType = models.AuthResponseJSON
+	} else if isValidResponseType(params.ResponseType) {
+		responseType = models.AuthResponseType(params.ResponseType)
+	} else {
+		verifyPasswordlessLog.Error().Str("response_type", params.ResponseType).Msg("Invalid Response Type")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid response type"),
+		})
+		return
+	}
+
+	if isValid, message := verifyResponseTypeDependentValueExist(responseType, dependentValues{
+		callbackURL: &params.CallbackUrl,
+		redirectURL: &params.RedirectUrl,
+	}); !isValid {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    message,
+		})
+		return
+	}
+
+	// The link is only used up once the urls it sends the user to are known
+	// to belong to its app.
+	appID, err := c.authService.MagicLinkAppID(token)
+	if err != nil {
+		verifyPasswordlessLog.Warn().Err(err).Msg("Invalid magic link")
+		utils.RespondWithError(w, magicLinkError(err))
+		return
+	}
+
+	if !c.checkRedirectParams(w, r, appID, params) {
+		return
+	}
+
+	loginResponse, err := c.authService.VerifyMagicLink(r.Context(), token, authService.AuthOptions{
+		CallbackURL: params.CallbackUrl,
+		RedirectURL: params.RedirectUrl,
+		IPAddress:   clientIP(r),
+		UserAgent:   r.UserAgent(),
+		DeviceID:    queryParams.Get("device_id"),
+		DeviceName:  queryParams.Get("device_name"),
+	})
+
+	if err != nil {
+		verifyPasswordlessLog.Error().Err(err).Msg("Failed to verify magic link")
+
+		if responseType == models.AuthResponseCallback || responseType == models.AuthResponseRedirect {
+			handleCallbackOrRedirectResponse(w, r, responseType, loginResponse)
+			return
+		}
+
+		utils.RespondWithError(w, magicLinkError(err))
+		return
+	}
+
+	if params.SetCookie {
+		setAuthCookies(w, loginResponse.AccessToken, loginResponse.ExpiresIn, loginResponse.RefreshToken, loginResponse.RefreshExpiresIn, params.CookieDomain)
+	}
+
+	switch responseType {
+	case models.AuthResponseRedirect, models.AuthResponseCallback:
+		handleCallbackOrRedirectResponse(w, r, responseType, loginResponse)
+
+	default:
+		handleJSONResponse(w, loginResponse)
+	}
+}
+
+func magicLinkError(err error) models.ApiError {
+	errConfig := models.ApiError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    utils.StringPointer("Something went wrong"),
+	}
+
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		errConfig.StatusCode = http.StatusUnauthorized
+		errConfig.Message = utils.StringPointer("Sign-in link has expired")
+		errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenExpired}
+	} else if isInvalidTokenError(err) {
+		errConfig.StatusCode = http.StatusUnauthorized
+		errConfig.Message = utils.StringPointer("Invalid sign-in link")
+		errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenInvalid}
+	}
+
+	return errConfig
+}
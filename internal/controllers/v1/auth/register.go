@@ -9,7 +9,6 @@ import (
 	"github.com/fransiscushermanto/backend/internal/models"
 	authService "github.com/fransiscushermanto/backend/internal/services/auth"
 	"github.com/fransiscushermanto/backend/internal/utils"
-	"github.com/google/uuid"
 )
 
 func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
@@ -26,19 +25,17 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 		params.CookieDomain = "*." + strings.Split(r.Host, ":")[0]
 	}
 
-	appID, err := uuid.Parse(params.AppID)
+	appID, err := utils.GetAppIDFromContext(r.Context())
 
 	if err != nil {
-		registerLog.Error().Err(err).Msg("Missing or Invalid app_id")
+		registerLog.Error().Err(err).Msg("Context missing app_id")
 		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusNotFound,
-			Message:    utils.StringPointer("app_id not found"),
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
 		})
 		return
 	}
 
-	req.AppID = appID
-
 	if params.ResponseType == "" {
 		registerLog.Error().Msg("Missing response_type")
 		utils.RespondWithError(w, models.ApiError{
@@ -70,6 +67,10 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !c.checkRedirectParams(w, r, *appID, params) {
+		return
+	}
+
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		registerLog.Error().Err(err).Msg("Invalid JSON")
 		utils.RespondWithError(w, models.ApiError{
@@ -79,14 +80,8 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: remove this check when implemented code for other providers
-	if req.Provider != models.AuthProviderLocal {
-		registerLog.Error().Str("provider", string(req.Provider)).Msg("Accessing not implemented provider")
-		utils.RespondWithError(w, models.ApiError{
-			StatusCode: http.StatusNotImplemented,
-		})
-		return
-	}
+	// The app is the one behind the API key, whatever the body claims.
+	req.AppID = *appID
 
 	if err := utils.ValidateBodyRequest(req); err != nil {
 		registerLog.Error().Err(err).Msg("Invalid Payload Request")
@@ -97,15 +92,30 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := mValidator.Struct(req); err != nil {
+	passwordPolicy, err := c.authService.GetPasswordPolicy(r.Context(), *appID)
+	if err != nil {
+		registerLog.Error().Err(err).Msg("Failed to get password policy")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
+		})
+		return
+	}
+
+	validationCtx := utils.ContextWithPasswordPolicy(r.Context(), passwordPolicy)
+	if err := mValidator.StructCtx(validationCtx, req); err != nil {
 		registerLog.Error().Err(err).Msg("Validation error")
-		handleValidationError(w, err)
+		handleValidationError(validationCtx, w, err)
 		return
 	}
 
 	registerResponse, err := c.authService.Register(r.Context(), &req, authService.AuthOptions{
 		CallbackURL: params.CallbackUrl,
 		RedirectURL: params.RedirectUrl,
+		IPAddress:   clientIP(r),
+		UserAgent:   r.UserAgent(),
+		DeviceID:    req.DeviceID,
+		DeviceName:  req.DeviceName,
 	})
 
 	if err != nil {
@@ -125,6 +135,8 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, utils.ErrBadRequest) {
 			errConfig.StatusCode = http.StatusBadRequest
 			errConfig.Message = utils.StringPointer("Please provide valid user data")
+		} else {
+			applyProviderError(&errConfig, err)
 		}
 
 		// If you had specific data or meta to include with this error, you'd add it to errConfig here
@@ -133,12 +145,11 @@ func (c *Controller) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if params.SetCookie {
-		setAuthCookies(w, registerResponse, params.CookieDomain)
+		setAuthCookies(w, registerResponse.AccessToken, registerResponse.ExpiresIn, registerResponse.RefreshToken, registerResponse.RefreshExpiresIn, params.CookieDomain)
 	}
 
-	switch {
-	case responseType == models.AuthResponseRedirect:
-	case responseType == models.AuthResponseCallback:
+	switch responseType {
+	case models.AuthResponseRedirect, models.AuthResponseCallback:
 		// Either callbackUrl is provided or responseType is redirect
 		handleCallbackOrRedirectResponse(w, r, responseType, registerResponse)
 
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type sessionCaller struct {
+	appID      uuid.UUID
+	userID     uuid.UUID
+	currentJTI string
+}
+
+// sessionCallerFromContext reads the caller set by RequireAuth.
+func sessionCallerFromContext(ctx context.Context) (*sessionCaller, error) {
+	userID, err := utils.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	appID, err := utils.GetAppIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshJTI, err := utils.GetRefreshJtiFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionCaller{appID: *appID, userID: *userID, currentJTI: refreshJTI}, nil
+}
+
+func (c *Controller) ListSessions(w http.ResponseWriter, r *http.Request) {
+	listSessionsLog := log("ListSessions")
+
+	caller, err := sessionCallerFromContext(r.Context())
+	if err != nil {
+		listSessionsLog.Error().Err(err).Msg("Context missing session caller")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal server error"),
+		})
+		return
+	}
+
+	sessions, err := c.authService.ListSessions(r.Context(), caller.appID, caller.userID, caller.currentJTI)
+	if err != nil {
+		listSessionsLog.Error().Err(err).Msg("Failed to list sessions")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to retrieve sessions"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusOK, sessions, nil)
+}
+
+func (c *Controller) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	revokeSessionLog := log("RevokeSession")
+
+	caller, err := sessionCallerFromContext(r.Context())
+	if err != nil {
+		revokeSessionLog.Error().Err(err).Msg("Context missing session caller")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal server error"),
+		})
+		return
+	}
+
+	err = c.authService.RevokeSession(r.Context(), caller.appID, caller.userID, chi.URLParam(r, "jti"))
+	if err != nil {
+		revokeSessionLog.Error().Err(err).Msg("Failed to revoke session")
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to revoke session"),
+		}
+
+		if errors.Is(err, utils.ErrNotFound) {
+			errConfig.StatusCode = http.StatusNotFound
+			errConfig.Message = utils.StringPointer("Session not found")
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
+
+func (c *Controller) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	revokeOtherSessionsLog := log("RevokeOtherSessions")
+
+	caller, err := sessionCallerFromContext(r.Context())
+	if err != nil {
+		revokeOtherSessionsLog.Error().Err(err).Msg("Context missing session caller")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal server error"),
+		})
+		return
+	}
+
+	if err := c.authService.RevokeOtherSessions(r.Context(), caller.appID, caller.userID, caller.currentJTI); err != nil {
+		revokeOtherSessionsLog.Error().Err(err).Msg("Failed to revoke other sessions")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to revoke sessions"),
+		})
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
@@ -2,9 +2,11 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/fransiscushermanto/backend/internal/models"
+	authService "github.com/fransiscushermanto/backend/internal/services/auth"
 	"github.com/fransiscushermanto/backend/internal/utils"
 )
 
@@ -31,7 +33,31 @@ func (c *Controller) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := c.authService.RefreshToken(r.Context(), *req.RefreshToken)
+	tokens, err := c.authService.RefreshToken(r.Context(), *req.RefreshToken, authService.AuthOptions{
+		IPAddress: clientIP(r),
+		UserAgent: r.UserAgent(),
+		DeviceID:  *req.DeviceID,
+	})
+	if errors.Is(err, authService.ErrRefreshTokenReused) {
+		refreshTokenLog.Warn().Err(err).Msg("Refresh token reused")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    utils.StringPointer("Refresh token has already been used, please log in again"),
+			Meta:       &models.ErrorMeta{Code: models.CodeRefreshTokenReused},
+		})
+		return
+	}
+
+	if errors.Is(err, authService.ErrDeviceMismatch) {
+		refreshTokenLog.Warn().Err(err).Msg("Refresh token presented from another device")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    utils.StringPointer("Refresh token was issued to another device"),
+			Meta:       &models.ErrorMeta{Code: models.CodeDeviceMismatch},
+		})
+		return
+	}
+
 	if err != nil {
 		refreshTokenLog.Error().Err(err).Msg("Provided token is invalid")
 		utils.RespondWithError(w, models.ApiError{
@@ -1,12 +1,19 @@
 package auth
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/url"
 
 	"github.com/fransiscushermanto/backend/internal/models"
+	authService "github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/services/oauth"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/iancoleman/strcase"
 )
 
@@ -52,7 +59,38 @@ func verifyResponseTypeDependentValueExist(responseType models.AuthResponseType,
 	return true, nil
 }
 
-func handleValidationError(w http.ResponseWriter, err error) {
+// checkRedirectParams rejects callback and redirect urls that are not
+// registered for the app, and a cookie_domain they do not cover. It responds itself
+// and returns false when they are not. Only a cookie_domain sent by the client
+// is checked, not the default derived from the request host.
+func (c *Controller) checkRedirectParams(w http.ResponseWriter, r *http.Request, appID uuid.UUID, params *AuthQueryParams) bool {
+	err := c.oauthService.CheckRedirectURLs(r.Context(), appID, params.CallbackUrl, params.RedirectUrl, r.URL.Query().Get("cookie_domain"))
+	if err == nil {
+		return true
+	}
+
+	errConfig := models.ApiError{
+		StatusCode: http.StatusInternalServerError,
+		Message:    utils.StringPointer("Something went wrong"),
+	}
+
+	if errors.Is(err, oauth.ErrRedirectURLNotAllowed) {
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("callback_url or redirect_url is not allowed for this app")
+		errConfig.Meta = &models.ErrorMeta{Code: models.CodeRedirectURLNotAllowed}
+	} else if errors.Is(err, oauth.ErrCookieDomainNotAllowed) {
+		errConfig.StatusCode = http.StatusBadRequest
+		errConfig.Message = utils.StringPointer("cookie_domain is not allowed for this app")
+		errConfig.Meta = &models.ErrorMeta{Code: models.CodeCookieDomainNotAllowed}
+	}
+
+	utils.RespondWithError(w, errConfig)
+	return false
+}
+
+// handleValidationError renders password-pattern failures with the policy
+// in ctx, the one the request was validated against.
+func handleValidationError(ctx context.Context, w http.ResponseWriter, err error) {
 	validationErrors, ok := err.(validator.ValidationErrors)
 	if !ok {
 		utils.RespondWithError(w, models.ApiError{
@@ -61,22 +99,26 @@ func handleValidationError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	renderPasswordError := utils.RenderPasswordErrorMessage(ctx)
 	formattedErrors := make(map[string]string)
 	for _, fieldErr := range validationErrors {
-		formattedErrors[strcase.ToSnake(fieldErr.Field())] = utils.GetValidationErrorMessage(fieldErr, RenderErrorMessage)
+		formattedErrors[strcase.ToSnake(fieldErr.Field())] = utils.GetValidationErrorMessage(fieldErr, renderPasswordError)
 	}
 
 	utils.RespondWithValidationError(w, formattedErrors, nil, nil)
 }
 
-func setAuthCookies(w http.ResponseWriter, response *models.RegisterResponse, domain string) {
+// setAuthCookies keeps each cookie for as long as its token is valid, which
+// depends on the app's settings; expiresIn and refreshExpiresIn are in
+// seconds as on the auth responses.
+func setAuthCookies(w http.ResponseWriter, accessToken string, expiresIn int, refreshToken string, refreshExpiresIn int, domain string) {
 	// Set access token cookie
 	accessCookie := &http.Cookie{
 		Name:     "access_token",
-		Value:    response.AccessToken,
+		Value:    accessToken,
 		Path:     "/",
 		Domain:   domain,
-		MaxAge:   15 * 60, // 15 minutes
+		MaxAge:   expiresIn,
 		HttpOnly: true,
 		Secure:   true, // Set to true in production with HTTPS
 		SameSite: http.SameSiteStrictMode,
@@ -85,10 +127,10 @@ func setAuthCookies(w http.ResponseWriter, response *models.RegisterResponse, do
 	// Set refresh token cookie
 	refreshCookie := &http.Cookie{
 		Name:     "refresh_token",
-		Value:    response.RefreshToken,
+		Value:    refreshToken,
 		Path:     "/",
 		Domain:   domain,
-		MaxAge:   30 * 24 * 60 * 60, // 30 days
+		MaxAge:   refreshExpiresIn,
 		HttpOnly: true,
 		Secure:   true, // Set to true in production with HTTPS
 		SameSite: http.SameSiteStrictMode,
@@ -102,19 +144,32 @@ func setAuthCookies(w http.ResponseWriter, response *models.RegisterResponse, do
 func handleCallbackOrRedirectResponse(w http.ResponseWriter, r *http.Request, responseType models.AuthResponseType, response any) {
 	var url string
 
+	var redirectURL, callbackURL string
+
+	switch resp := response.(type) {
+	case *models.LoginResponse:
+		if resp != nil {
+			redirectURL, callbackURL = resp.RedirectURL, resp.CallbackURL
+		}
+	case *models.RegisterResponse:
+		if resp != nil {
+			redirectURL, callbackURL = resp.RedirectURL, resp.CallbackURL
+		}
+	}
+
 	switch responseType {
 	case models.AuthResponseRedirect:
-		if resp, ok := response.(*struct {
-			RedirectURL string
-		}); ok {
-			url = resp.RedirectURL
-		}
+		url = redirectURL
 	case models.AuthResponseCallback:
-		if resp, ok := response.(*struct {
-			CallbackURL string
-		}); ok {
-			url = resp.CallbackURL
-		}
+		url = callbackURL
+	}
+
+	if url == "" {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Something went wrong"),
+		})
+		return
 	}
 
 	http.Redirect(w, r, url, http.StatusFound)
@@ -135,3 +190,45 @@ func isValidOtherAuthProvider(provider models.AuthProvider) bool {
 	}
 
 }
+
+func isInvalidTokenError(err error) bool {
+	return errors.Is(err, authService.ErrTokenRevoked) || errors.Is(err, authService.ErrTokenNotFound) || errors.Is(err, authService.ErrTokenMismatch) || errors.Is(err, authService.ErrInvalidTokenType) || errors.Is(err, authService.ErrMissingRequiredClaim) || errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid)
+}
+
+// clientIP is the connection's address, or the forwarded one when the
+// connection comes from a trusted proxy (see middlewares.RealIP).
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func magicLinkParams(queryParams url.Values) url.Values {
+	params := url.Values{}
+
+	for _, key := range []string{"response_type", "callback_url", "redirect_url", "set_cookie", "cookie_domain"} {
+		if value := queryParams.Get(key); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	return params
+}
+
+func applyProviderError(errConfig *models.ApiError, err error) {
+	switch {
+	case errors.Is(err, authService.ErrProviderNotConfigured):
+		errConfig.StatusCode = http.StatusNotImplemented
+		errConfig.Message = utils.StringPointer("Provider is not enabled")
+	case errors.Is(err, authService.ErrInvalidProviderToken), errors.Is(err, authService.ErrMissingRequiredClaim):
+		errConfig.StatusCode = http.StatusUnauthorized
+		errConfig.Message = utils.StringPointer("Invalid provider token")
+		errConfig.Meta = &models.ErrorMeta{Code: models.CodeInvalidCredentials}
+	case errors.Is(err, authService.ErrProviderAccountConflict):
+		errConfig.StatusCode = http.StatusConflict
+		errConfig.Message = utils.StringPointer("An account with this email already exists")
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func (c *Controller) RequestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+
+	requestEmailVerificationLog := log("RequestEmailVerification")
+
+	appID, err := utils.GetAppIDFromContext(r.Context())
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Context missing app_id")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Internal Server Error"),
+		})
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Invalid JSON")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+	req.AppID = appID
+
+	if err := utils.ValidateBodyRequest(req); err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Missing required key payload")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := mValidator.Struct(req); err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Validation error")
+		handleValidationError(r.Context(), w, err)
+		return
+	}
+
+	err = c.authService.RequestEmailVerification(r.Context(), &req)
+
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Failed to request email verification")
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
+
+func (c *Controller) ConfirmEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.ConfirmEmailVerificationRequest
+
+	confirmEmailVerificationLog := log("ConfirmEmailVerification")
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		confirmEmailVerificationLog.Error().Err(err).Msg("Invalid JSON")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	if err := utils.ValidateBodyRequest(req); err != nil {
+		confirmEmailVerificationLog.Error().Err(err).Msg("Missing required key payload")
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    utils.StringPointer("Invalid request payload"),
+		})
+		return
+	}
+
+	err := c.authService.ConfirmEmailVerification(r.Context(), &req)
+
+	if err != nil {
+		confirmEmailVerificationLog.Error().Err(err).Msg("Failed to confirm email verification")
+
+		errConfig := models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to verify email"),
+		}
+
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Email verification token has expired")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenExpired}
+		} else if isInvalidTokenError(err) {
+			errConfig.StatusCode = http.StatusBadRequest
+			errConfig.Message = utils.StringPointer("Invalid email verification token")
+			errConfig.Meta = &models.ErrorMeta{Code: models.CodeTokenInvalid}
+		}
+
+		utils.RespondWithError(w, errConfig)
+		return
+	}
+
+	utils.RespondWithSuccess(w, http.StatusNoContent, nil, nil)
+}
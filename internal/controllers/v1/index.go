@@ -11,10 +11,10 @@ func NewUserController(userService *services.UserService) *userController.Contro
 	return userController.NewController(userService)
 }
 
-func NewAppController(appService *services.AppService, options appController.ControllerOptions) *appController.Controller {
-	return appController.NewController(appService, options)
+func NewAppController(appService *services.AppService, authService *services.AuthService, federationService *services.FederationService, oauthService *services.OAuthService, options appController.ControllerOptions) *appController.Controller {
+	return appController.NewController(appService, authService, federationService, oauthService, options)
 }
 
-func NewAuthController(authService *services.AuthService) *authController.Controller {
-	return authController.NewController(authService)
+func NewAuthController(authService *services.AuthService, federationService *services.FederationService, oauthService *services.OAuthService) *authController.Controller {
+	return authController.NewController(authService, federationService, oauthService)
 }
@@ -46,7 +46,17 @@ func (c *Controller) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := mValidator.Struct(req); err != nil {
+	passwordPolicy, err := c.userService.GetPasswordPolicy(r.Context(), req.AppID)
+	if err != nil {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    utils.StringPointer("Failed to create user"),
+		})
+		return
+	}
+
+	validationCtx := utils.ContextWithPasswordPolicy(r.Context(), passwordPolicy)
+	if err := mValidator.StructCtx(validationCtx, req); err != nil {
 		validationErrors, ok := err.(validator.ValidationErrors)
 		if !ok {
 			utils.RespondWithError(w, models.ApiError{
@@ -55,9 +65,10 @@ func (c *Controller) CreateUser(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		renderPasswordError := utils.RenderPasswordErrorMessage(validationCtx)
 		formattedErrors := make(map[string]string)
 		for _, fieldErr := range validationErrors {
-			formattedErrors[strcase.ToSnake(fieldErr.Field())] = utils.GetValidationErrorMessage(fieldErr, RenderErrorMessage)
+			formattedErrors[strcase.ToSnake(fieldErr.Field())] = utils.GetValidationErrorMessage(fieldErr, renderPasswordError)
 		}
 
 		utils.RespondWithValidationError(w, formattedErrors, nil, nil)
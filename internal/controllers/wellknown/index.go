@@ -0,0 +1,51 @@
+package wellknown
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services"
+	"github.com/fransiscushermanto/backend/internal/utils"
+)
+
+type Controller struct {
+	authService *services.AuthService
+	cfg         *config.AppConfig
+}
+
+func NewController(authService *services.AuthService, cfg *config.AppConfig) *Controller {
+	return &Controller{
+		authService: authService,
+		cfg:         cfg,
+	}
+}
+
+func (c *Controller) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	utils.RespondWithJSON(w, http.StatusOK, c.authService.JWKS())
+}
+
+func (c *Controller) OpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	publicURL := strings.TrimRight(c.cfg.PublicURL, "/")
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	utils.RespondWithJSON(w, http.StatusOK, &models.OpenIDConfiguration{
+		Issuer:                 c.cfg.Issuer,
+		AuthorizationEndpoint:  publicURL + "/api/v1/oauth/authorize",
+		TokenEndpoint:          publicURL + "/api/v1/oauth/token",
+		JWKSURI:                publicURL + "/.well-known/jwks.json",
+		ResponseTypesSupported: []string{"code"},
+		GrantTypesSupported: []string{
+			string(models.GrantTypeAuthorizationCode),
+			string(models.GrantTypeRefreshToken),
+			string(models.GrantTypeClientCredentials),
+		},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{constants.DEFAULT_JWT_SIGNING_METHOD.Alg()},
+		TokenEndpointAuthMethodsSupported: []string{"none", "client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{models.CodeChallengeMethodS256},
+	})
+}
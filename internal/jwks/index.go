@@ -0,0 +1,245 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+)
+
+const minRefreshInterval = time.Minute
+
+var (
+	ErrKeyNotFound    = errors.New("signing key not found in jwks")
+	ErrUnsupportedKey = errors.New("unsupported jwk key type")
+	ErrMissingKeyID   = errors.New("token header is missing kid")
+	ErrFetchFailed    = errors.New("failed to fetch jwks")
+)
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Cache keeps the public keys of a remote JWKS endpoint in memory. Keys are
+// refreshed once the cache expires, or earlier when a token references an
+// unknown kid (at most once per minRefreshInterval).
+type Cache struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	expiresAt   time.Time
+	lastFetched time.Time
+}
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("service", "JWKS").Str("method", method).Logger()
+	return &l
+}
+
+func NewCache(url string, ttl time.Duration) *Cache {
+	return NewCacheWithClient(url, ttl, &http.Client{Timeout: 10 * time.Second})
+}
+
+// NewCacheWithClient is NewCache with a caller-supplied client, for endpoints
+// that must be fetched through a restricted transport.
+func NewCacheWithClient(url string, ttl time.Duration, httpClient *http.Client) *Cache {
+	return &Cache{
+		url:        url,
+		ttl:        ttl,
+		httpClient: httpClient,
+		keys:       map[string]any{},
+	}
+}
+
+func (c *Cache) Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrMissingKeyID
+		}
+
+		return c.Key(ctx, kid)
+	}
+}
+
+func (c *Cache) Key(ctx context.Context, kid string) (any, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	fresh := time.Now().Before(c.expiresAt)
+	canRefetch := time.Since(c.lastFetched) >= minRefreshInterval
+	c.mu.RUnlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+
+	if fresh && !canRefetch {
+		return nil, ErrKeyNotFound
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		// Serve a stale key rather than failing when the endpoint is down.
+		if ok {
+			log("Key").Warn().Err(err).Str("url", c.url).Msg("Using stale jwks key")
+			return key, nil
+		}
+
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+func (c *Cache) refresh(ctx context.Context) error {
+	refreshLog := log("refresh")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		refreshLog.Error().Err(err).Str("url", c.url).Msg("Failed to request jwks")
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		refreshLog.Error().Int("status", res.StatusCode).Str("url", c.url).Msg("Unexpected jwks response status")
+		return fmt.Errorf("%w: unexpected status %d", ErrFetchFailed, res.StatusCode)
+	}
+
+	var set JSONWebKeySet
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return fmt.Errorf("%w: %v", ErrFetchFailed, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			refreshLog.Warn().Err(err).Str("kid", jwk.Kid).Msg("Skipping jwk")
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	c.keys = keys
+	c.lastFetched = now
+	c.expiresAt = now.Add(cacheTTL(res.Header.Get("Cache-Control"), c.ttl))
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (k JSONWebKey) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwk encoding: %w", err)
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func cacheTTL(cacheControl string, fallback time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return fallback
+}
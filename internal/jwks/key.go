@@ -0,0 +1,65 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// NewECSigningKey describes an ECDSA public key as a JWK for the given alg,
+// with its RFC 7638 thumbprint as the kid.
+func NewECSigningKey(publicKey *ecdsa.PublicKey, alg string) (JSONWebKey, error) {
+	params := publicKey.Curve.Params()
+	size := (params.BitSize + 7) / 8
+
+	key := JSONWebKey{
+		Kty: "EC",
+		Use: "sig",
+		Alg: alg,
+		Crv: params.Name,
+		X:   base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+	}
+
+	kid, err := Thumbprint(key)
+	if err != nil {
+		return JSONWebKey{}, err
+	}
+
+	key.Kid = kid
+	return key, nil
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of a JWK.
+func Thumbprint(key JSONWebKey) (string, error) {
+	var members any
+
+	// The required members in lexicographic order, as RFC 7638 §3.2 mandates.
+	switch key.Kty {
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{key.Crv, key.Kty, key.X, key.Y}
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{key.E, key.Kty, key.N}
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedKey, key.Kty)
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
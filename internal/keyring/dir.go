@@ -0,0 +1,192 @@
+package keyring
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const manifestFile = "keyring.yaml"
+
+// DirStore keeps one PEM file per key in a directory, next to a keyring.yaml
+// manifest holding each key's activation and retirement times:
+//
+//	keys:
+//	  - file: 2026-01.pem
+//	    created_at: 2026-01-01T00:00:00Z
+//	    activates_at: 2026-01-02T00:00:00Z
+//	    retires_at: null
+//
+// A file holds either a private key (EC PRIVATE KEY or PRIVATE KEY) or, for
+// keys we only verify with, a PUBLIC KEY. The kid defaults to the key's
+// RFC 7638 thumbprint.
+type DirStore struct {
+	dir string
+}
+
+type manifest struct {
+	Keys []manifestEntry `yaml:"keys"`
+}
+
+type manifestEntry struct {
+	Kid         string     `yaml:"kid,omitempty"`
+	File        string     `yaml:"file"`
+	CreatedAt   time.Time  `yaml:"created_at"`
+	ActivatesAt *time.Time `yaml:"activates_at"`
+	RetiresAt   *time.Time `yaml:"retires_at"`
+}
+
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+func (s *DirStore) Load(ctx context.Context) ([]*Key, error) {
+	m, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(m.Keys))
+
+	for _, entry := range m.Keys {
+		key, err := s.readKey(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", entry.File, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (s *DirStore) Save(ctx context.Context, key *Key) error {
+	m, err := s.readManifest()
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range m.Keys {
+		if current, err := s.readKey(entry); err == nil && current.Kid == key.Kid {
+			m.Keys[i].ActivatesAt = key.ActivatesAt
+			m.Keys[i].RetiresAt = key.RetiresAt
+			return s.writeManifest(m)
+		}
+	}
+
+	var keyPEM string
+	var perm os.FileMode = 0600
+
+	if key.PrivateKey != nil {
+		keyPEM, err = EncodePrivateKey(key.PrivateKey)
+	} else {
+		keyPEM, err = EncodePublicKey(key.PublicKey)
+		perm = 0644
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	file := "signing-" + key.Kid + ".pem"
+	if err := os.WriteFile(filepath.Join(s.dir, file), []byte(keyPEM), perm); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	m.Keys = append(m.Keys, manifestEntry{
+		Kid:         key.Kid,
+		File:        file,
+		CreatedAt:   key.CreatedAt,
+		ActivatesAt: key.ActivatesAt,
+		RetiresAt:   key.RetiresAt,
+	})
+
+	return s.writeManifest(m)
+}
+
+func (s *DirStore) readKey(entry manifestEntry) (*Key, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, entry.File))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	var key *Key
+	if block.Type == "PUBLIC KEY" {
+		key, err = NewKey(entry.Kid, "", string(data))
+	} else {
+		key, err = NewKey(entry.Kid, string(data), "")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	key.CreatedAt = entry.CreatedAt
+	key.ActivatesAt = entry.ActivatesAt
+	key.RetiresAt = entry.RetiresAt
+
+	return key, nil
+}
+
+func (s *DirStore) readManifest() (*manifest, error) {
+	var m manifest
+
+	data, err := os.ReadFile(filepath.Join(s.dir, manifestFile))
+	if os.IsNotExist(err) {
+		return &m, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key manifest: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse key manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// writeManifest replaces the manifest atomically so running servers never
+// read a half written file.
+func (s *DirStore) writeManifest(m *manifest) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode key manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, manifestFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, manifestFile)); err != nil {
+		return fmt.Errorf("failed to write key manifest: %w", err)
+	}
+
+	return nil
+}
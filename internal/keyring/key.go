@@ -0,0 +1,145 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+)
+
+type KeyStatus string
+
+const (
+	KeyStatusStaged  KeyStatus = "staged"
+	KeyStatusActive  KeyStatus = "active"
+	KeyStatusRetired KeyStatus = "retired"
+)
+
+// Key is one entry of the key ring. A key without ActivatesAt is staged: it
+// is published and verifies tokens but does not sign. Once RetiresAt has
+// passed it no longer verifies anything. PrivateKey is nil for keys we only
+// verify with.
+type Key struct {
+	Kid         string
+	PrivateKey  *ecdsa.PrivateKey
+	PublicKey   *ecdsa.PublicKey
+	CreatedAt   time.Time
+	ActivatesAt *time.Time
+	RetiresAt   *time.Time
+}
+
+func (k *Key) Status(now time.Time) KeyStatus {
+	switch {
+	case k.RetiresAt != nil && !k.RetiresAt.After(now):
+		return KeyStatusRetired
+	case k.ActivatesAt != nil && !k.ActivatesAt.After(now):
+		return KeyStatusActive
+	default:
+		return KeyStatusStaged
+	}
+}
+
+// JWK describes the public half of the key, with Kid as its key ID.
+func (k *Key) JWK() (jwks.JSONWebKey, error) {
+	jwk, err := jwks.NewECSigningKey(k.PublicKey, constants.DEFAULT_JWT_SIGNING_METHOD.Alg())
+	if err != nil {
+		return jwks.JSONWebKey{}, err
+	}
+
+	jwk.Kid = k.Kid
+	return jwk, nil
+}
+
+// NewKey builds a key from PEM encoded material. Either half may be empty, but
+// not both; when both are given they must form a pair. An empty kid defaults
+// to the RFC 7638 thumbprint of the public key.
+func NewKey(kid, privateKeyPEM, publicKeyPEM string) (*Key, error) {
+	key := &Key{Kid: kid}
+
+	if privateKeyPEM != "" {
+		privateKey, err := config.ParsePrivateKey(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+
+		key.PrivateKey = privateKey
+		key.PublicKey = &privateKey.PublicKey
+	}
+
+	if publicKeyPEM != "" {
+		publicKey, err := config.ParsePublicKey(publicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+
+		if key.PublicKey != nil && !key.PublicKey.Equal(publicKey) {
+			return nil, fmt.Errorf("private key and public key do not form a valid pair")
+		}
+
+		key.PublicKey = publicKey
+	}
+
+	if key.PublicKey == nil {
+		return nil, fmt.Errorf("key has neither a private nor a public key")
+	}
+
+	if key.PublicKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("%s requires a P-256 key", constants.DEFAULT_JWT_SIGNING_METHOD.Alg())
+	}
+
+	if key.Kid == "" {
+		jwk, err := jwks.NewECSigningKey(key.PublicKey, constants.DEFAULT_JWT_SIGNING_METHOD.Alg())
+		if err != nil {
+			return nil, err
+		}
+
+		key.Kid = jwk.Kid
+	}
+
+	return key, nil
+}
+
+// GenerateKey creates a new staged P-256 key.
+func GenerateKey() (*Key, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ECDSA key pair: %w", err)
+	}
+
+	jwk, err := jwks.NewECSigningKey(&privateKey.PublicKey, constants.DEFAULT_JWT_SIGNING_METHOD.Alg())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Kid:        jwk.Kid,
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func EncodePrivateKey(privateKey *ecdsa.PrivateKey) (string, error) {
+	privateKeyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateKeyBytes})), nil
+}
+
+func EncodePublicKey(publicKey *ecdsa.PublicKey) (string, error) {
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})), nil
+}
@@ -0,0 +1,275 @@
+package keyring
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// Store persists the keys of a KeyRing. Save inserts a key or updates the
+// activation and retirement times of an existing one.
+type Store interface {
+	Load(ctx context.Context) ([]*Key, error)
+	Save(ctx context.Context, key *Key) error
+}
+
+var (
+	ErrNoSigningKey  = errors.New("no active signing key")
+	ErrUnknownKey    = errors.New("unknown signing key")
+	ErrKeyRetired    = errors.New("signing key has been retired")
+	ErrKeyExists     = errors.New("signing key already exists")
+	ErrKeyIsSigning  = errors.New("key is the current signing key")
+	ErrKeyNotSigning = errors.New("key has no private key")
+	ErrReadOnlyStore = errors.New("key store is read-only")
+)
+
+// KeyRing holds every key tokens may be signed with. The most recently
+// activated key with a private key signs; every key that has not been retired
+// verifies and is published in the JWKS, so verifiers learn about a staged key
+// before it is promoted.
+type KeyRing struct {
+	store Store
+
+	mu   sync.RWMutex
+	keys map[string]*Key
+	jwks map[string]jwks.JSONWebKey
+}
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("component", "KeyRing").Str("method", method).Logger()
+	return &l
+}
+
+func New(ctx context.Context, store Store) (*KeyRing, error) {
+	ring := &KeyRing{store: store}
+
+	if err := ring.Reload(ctx); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+func (r *KeyRing) Reload(ctx context.Context) error {
+	storedKeys, err := r.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	keys := make(map[string]*Key, len(storedKeys))
+	jwkByKid := make(map[string]jwks.JSONWebKey, len(storedKeys))
+
+	for _, key := range storedKeys {
+		jwk, err := key.JWK()
+		if err != nil {
+			return fmt.Errorf("failed to describe signing key %s: %w", key.Kid, err)
+		}
+
+		keys[key.Kid] = key
+		jwkByKid[key.Kid] = jwk
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.jwks = jwkByKid
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Watch reloads the ring every interval until ctx is done, so keys staged,
+// promoted or retired by another process take effect without a restart. A
+// failed reload keeps the keys already loaded.
+func (r *KeyRing) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(ctx); err != nil {
+				log("Watch").Error().Err(err).Msg("Failed to reload signing keys")
+			}
+		}
+	}
+}
+
+func (r *KeyRing) Keys() []*Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (r *KeyRing) SigningKey() (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.signingKey(time.Now())
+}
+
+func (r *KeyRing) signingKey(now time.Time) (*Key, error) {
+	var signingKey *Key
+
+	for _, key := range r.keys {
+		if key.PrivateKey == nil || key.Status(now) != KeyStatusActive {
+			continue
+		}
+
+		if signingKey == nil || key.ActivatesAt.After(*signingKey.ActivatesAt) ||
+			(key.ActivatesAt.Equal(*signingKey.ActivatesAt) && key.CreatedAt.After(signingKey.CreatedAt)) {
+			signingKey = key
+		}
+	}
+
+	if signingKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	return signingKey, nil
+}
+
+func (r *KeyRing) VerificationKey(kid string) (*ecdsa.PublicKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if key.Status(time.Now()) == KeyStatusRetired {
+		return nil, ErrKeyRetired
+	}
+
+	return key.PublicKey, nil
+}
+
+// VerificationKeys returns the public half of every key that has not been retired.
+func (r *KeyRing) VerificationKeys() []*ecdsa.PublicKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	publicKeys := make([]*ecdsa.PublicKey, 0, len(r.keys))
+
+	for _, key := range r.keys {
+		if key.Status(now) != KeyStatusRetired {
+			publicKeys = append(publicKeys, key.PublicKey)
+		}
+	}
+
+	return publicKeys
+}
+
+func (r *KeyRing) JWKS() jwks.JSONWebKeySet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	set := jwks.JSONWebKeySet{Keys: []jwks.JSONWebKey{}}
+
+	for kid, key := range r.keys {
+		if key.Status(now) != KeyStatusRetired {
+			set.Keys = append(set.Keys, r.jwks[kid])
+		}
+	}
+
+	return set
+}
+
+// Stage generates a new key and publishes it without signing with it yet.
+func (r *KeyRing) Stage(ctx context.Context) (*Key, error) {
+	key, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return key, r.Add(ctx, key)
+}
+
+// Add stores an existing key, e.g. the one previously configured through
+// PRIVATE_KEY, so tokens it signed keep verifying after moving to this store.
+func (r *KeyRing) Add(ctx context.Context, key *Key) error {
+	r.mu.RLock()
+	_, exists := r.keys[key.Kid]
+	r.mu.RUnlock()
+
+	if exists {
+		return ErrKeyExists
+	}
+
+	if err := r.store.Save(ctx, key); err != nil {
+		return err
+	}
+
+	return r.Reload(ctx)
+}
+
+// Promote makes kid the signing key from at onwards.
+func (r *KeyRing) Promote(ctx context.Context, kid string, at time.Time) (*Key, error) {
+	r.mu.RLock()
+	key, ok := r.keys[kid]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if key.PrivateKey == nil {
+		return nil, ErrKeyNotSigning
+	}
+
+	if key.Status(at) == KeyStatusRetired {
+		return nil, ErrKeyRetired
+	}
+
+	promoted := *key
+	promoted.ActivatesAt = &at
+
+	if err := r.store.Save(ctx, &promoted); err != nil {
+		return nil, err
+	}
+
+	return &promoted, r.Reload(ctx)
+}
+
+// Retire stops kid from verifying tokens from at onwards. The signing key
+// cannot be retired; promote its successor first.
+func (r *KeyRing) Retire(ctx context.Context, kid string, at time.Time) (*Key, error) {
+	r.mu.RLock()
+	key, ok := r.keys[kid]
+	signingKey, _ := r.signingKey(time.Now())
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if signingKey != nil && signingKey.Kid == kid {
+		return nil, ErrKeyIsSigning
+	}
+
+	retired := *key
+	retired.RetiresAt = &at
+
+	if err := r.store.Save(ctx, &retired); err != nil {
+		return nil, err
+	}
+
+	return &retired, r.Reload(ctx)
+}
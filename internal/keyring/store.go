@@ -0,0 +1,51 @@
+package keyring
+
+import (
+	"context"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+)
+
+// Values of config.AppConfig.KeySource.
+const (
+	SourceEnv = "env"
+	SourceDir = "dir"
+	SourceDB  = "db"
+)
+
+// StaticStore serves a fixed set of keys, such as the single pair configured
+// through PRIVATE_KEY and PUBLIC_KEY. Rotating it means restarting with new keys.
+type StaticStore struct {
+	keys []*Key
+}
+
+func NewStaticStore(keys ...*Key) *StaticStore {
+	return &StaticStore{keys: keys}
+}
+
+// NewEnvStore wraps the key pair loaded by config.LoadCryptoKeys as an always
+// active signing key.
+func NewEnvStore(cryptoKeys *config.CryptoKeys) (*StaticStore, error) {
+	jwk, err := jwks.NewECSigningKey(cryptoKeys.PublicKey, constants.DEFAULT_JWT_SIGNING_METHOD.Alg())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStaticStore(&Key{
+		Kid:         jwk.Kid,
+		PrivateKey:  cryptoKeys.PrivateKey,
+		PublicKey:   cryptoKeys.PublicKey,
+		ActivatesAt: &time.Time{},
+	}), nil
+}
+
+func (s *StaticStore) Load(ctx context.Context) ([]*Key, error) {
+	return s.keys, nil
+}
+
+func (s *StaticStore) Save(ctx context.Context, key *Key) error {
+	return ErrReadOnlyStore
+}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer writes every message as a raw .eml file into dir, or to out when
+// no directory is configured. Intended for local development only.
+type FileMailer struct {
+	mu   sync.Mutex
+	out  io.Writer
+	dir  string
+	from string
+}
+
+func NewFileMailer(out io.Writer, dir, from string) *FileMailer {
+	return &FileMailer{
+		out:  out,
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	sendLog := log("FileSend")
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from := msg.From
+	if from == "" {
+		from = m.from
+	}
+
+	raw := buildMIMEMessage(from, msg)
+
+	if m.dir == "" {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if _, err := m.out.Write(append(raw, '\n')); err != nil {
+			return fmt.Errorf("failed to write mail: %w", err)
+		}
+
+		return nil
+	}
+
+	filename := filepath.Join(m.dir, fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), uuid.NewString()))
+
+	if err := os.WriteFile(filename, raw, 0o644); err != nil {
+		sendLog.Error().Err(err).Str("file", filename).Msg("Failed to write mail file")
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	sendLog.Info().Strs("to", msg.To).Str("subject", msg.Subject).Str("file", filename).Msg("Mail written")
+	return nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	HTML    string
+}
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("service", "Mailer").Str("method", method).Logger()
+	return &l
+}
+
+func NewMailer(cfg *config.AppConfig) (Mailer, error) {
+	switch cfg.MailDriver {
+	case DriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER is %q", DriverSMTP)
+		}
+
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case DriverFile, "":
+		if cfg.MailFileDir == "" {
+			return NewFileMailer(os.Stdout, "", cfg.MailFrom), nil
+		}
+
+		if err := os.MkdirAll(cfg.MailFileDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create mail directory %s: %w", cfg.MailFileDir, err)
+		}
+
+		return NewFileMailer(nil, cfg.MailFileDir, cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.MailDriver)
+	}
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	sendLog := log("SMTPSend")
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from := msg.From
+	if from == "" {
+		from = m.from
+	}
+
+	// Local catchers such as MailHog don't support AUTH, so only authenticate
+	// when credentials are configured.
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(m.addr, auth, from, msg.To, buildMIMEMessage(from, msg)); err != nil {
+		sendLog.Error().Err(err).Str("addr", m.addr).Strs("to", msg.To).Msg("Failed to send mail")
+		return fmt.Errorf("failed to send mail via smtp: %w", err)
+	}
+
+	sendLog.Info().Strs("to", msg.To).Str("subject", msg.Subject).Msg("Mail sent")
+	return nil
+}
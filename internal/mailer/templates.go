@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+const (
+	TemplateResetPassword     = "reset_password"
+	TemplateEmailVerification = "email_verification"
+	TemplateLoginAlert        = "login_alert"
+	TemplateMagicLink         = "magic_link"
+)
+
+type ResetPasswordData struct {
+	Name      string
+	AppName   string
+	Link      string
+	ExpiresAt time.Time
+}
+
+type EmailVerificationData struct {
+	Name      string
+	AppName   string
+	Link      string
+	ExpiresAt time.Time
+}
+
+type MagicLinkData struct {
+	Name      string
+	AppName   string
+	Link      string
+	ExpiresAt time.Time
+}
+
+type LoginAlertData struct {
+	Name      string
+	AppName   string
+	IPAddress string
+	UserAgent string
+	LoginAt   time.Time
+}
+
+// Render executes the "<name>_subject" and "<name>" templates and returns a
+// message addressed to the given recipient. Subjects are rendered with the
+// same HTML-escaping set, so they are unescaped before going into the header.
+func Render(name string, to string, data any) (*Message, error) {
+	var subject, body bytes.Buffer
+
+	if err := templates.ExecuteTemplate(&subject, name+"_subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+
+	if err := templates.ExecuteTemplate(&body, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s body: %w", name, err)
+	}
+
+	return &Message{
+		To:      []string{to},
+		Subject: html.UnescapeString(subject.String()),
+		HTML:    body.String(),
+	}, nil
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services"
+	appTypes "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	apiKeyHeader = "X-Api-Key"
+	appIDHeader  = "X-App-Id"
+)
+
+type AppKeyMiddleware struct {
+	appService *services.AppService
+}
+
+func NewAppKeyMiddleware(appService *services.AppService) *AppKeyMiddleware {
+	return &AppKeyMiddleware{
+		appService: appService,
+	}
+}
+
+func appKeyMiddlewareLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("middleware", "AppKey").Str("method", method).Logger()
+	return &l
+}
+
+// RequireAppKey authenticates the calling app by its API key, which names
+// the app itself. An X-App-Id header or app_id query param, if sent, has to
+// match it, and is required for keys issued before key ids (accepted until
+// constants.LEGACY_API_KEY_SUNSET).
+func (m *AppKeyMiddleware) RequireAppKey(next http.Handler) http.Handler {
+	requireAppKeyLog := appKeyMiddlewareLog("RequireAppKey")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := r.Header.Get(apiKeyHeader)
+		if apiKey == "" {
+			respondWithInvalidAPIKey(w, "API key required")
+			return
+		}
+
+		claimedAppID := r.Header.Get(appIDHeader)
+		if claimedAppID == "" {
+			claimedAppID = r.URL.Query().Get("app_id")
+		}
+
+		var app *models.App
+		var err error
+
+		if m.appService.HasKeyID(apiKey) {
+			app, err = m.appService.AuthenticateAPIKey(r.Context(), apiKey)
+		} else if appID, parseErr := uuid.Parse(claimedAppID); parseErr == nil {
+			// Keys issued before key ids can only be checked against the app
+			// they claim to belong to.
+			app, err = m.appService.AuthenticateLegacyAPIKey(r.Context(), appID, apiKey)
+		} else {
+			err = appTypes.ErrInvalidClientCredentials
+		}
+
+		if err != nil {
+			if errors.Is(err, appTypes.ErrInvalidClientCredentials) {
+				requireAppKeyLog.Warn().Msg("Rejected API key")
+				respondWithInvalidAPIKey(w, "Invalid API key")
+				return
+			}
+
+			requireAppKeyLog.Error().Err(err).Msg("Failed to authenticate API key")
+			utils.RespondWithError(w, models.ApiError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    utils.StringPointer("Internal Server Error"),
+			})
+			return
+		}
+
+		if claimedAppID != "" && claimedAppID != app.ID.String() {
+			requireAppKeyLog.Warn().Str("app_id", claimedAppID).Msg("API key belongs to another app")
+			respondWithInvalidAPIKey(w, "Invalid API key")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), utils.AppIDContextKey, app.ID.String())
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func respondWithInvalidAPIKey(w http.ResponseWriter, message string) {
+	utils.RespondWithError(w, models.ApiError{
+		StatusCode: http.StatusUnauthorized,
+		Message:    utils.StringPointer(message),
+		Meta: &models.ErrorMeta{
+			Code: models.CodeInvalidAPIKey,
+		},
+	})
+}
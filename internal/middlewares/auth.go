@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/fransiscushermanto/backend/internal/keyring"
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/services"
 	authTypes "github.com/fransiscushermanto/backend/internal/services/auth"
@@ -33,59 +35,17 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	requireAuthLog := authMiddlewareLog("RequireAuth")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			utils.RespondWithError(w, models.ApiError{
-				StatusCode: http.StatusUnauthorized,
-				Message:    utils.StringPointer("Authorization header required"),
-			})
-			return
-		}
-
-		// Check Bearer format
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.RespondWithError(w, models.ApiError{
-				StatusCode: http.StatusUnauthorized,
-				Message:    utils.StringPointer("Invalid authorization header format"),
-			})
+		tokenString, ok := bearerToken(w, r)
+		if !ok {
 			return
 		}
 
-		tokenString := parts[1]
-
 		// Verify token
 		token, err := m.authService.VerifyAccessToken(r.Context(), tokenString)
 
 		if err != nil {
-			var statusCode int
-			var message string
-			var errorCode models.ErrorCode
-
-			isInvalidToken := errors.Is(err, authTypes.ErrTokenRevoked) || errors.Is(err, authTypes.ErrMissingRequiredClaim) || errors.Is(err, authTypes.ErrTokenMismatch) || errors.Is(err, authTypes.ErrTokenNotFound) || errors.Is(err, jwt.ErrTokenMalformed)
-
-			// Check specific error types
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				statusCode = http.StatusUnauthorized
-				message = "Token has expired"
-				errorCode = models.CodeTokenExpired
-			} else if isInvalidToken {
-				statusCode = http.StatusUnauthorized
-				message = "Invalid token"
-				errorCode = models.CodeTokenInvalid
-			} else {
-				statusCode = http.StatusInternalServerError
-				message = "Internal Server Error"
-			}
-
 			requireAuthLog.Error().Err(err).Msg("Failed to verify access token")
-			utils.RespondWithError(w, models.ApiError{
-				StatusCode: statusCode,
-				Message:    utils.StringPointer(message),
-				Meta: &models.ErrorMeta{
-					Code: errorCode,
-				},
-			})
+			respondWithTokenError(w, err)
 			return
 		}
 
@@ -103,7 +63,116 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, utils.AppIDContextKey, claims[string(utils.AppIDContextKey)])
 		ctx = context.WithValue(ctx, utils.TokenTypeContextKey, claims[string(utils.TokenTypeContextKey)])
 		ctx = context.WithValue(ctx, utils.JTIContextKey, claims[string(utils.JTIContextKey)])
+		ctx = context.WithValue(ctx, utils.RefreshJTIContextKey, claims[string(utils.RefreshJTIContextKey)])
+		ctx = context.WithValue(ctx, utils.AccessTokenContextKey, tokenString)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireClientAuth accepts the app tokens issued by the client_credentials
+// grant. User access tokens are rejected.
+func (m *AuthMiddleware) RequireClientAuth(next http.Handler) http.Handler {
+	requireClientAuthLog := authMiddlewareLog("RequireClientAuth")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := bearerToken(w, r)
+		if !ok {
+			return
+		}
+
+		claims, err := m.authService.VerifyClientToken(r.Context(), tokenString)
+
+		if err != nil {
+			requireClientAuthLog.Error().Err(err).Msg("Failed to verify client token")
+			respondWithTokenError(w, err)
+			return
+		}
+
+		scope, _ := claims["scope"].(string)
+
+		ctx := context.WithValue(r.Context(), utils.AppIDContextKey, claims[string(utils.AppIDContextKey)])
+		ctx = context.WithValue(ctx, utils.TokenTypeContextKey, claims[string(utils.TokenTypeContextKey)])
+		ctx = context.WithValue(ctx, utils.JTIContextKey, claims[string(utils.JTIContextKey)])
+		ctx = context.WithValue(ctx, utils.ScopesContextKey, strings.Fields(scope))
+		ctx = context.WithValue(ctx, utils.AccessTokenContextKey, tokenString)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequireScope lets through client tokens carrying scope. It has to run
+// after RequireClientAuth.
+func (m *AuthMiddleware) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, err := utils.GetScopesFromContext(r.Context())
+
+			if err != nil || !slices.Contains(scopes, scope) {
+				utils.RespondWithError(w, models.ApiError{
+					StatusCode: http.StatusForbidden,
+					Message:    utils.StringPointer("Token lacks the " + scope + " scope"),
+					Meta: &models.ErrorMeta{
+						Code: models.CodeInsufficientScope,
+					},
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    utils.StringPointer("Authorization header required"),
+		})
+		return "", false
+	}
+
+	// Check Bearer format
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		utils.RespondWithError(w, models.ApiError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    utils.StringPointer("Invalid authorization header format"),
+		})
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+func respondWithTokenError(w http.ResponseWriter, err error) {
+	var statusCode int
+	var message string
+	var errorCode models.ErrorCode
+
+	isInvalidToken := errors.Is(err, authTypes.ErrTokenRevoked) || errors.Is(err, authTypes.ErrMissingRequiredClaim) || errors.Is(err, authTypes.ErrTokenMismatch) || errors.Is(err, authTypes.ErrTokenNotFound) || errors.Is(err, authTypes.ErrTokenBlacklisted) || errors.Is(err, authTypes.ErrInvalidTokenType) || errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, keyring.ErrUnknownKey) || errors.Is(err, keyring.ErrKeyRetired)
+
+	// Check specific error types
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		statusCode = http.StatusUnauthorized
+		message = "Token has expired"
+		errorCode = models.CodeTokenExpired
+	} else if isInvalidToken {
+		statusCode = http.StatusUnauthorized
+		message = "Invalid token"
+		errorCode = models.CodeTokenInvalid
+	} else {
+		statusCode = http.StatusInternalServerError
+		message = "Internal Server Error"
+	}
+
+	utils.RespondWithError(w, models.ApiError{
+		StatusCode: statusCode,
+		Message:    utils.StringPointer(message),
+		Meta: &models.ErrorMeta{
+			Code: errorCode,
+		},
+	})
+}
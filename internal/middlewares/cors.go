@@ -2,49 +2,83 @@ package middlewares
 
 import (
 	"net/http"
-	"strings"
 
+	"github.com/fransiscushermanto/backend/internal/services"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
 	"github.com/rs/cors"
+	"github.com/rs/zerolog"
 )
 
-func NewCorsMiddleware(options ...cors.Options) *cors.Cors {
+func corsMiddlewareLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("middleware", "Cors").Str("method", method).Logger()
+	return &l
+}
+
+// NewCorsMiddleware allows the AllowedOrigins of options for every request,
+// and on top of that the origins of the app the request is made for (see
+// corsAppID).
+func NewCorsMiddleware(appService *services.AppService, options ...cors.Options) *cors.Cors {
 	if len(options) == 0 {
 		return cors.Default()
 	}
 
 	userOptions := options[0]
 
+	allowOrigin := func(origin string) bool {
+		// Check wildcard patterns
+		for _, allowedOrigin := range userOptions.AllowedOrigins {
+			if utils.MatchOrigin(allowedOrigin, origin) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if userOptions.AllowOriginFunc != nil {
+		allowOrigin = userOptions.AllowOriginFunc
+	}
+
 	defaultOptions := cors.Options{
-		AllowOriginFunc: func(origin string) bool {
-			// Check wildcard patterns
-			for _, allowedOrigin := range userOptions.AllowedOrigins {
-				if matchOrigin(allowedOrigin, origin) {
-					return true
-				}
+		AllowOriginVaryRequestFunc: func(r *http.Request, origin string) (bool, []string) {
+			if allowOrigin(origin) {
+				return true, nil
 			}
-			return false
+
+			appID, ok := corsAppID(r, appService)
+			if !ok {
+				return false, nil
+			}
+
+			allowed, err := appService.IsOriginAllowed(r.Context(), appID, origin)
+			if err != nil {
+				corsMiddlewareLog("AllowOrigin").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to check app origins")
+				return false, nil
+			}
+
+			return allowed, []string{apiKeyHeader, appIDHeader}
 		},
 		AllowedMethods: []string{
 			http.MethodGet,
 			http.MethodPost,
 			http.MethodPut,
+			http.MethodPatch,
 			http.MethodDelete,
 			http.MethodOptions,
 		},
-		AllowedHeaders:             []string{"*"},
-		AllowCredentials:           true,
-		Debug:                      userOptions.Debug,
-		AllowOriginVaryRequestFunc: userOptions.AllowOriginVaryRequestFunc,
-		ExposedHeaders:             userOptions.ExposedHeaders,
-		MaxAge:                     userOptions.MaxAge,
-		OptionsPassthrough:         userOptions.OptionsPassthrough,
-		AllowPrivateNetwork:        userOptions.AllowPrivateNetwork,
-		OptionsSuccessStatus:       userOptions.OptionsSuccessStatus,
-		Logger:                     userOptions.Logger,
+		AllowedHeaders:       []string{"*"},
+		AllowCredentials:     true,
+		Debug:                userOptions.Debug,
+		ExposedHeaders:       userOptions.ExposedHeaders,
+		MaxAge:               userOptions.MaxAge,
+		OptionsPassthrough:   userOptions.OptionsPassthrough,
+		AllowPrivateNetwork:  userOptions.AllowPrivateNetwork,
+		OptionsSuccessStatus: userOptions.OptionsSuccessStatus,
+		Logger:               userOptions.Logger,
 	}
 
-	if userOptions.AllowOriginFunc != nil {
-		defaultOptions.AllowOriginFunc = userOptions.AllowOriginFunc
+	if userOptions.AllowOriginVaryRequestFunc != nil {
+		defaultOptions.AllowOriginVaryRequestFunc = userOptions.AllowOriginVaryRequestFunc
 	}
 
 	if len(userOptions.AllowedMethods) > 0 {
@@ -62,42 +96,29 @@ func NewCorsMiddleware(options ...cors.Options) *cors.Cors {
 	return cors.New(defaultOptions)
 }
 
-func matchOrigin(pattern, origin string) bool {
-	if pattern == origin {
-		return true
+// corsAppID works out the app a request is made for from the X-App-Id header,
+// the app_id query param or the API key. Preflight requests carry no header
+// values, so browsers calling an app's endpoints have to pass app_id in the
+// query for the preflight to pass.
+//
+// The app only widens the origins CORS allows; authenticating the request is
+// still up to RequireAppKey and RequireAuth.
+func corsAppID(r *http.Request, appService *services.AppService) (uuid.UUID, bool) {
+	claimedAppID := r.Header.Get(appIDHeader)
+	if claimedAppID == "" {
+		claimedAppID = r.URL.Query().Get("app_id")
 	}
-	if strings.Contains(pattern, "*") {
-		// Handle wildcard matching
-		// Split both pattern and origin by "://" to separate protocol
-		patternParts := strings.SplitN(pattern, "://", 2)
-		originParts := strings.SplitN(origin, "://", 2)
-
-		if len(patternParts) != 2 || len(originParts) != 2 {
-			return false
-		}
-
-		// Protocol must match exactly
-		if patternParts[0] != originParts[0] {
-			return false
-		}
 
-		// Check host part (remove port from origin if present)
-		patternHost := patternParts[1]
-		originHost := originParts[1]
-
-		// Remove port from origin host
-		if portIndex := strings.LastIndex(originHost, ":"); portIndex != -1 {
-			originHost = originHost[:portIndex]
-		}
+	if claimedAppID != "" {
+		appID, err := uuid.Parse(claimedAppID)
+		return appID, err == nil
+	}
 
-		// Simple wildcard matching
-		if strings.HasPrefix(patternHost, "*.") {
-			suffix := patternHost[2:] // Remove "*."
-			if strings.HasSuffix(originHost, "."+suffix) || originHost == suffix {
-				return true
-			}
-		}
+	apiKey := r.Header.Get(apiKeyHeader)
+	if apiKey == "" {
+		return uuid.Nil, false
 	}
 
-	return false
+	appID, err := appService.AppIDForAPIKey(r.Context(), apiKey)
+	return appID, err == nil
 }
@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/ratelimit"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// RateLimitKey picks who a request is counted against, if it can tell.
+type RateLimitKey func(r *http.Request) (string, bool)
+
+type RateLimitMiddleware struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitMiddleware(limiter *ratelimit.Limiter) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+	}
+}
+
+func rateLimitMiddlewareLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("middleware", "RateLimit").Str("method", method).Logger()
+	return &l
+}
+
+// KeyByIP counts the client address; forwarded addresses are only used when
+// they come from a trusted proxy (see RealIP).
+func KeyByIP(r *http.Request) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr, true
+	}
+
+	return "ip:" + host, true
+}
+
+// KeyByAppID needs RequireAppKey or RequireClientAuth to run first.
+func KeyByAppID(r *http.Request) (string, bool) {
+	appID, err := utils.GetAppIDFromContext(r.Context())
+	if err != nil {
+		return "", false
+	}
+
+	return "app:" + appID.String(), true
+}
+
+// KeyByUserID needs RequireAuth to run first.
+func KeyByUserID(r *http.Request) (string, bool) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		return "", false
+	}
+
+	return "user:" + userID.String(), true
+}
+
+// Limit allows limit requests per key to the routes it wraps, counted apart
+// from the other limits by name. Requests the key cannot tell apart are
+// counted per IP. If the counts cannot be reached the request is let
+// through rather than taking the API down with them, unless the limit is
+// set to fail closed.
+func (m *RateLimitMiddleware) Limit(name string, limit ratelimit.Limit, key RateLimitKey) func(http.Handler) http.Handler {
+	limitLog := rateLimitMiddlewareLog("Limit")
+	policy := fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, ok := key(r)
+			if !ok {
+				subject, _ = KeyByIP(r)
+			}
+
+			result, err := m.limiter.Allow(r.Context(), name+":"+subject, limit)
+			if err != nil {
+				limitLog.Error().Err(err).Str("limit", name).Bool("fail_closed", limit.FailClosed).Msg("Failed to check rate limit")
+
+				if limit.FailClosed {
+					utils.RespondWithError(w, models.ApiError{
+						StatusCode: http.StatusServiceUnavailable,
+						Message:    utils.StringPointer(utils.ErrServiceUnavailable.Error()),
+					})
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+
+			w.Header().Set("RateLimit-Policy", policy)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", reset)
+
+			if !result.Allowed {
+				limitLog.Warn().Str("limit", name).Str("subject", subject).Msg("Rate limit exceeded")
+				w.Header().Set("Retry-After", reset)
+				utils.RespondWithError(w, models.ApiError{
+					StatusCode: http.StatusTooManyRequests,
+					Message:    utils.StringPointer(utils.ErrTooManyRequests.Error()),
+					Meta: &models.ErrorMeta{
+						Code: models.CodeRateLimited,
+					},
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP sets r.RemoteAddr to the client address, like chi's
+// middleware.RealIP, but only believes X-Forwarded-For and X-Real-IP when the
+// connection comes from one of the trusted proxies. Anyone else could send
+// the headers themselves and pick the address that login lockouts and rate
+// limits are counted against.
+//
+// X-Forwarded-For is read from the right, skipping trusted proxies, so only
+// the entry added by the outermost trusted hop is used.
+func RealIP(trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	isTrusted := func(ip net.IP) bool {
+		for _, network := range trustedProxies {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(trustedProxies) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+
+			if peer := net.ParseIP(host); peer == nil || !isTrusted(peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if clientIP := forwardedClientIP(r, isTrusted); clientIP != "" {
+				r.RemoteAddr = clientIP
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forwardedClientIP(r *http.Request, isTrusted func(net.IP) bool) string {
+	forwardedFor := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwardedFor[i]))
+		if ip == nil {
+			break
+		}
+
+		if !isTrusted(ip) {
+			return ip.String()
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
@@ -20,8 +20,27 @@ const (
 	CodeTokenExpired ErrorCode = "token_expired"
 	// CodeTokenInvalid is for invalid access tokens (401).
 	CodeTokenInvalid ErrorCode = "token_invalid"
+	// CodeRefreshTokenReused is for refresh tokens presented again after being rotated (401).
+	// The token's whole family has been revoked, so the user has to log in again.
+	CodeRefreshTokenReused ErrorCode = "refresh_token_reused"
+	// CodeDeviceMismatch is for refresh tokens presented by another device than the one they were issued to (401).
+	CodeDeviceMismatch ErrorCode = "device_mismatch"
+	// CodeInvalidAPIKey is for requests without a valid app API key (401).
+	CodeInvalidAPIKey ErrorCode = "invalid_api_key"
 	// CodeUnauthorized is for requests lacking authentication (401).
 	CodeUnauthorized ErrorCode = "unauthorized"
+	// CodeInsufficientScope is for client tokens missing the scope a route requires (403).
+	CodeInsufficientScope ErrorCode = "insufficient_scope"
+	// CodeEmailNotVerified is for logins by users who have not verified their email yet (403).
+	CodeEmailNotVerified ErrorCode = "email_not_verified"
+	// CodeAccountLocked is for logins refused after too many failed attempts, see Retry-After (429).
+	CodeAccountLocked ErrorCode = "account_locked"
+	// CodeRateLimited is for requests over the rate limit of the route, see Retry-After (429).
+	CodeRateLimited ErrorCode = "rate_limited"
+	// CodeRedirectURLNotAllowed is for callback or redirect urls the app has not registered (400).
+	CodeRedirectURLNotAllowed ErrorCode = "redirect_url_not_allowed"
+	// CodeCookieDomainNotAllowed is for cookie domains not covered by the app's registered redirect urls (400).
+	CodeCookieDomainNotAllowed ErrorCode = "cookie_domain_not_allowed"
 )
 
 type ErrorMeta struct {
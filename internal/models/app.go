@@ -7,19 +7,73 @@ import (
 )
 
 type App struct {
-	ID        uuid.UUID `json:"id"`
-	Name      []byte    `json:"name"`
-	CreatedAt time.Time `json:"created_at" time_format:"2006-01-02T15:04:05Z"`
-	UpdatedAt time.Time `json:"updated_at" time_format:"2006-01-02T15:04:05Z"`
+	ID            uuid.UUID `json:"id"`
+	Name          []byte    `json:"name"`
+	GrantedScopes []string  `json:"granted_scopes"`
+	CreatedAt     time.Time `json:"created_at" time_format:"2006-01-02T15:04:05Z"`
+	UpdatedAt     time.Time `json:"updated_at" time_format:"2006-01-02T15:04:05Z"`
 }
 
+// Scopes checked by the management API. Apps are registered with
+// DefaultAppScopes; anything else is granted by an operator (appctl
+// set-scopes) and only passed on to resource servers.
+const (
+	ScopeAppsRead  = "apps:read"
+	ScopeAppsWrite = "apps:write"
+)
+
+var DefaultAppScopes = []string{ScopeAppsRead, ScopeAppsWrite}
+
+// AppApiKeyKind tells where a key may be used. Publishable keys identify
+// the app to the auth endpoints and are visible to browsers; secret keys
+// stay on the app's servers and authenticate it as an OAuth client, which
+// is what the management API requires.
+type AppApiKeyKind string
+
+const (
+	AppApiKeyPublishable AppApiKeyKind = "publishable"
+	AppApiKeySecret      AppApiKeyKind = "secret"
+)
+
+// AppApiKey is stored as the public KeyID embedded in the key and an HMAC
+// of the whole key; the key itself is only shown once.
 type AppApiKey struct {
-	ID         uuid.UUID `json:"id"`
-	AppID      uuid.UUID `json:"app_id"`
-	KeyHash    string    `json:"key_hash"`
-	CreatedAt  time.Time `json:"created_at" time_format:"2006-01-02T15:04:05Z"`
-	IsActive   bool      `json:"is_active"`
-	LastUsedAt string    `json:"last_used_at"`
+	ID         uuid.UUID     `json:"id"`
+	AppID      uuid.UUID     `json:"app_id"`
+	KeyID      string        `json:"key_id"`
+	Kind       AppApiKeyKind `json:"kind"`
+	KeyHash    string        `json:"key_hash"`
+	CreatedAt  time.Time     `json:"created_at" time_format:"2006-01-02T15:04:05Z"`
+	IsActive   bool          `json:"is_active"`
+	RevokedAt  *time.Time    `json:"revoked_at"`
+	LastUsedAt *time.Time    `json:"last_used_at"`
+}
+
+// AppApiKeyResponse describes a key without its hash. A rotated key stays
+// active until RevokedAt when it was given a grace period.
+type AppApiKeyResponse struct {
+	ID         string        `json:"id"`
+	KeyID      string        `json:"key_id"`
+	Kind       AppApiKeyKind `json:"kind"`
+	Active     bool          `json:"active"`
+	CreatedAt  time.Time     `json:"created_at"`
+	LastUsedAt *time.Time    `json:"last_used_at"`
+	RevokedAt  *time.Time    `json:"revoked_at"`
+}
+
+// RotateAppApiKeyRequest rotates the publishable keys unless Kind says
+// otherwise.
+type RotateAppApiKeyRequest struct {
+	Kind               AppApiKeyKind `json:"kind" validate:"omitempty,oneof=publishable secret"`
+	GracePeriodSeconds int           `json:"grace_period_seconds" validate:"min=0"`
+}
+
+type RotateAppApiKeyResponse struct {
+	ID                    string        `json:"id"`
+	KeyID                 string        `json:"key_id"`
+	Kind                  AppApiKeyKind `json:"kind"`
+	APIKey                string        `json:"api_key"`
+	PreviousKeysRevokedAt time.Time     `json:"previous_keys_revoked_at"`
 }
 
 type UpdateAppApiKey struct {
@@ -29,16 +83,94 @@ type UpdateAppApiKey struct {
 }
 
 type RegisterAppRequest struct {
-	Name string `json:"name" validate:"required,min=3,max=100"`
+	Name                     string `json:"name" validate:"required,min=3,max=100"`
+	RequireEmailVerification bool   `json:"require_email_verification"`
 }
 
 type RegisterAppResponse struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	APIKey string `json:"api_key"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	APIKey        string   `json:"api_key"`
+	SecretKey     string   `json:"secret_key"`
+	GrantedScopes []string `json:"granted_scopes"`
 }
 
 type AppResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	GrantedScopes []string `json:"granted_scopes,omitempty"`
+}
+
+type AppDetailResponse struct {
+	ID                       string    `json:"id"`
+	Name                     string    `json:"name"`
+	RequireEmailVerification bool      `json:"require_email_verification"`
+	GrantedScopes            []string  `json:"granted_scopes"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+type UpdateAppRequest struct {
+	Name string `json:"name" validate:"required,min=3,max=100"`
+}
+
+// DeleteAppResponse tells when a deleted app and everything that belongs to
+// it are removed for good.
+type DeleteAppResponse struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// AppAllowedOrigin is a browser origin allowed to call the API for an app,
+// exact or with a leading wildcard label (https://*.example.com).
+type AppAllowedOrigin struct {
+	ID        uuid.UUID `json:"id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Origin    string    `json:"origin"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AllowedOriginRequest struct {
+	Origin string `json:"origin" validate:"required,max=255"`
+}
+
+// AppSettings is an app's security policy. A zero SessionLifetimeSeconds or
+// MaxSessions means no limit. GoogleClientIDs are the OAuth client ids whose
+// Google ID tokens the app accepts; Google sign-in is off while it is empty.
+type AppSettings struct {
+	AppID                    uuid.UUID      `json:"app_id"`
+	AccessTokenTTLSeconds    int            `json:"access_token_ttl_seconds"`
+	RefreshTokenTTLSeconds   int            `json:"refresh_token_ttl_seconds"`
+	SessionLifetimeSeconds   int            `json:"session_lifetime_seconds"`
+	PasswordPolicy           PasswordPolicy `json:"password_policy"`
+	MaxSessions              int            `json:"max_sessions"`
+	RequireEmailVerification bool           `json:"require_email_verification"`
+	GoogleClientIDs          []string       `json:"google_client_ids"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+}
+
+func (s *AppSettings) AccessTokenTTL() time.Duration {
+	return time.Duration(s.AccessTokenTTLSeconds) * time.Second
+}
+
+func (s *AppSettings) RefreshTokenTTL() time.Duration {
+	return time.Duration(s.RefreshTokenTTLSeconds) * time.Second
+}
+
+func (s *AppSettings) SessionLifetime() time.Duration {
+	return time.Duration(s.SessionLifetimeSeconds) * time.Second
+}
+
+// UpdateAppSettingsRequest replaces the whole policy. The refresh token TTL
+// is capped to constants.MAX_REFRESH_TOKEN_EXPIRY.
+type UpdateAppSettingsRequest struct {
+	AccessTokenTTLSeconds    int            `json:"access_token_ttl_seconds" validate:"gte=60,lte=86400"`
+	RefreshTokenTTLSeconds   int            `json:"refresh_token_ttl_seconds" validate:"gte=300,lte=7776000"`
+	SessionLifetimeSeconds   int            `json:"session_lifetime_seconds" validate:"gte=0,lte=31536000"`
+	PasswordPolicy           PasswordPolicy `json:"password_policy"`
+	MaxSessions              int            `json:"max_sessions" validate:"gte=0,lte=100"`
+	RequireEmailVerification bool           `json:"require_email_verification"`
+	GoogleClientIDs          []string       `json:"google_client_ids" validate:"max=20,dive,required,max=255"`
 }
@@ -22,26 +22,36 @@ const (
 	AuthResponseJSON     AuthResponseType = "json"
 )
 
+type BlacklistReason string
+
+const (
+	BlacklistReasonLogout BlacklistReason = "logout"
+	BlacklistReasonRevoke BlacklistReason = "revoke"
+)
+
 type LoginWithEmailRequest struct {
-	Provider AuthProvider `json:"provider" validate:"required,oneof=local"`
-	AppID    uuid.UUID    `json:"app_id" validate:"required"`
-	Email    string       `json:"email" validate:"required,email"`
-	Password string       `json:"password" validate:"required"`
-	DeviceID string       `json:"device_id"`
+	Provider   AuthProvider `json:"provider" validate:"required,oneof=local"`
+	AppID      uuid.UUID    `json:"app_id" validate:"required"`
+	Email      string       `json:"email" validate:"required,email"`
+	Password   string       `json:"password" validate:"required"`
+	DeviceID   string       `json:"device_id" validate:"omitempty,max=255"`
+	DeviceName string       `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type LoginWithPasswordlessRequest struct {
-	Provider AuthProvider `json:"provider" validate:"required,oneof=passwordless"`
-	AppID    uuid.UUID    `json:"app_id" validate:"required"`
-	Email    string       `json:"email" validate:"required,email"`
-	DeviceID string       `json:"device_id"`
+	Provider   AuthProvider `json:"provider" validate:"required,oneof=passwordless"`
+	AppID      uuid.UUID    `json:"app_id" validate:"required"`
+	Email      string       `json:"email" validate:"required,email"`
+	DeviceID   string       `json:"device_id" validate:"omitempty,max=255"`
+	DeviceName string       `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type LoginWithOtherProviderRequest struct {
 	Provider      AuthProvider `json:"provider" validate:"required,oneof=google"`
 	ProviderToken string       `json:"provider_token" validate:"required"`
 	AppID         uuid.UUID    `json:"app_id" validate:"required"`
-	DeviceID      string       `json:"device_id"`
+	DeviceID      string       `json:"device_id" validate:"omitempty,max=255"`
+	DeviceName    string       `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type RegisterRequest struct {
@@ -51,7 +61,8 @@ type RegisterRequest struct {
 	Name          string       `json:"name" validate:"required,min=3,max=100"`
 	Email         string       `json:"email" validate:"required,email"`
 	Password      string       `json:"password" validate:"required_if=Provider local,omitempty,password-pattern"`
-	DeviceID      string       `json:"device_id"`
+	DeviceID      string       `json:"device_id" validate:"omitempty,max=255"`
+	DeviceName    string       `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type ForgetPasswordRequest struct {
@@ -59,23 +70,79 @@ type ForgetPasswordRequest struct {
 	Email *string    `json:"email" validate:"required,email"`
 }
 
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type VerifyEmailRequest struct {
+	AppID *uuid.UUID `json:"app_id" validate:"required"`
+	Email *string    `json:"email" validate:"required,email"`
+}
+
+type ConfirmEmailVerificationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type LoginResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in,omitempty"`
+	RefreshExpiresIn int    `json:"refresh_expires_in,omitempty"`
+	DeviceID         string `json:"device_id,omitempty"`
 
 	CallbackURL string `json:"callback_url,omitempty"`
 	RedirectURL string `json:"redirect_url,omitempty"`
 }
 
 type RegisterResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in,omitempty"`
+	RefreshExpiresIn int    `json:"refresh_expires_in,omitempty"`
+	DeviceID         string `json:"device_id,omitempty"`
 
 	CallbackURL string `json:"callback_url,omitempty"`
 	RedirectURL string `json:"redirect_url,omitempty"`
 }
 
+// RefreshToken is one link of a token family: every token rotated from the
+// same login shares the FamilyID of the first, and ParentJTI points at the
+// token it was rotated from. RotatedAt is set once the token has been used.
+// A family belongs to one device, and only that device may rotate it.
 type RefreshToken struct {
+	JTI        string     `json:"jti"`
+	UserID     uuid.UUID  `json:"user_id"`
+	AppID      uuid.UUID  `json:"app_id"`
+	Token      string     `json:"token"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	IsActive   bool       `json:"is_active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FamilyID   string     `json:"family_id"`
+	ParentJTI  *string    `json:"parent_jti"`
+	RotatedAt  *time.Time `json:"rotated_at"`
+	DeviceID   string     `json:"device_id"`
+	DeviceName *string    `json:"device_name"`
+	IPAddress  *string    `json:"ip_address"`
+	UserAgent  *string    `json:"user_agent"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+}
+
+// Session is a signed-in device as shown to its user. ID is the JTI of the
+// session's active refresh token, so it changes on every refresh.
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceID   string    `json:"device_id"`
+	DeviceName *string   `json:"device_name"`
+	IPAddress  *string   `json:"ip_address"`
+	UserAgent  *string   `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type ResetPasswordToken struct {
 	JTI       string    `json:"jti"`
 	UserID    uuid.UUID `json:"user_id"`
 	AppID     uuid.UUID `json:"app_id"`
@@ -85,7 +152,24 @@ type RefreshToken struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type ResetPasswordToken struct {
+type BlacklistToken struct {
+	JTI       string          `json:"jti"`
+	Token     string          `json:"token"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Reason    BlacklistReason `json:"reason"`
+}
+
+type EmailVerificationToken struct {
+	JTI       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MagicLinkToken struct {
 	JTI       string    `json:"jti"`
 	UserID    uuid.UUID `json:"user_id"`
 	AppID     uuid.UUID `json:"app_id"`
@@ -101,6 +185,8 @@ type RefreshTokenRequest struct {
 }
 
 type RefreshTokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
 }
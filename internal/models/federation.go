@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClaimMappings names the ID token claims that hold each user attribute.
+// Empty fields fall back to the standard OIDC claim names.
+type ClaimMappings struct {
+	Subject       string `json:"subject,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified string `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
+
+type IdentityProvider struct {
+	AppID         uuid.UUID     `json:"app_id"`
+	Provider      string        `json:"provider"`
+	Issuer        string        `json:"issuer"`
+	ClientID      string        `json:"client_id"`
+	ClientSecret  []byte        `json:"-"`
+	Scopes        []string      `json:"scopes"`
+	ClaimMappings ClaimMappings `json:"claim_mappings"`
+	// TrustEmail takes the provider's email_verified claim at its word, which
+	// lets its logins be linked to existing accounts by email.
+	TrustEmail bool      `json:"trust_email"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type FederationState struct {
+	State        string           `json:"state"`
+	AppID        uuid.UUID        `json:"app_id"`
+	Provider     string           `json:"provider"`
+	Nonce        string           `json:"nonce"`
+	CodeVerifier string           `json:"code_verifier"`
+	ResponseType AuthResponseType `json:"response_type"`
+	CallbackURL  *string          `json:"callback_url"`
+	RedirectURL  *string          `json:"redirect_url"`
+	SetCookie    bool             `json:"set_cookie"`
+	CookieDomain *string          `json:"cookie_domain"`
+	CreatedAt    time.Time        `json:"created_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+}
+
+// ExternalIdentity is a user identity asserted by a third-party provider.
+type ExternalIdentity struct {
+	Provider      AuthProvider
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type CreateIdentityProviderRequest struct {
+	Provider      string         `json:"provider" validate:"required,min=2,max=50"`
+	Issuer        string         `json:"issuer" validate:"required,url"`
+	ClientID      string         `json:"client_id" validate:"required,max=255"`
+	ClientSecret  string         `json:"client_secret" validate:"required"`
+	Scopes        []string       `json:"scopes"`
+	ClaimMappings *ClaimMappings `json:"claim_mappings"`
+	TrustEmail    bool           `json:"trust_email"`
+}
+
+type IdentityProviderResponse struct {
+	Provider      string        `json:"provider"`
+	Issuer        string        `json:"issuer"`
+	ClientID      string        `json:"client_id"`
+	Scopes        []string      `json:"scopes"`
+	ClaimMappings ClaimMappings `json:"claim_mappings"`
+	TrustEmail    bool          `json:"trust_email"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+func (p *IdentityProvider) ToResponse() *IdentityProviderResponse {
+	return &IdentityProviderResponse{
+		Provider:      p.Provider,
+		Issuer:        p.Issuer,
+		ClientID:      p.ClientID,
+		Scopes:        p.Scopes,
+		ClaimMappings: p.ClaimMappings,
+		TrustEmail:    p.TrustEmail,
+		CreatedAt:     p.CreatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OAuthGrantType string
+
+const (
+	GrantTypeAuthorizationCode OAuthGrantType = "authorization_code"
+	GrantTypeRefreshToken      OAuthGrantType = "refresh_token"
+	GrantTypeClientCredentials OAuthGrantType = "client_credentials"
+)
+
+const CodeChallengeMethodS256 = "S256"
+
+// AppRedirectURI is a uri an app may send users back to: the redirect_uri of
+// /oauth/authorize (exact match only), and the callback_url and redirect_url
+// of login, register and federation, which may also fall under a wildcard
+// origin entry (https://*.example.com).
+type AppRedirectURI struct {
+	ID          uuid.UUID `json:"id"`
+	AppID       uuid.UUID `json:"app_id"`
+	RedirectURI string    `json:"redirect_uri"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AuthorizationCode struct {
+	CodeHash            string     `json:"code_hash"`
+	AppID               uuid.UUID  `json:"app_id"`
+	UserID              uuid.UUID  `json:"user_id"`
+	RedirectURI         string     `json:"redirect_uri"`
+	CodeChallenge       string     `json:"code_challenge"`
+	CodeChallengeMethod string     `json:"code_challenge_method"`
+	CreatedAt           time.Time  `json:"created_at"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	UsedAt              *time.Time `json:"used_at"`
+}
+
+type CreateRedirectURIRequest struct {
+	RedirectURI string `json:"redirect_uri" validate:"required,url,max=2048"`
+}
+
+// AuthorizeRequest holds the OAuth2 parameters of /oauth/authorize. They are
+// read from the query string; the user's credentials travel in the body.
+type AuthorizeRequest struct {
+	ResponseType        string    `json:"response_type" validate:"required"`
+	ClientID            uuid.UUID `json:"client_id" validate:"required"`
+	RedirectURI         string    `json:"redirect_uri" validate:"required"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	State               string    `json:"state"`
+}
+
+type AuthorizeResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// TokenRequest holds the form parameters of /oauth/token (RFC 6749 §4.1.3, §4.4, §6).
+type TokenRequest struct {
+	GrantType    OAuthGrantType
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	// DeviceID is an extension parameter binding the session to a device.
+	// Without it the authorization_code grant picks one, which is returned
+	// and has to be sent with every refresh_token grant.
+	DeviceID string
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	DeviceID     string `json:"device_id,omitempty"`
+}
+
+// IntrospectRequest holds the form parameters of /oauth/introspect (RFC 7662 §2.1).
+type IntrospectRequest struct {
+	Token         string
+	TokenTypeHint string
+	ClientID      string
+	ClientSecret  string
+}
+
+// IntrospectResponse follows RFC 7662 §2.2. Only active is set for tokens
+// that are not active.
+type IntrospectResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	AppID     string `json:"app_id,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+}
+
+// RevokeRequest holds the form parameters of /oauth/revoke (RFC 7009 §2.1).
+type RevokeRequest struct {
+	Token         string
+	TokenTypeHint string
+	ClientID      string
+	ClientSecret  string
+}
+
+// OAuthError is the error body defined by RFC 6749 §5.2. The OAuth endpoints
+// answer with it instead of ApiError so standard client libraries can parse it.
+type OAuthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OpenIDConfiguration is the discovery document served at
+// /.well-known/openid-configuration (OpenID Connect Discovery 1.0, RFC 8414).
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
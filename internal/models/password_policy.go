@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the set of rules an app's passwords have to follow.
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length" validate:"gte=6,lte=72"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+}
+
+// DefaultPasswordPolicy applies where no app is known and to apps without
+// settings.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:        8,
+	RequireUppercase: true,
+	RequireDigit:     true,
+	RequireSymbol:    true,
+}
+
+// Violation describes how password breaks the policy, e.g. "must be at least
+// 8 characters long.", or returns "" when it follows it.
+func (p PasswordPolicy) Violation(password string) string {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Sprintf("must be at least %d characters long.", p.MinLength)
+	}
+
+	rules := []struct {
+		required bool
+		matches  func(rune) bool
+		name     string
+	}{
+		{p.RequireUppercase, unicode.IsUpper, "one uppercase letter"},
+		{p.RequireLowercase, unicode.IsLower, "one lowercase letter"},
+		{p.RequireDigit, unicode.IsDigit, "one digit"},
+		{p.RequireSymbol, isPasswordSymbol, "one special character"},
+	}
+
+	var required []string
+	valid := true
+
+	for _, rule := range rules {
+		if !rule.required {
+			continue
+		}
+
+		required = append(required, rule.name)
+		if !strings.ContainsFunc(password, rule.matches) {
+			valid = false
+		}
+	}
+
+	if valid {
+		return ""
+	}
+
+	if len(required) > 1 {
+		required[len(required)-1] = "and " + required[len(required)-1]
+	}
+
+	separator := ", "
+	if len(required) == 2 {
+		separator = " "
+	}
+
+	return fmt.Sprintf("must contain at least %s.", strings.Join(required, separator))
+}
+
+func isPasswordSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,33 @@
+package models
+
+type SecurityEventType string
+
+const (
+	// SecurityEventRefreshTokenReused is a refresh token presented again after
+	// it was rotated, i.e. a likely stolen token.
+	SecurityEventRefreshTokenReused SecurityEventType = "refresh_token_reused"
+	// SecurityEventRefreshTokenDeviceMismatch is a refresh token presented by
+	// a device other than the one it was issued to.
+	SecurityEventRefreshTokenDeviceMismatch SecurityEventType = "refresh_token_device_mismatch"
+	// SecurityEventLoginLocked is an email or IP locked out after too many
+	// failed logins, i.e. a likely password guessing attack.
+	SecurityEventLoginLocked SecurityEventType = "login_locked"
+)
+
+// LoginSubjectType is what failed logins are counted against.
+type LoginSubjectType string
+
+const (
+	LoginSubjectEmail LoginSubjectType = "email"
+	LoginSubjectIP    LoginSubjectType = "ip"
+)
+
+// UnlockLoginRequest lifts the lockout of an email, an IP or both.
+type UnlockLoginRequest struct {
+	Email     string `json:"email" validate:"required_without=IPAddress,omitempty,email"`
+	IPAddress string `json:"ip_address" validate:"required_without=Email,omitempty,ip"`
+}
+
+type UnlockLoginResponse struct {
+	Unlocked bool `json:"unlocked"`
+}
@@ -34,6 +34,10 @@ type CreateUserRequest struct {
 	Name          string       `json:"name" validate:"required,min=3,max=100"`
 	Email         string       `json:"email" validate:"required,email"`
 	Password      string       `json:"password" validate:"required_if=Provider local,omitempty,password-pattern"`
+
+	// Populated by the auth service once the provider token is verified.
+	ProviderUserID  *string `json:"-"`
+	IsEmailVerified bool    `json:"-"`
 }
 
 type UpdateUserRequest struct {
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+// Values of config.AppConfig.RateLimitStore.
+const (
+	StoreMemory = "memory"
+	StoreDB     = "db"
+)
+
+// Store counts the hits of every key per fixed window. Hit counts one more
+// hit in the window starting at windowStart and returns it with the count of
+// the window before. Purge forgets the windows that ended before before.
+type Store interface {
+	Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (current int, previous int, err error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Limit allows Requests per key within any Window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+	// FailClosed refuses requests when the store cannot be reached, instead
+	// of letting them through.
+	FailClosed bool
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the window the key is counted in ends.
+	Reset time.Duration
+}
+
+// Limiter approximates a sliding window from the counts of the current and
+// the previous fixed window: the previous one is weighed by how much of it
+// the sliding window still covers. Refused requests are counted too, so a
+// client hammering away stays refused.
+type Limiter struct {
+	store Store
+}
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("component", "RateLimiter").Str("method", method).Logger()
+	return &l
+}
+
+func New(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := time.Now()
+	windowStart := now.Truncate(limit.Window)
+
+	current, previous, err := l.store.Hit(ctx, key, windowStart, limit.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := now.Sub(windowStart)
+	previousWeight := 1 - float64(elapsed)/float64(limit.Window)
+	hits := current + int(float64(previous)*previousWeight)
+
+	return &Result{
+		Allowed:   hits <= limit.Requests,
+		Limit:     limit.Requests,
+		Remaining: max(limit.Requests-hits, 0),
+		Reset:     limit.Window - elapsed,
+	}, nil
+}
+
+// Watch forgets windows no limit can look back to every interval until ctx
+// is done. maxWindow is the longest window of any limit.
+func (l *Limiter) Watch(ctx context.Context, interval, maxWindow time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := l.store.Purge(ctx, time.Now().Add(-2*maxWindow)); err != nil {
+				log("Watch").Error().Err(err).Msg("Failed to purge rate limit windows")
+			}
+		}
+	}
+}
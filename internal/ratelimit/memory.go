@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryCounter struct {
+	windowStart time.Time
+	current     int
+	previous    int
+}
+
+// MemoryStore keeps the counts in the process, so every replica enforces
+// its own limits. Use the db store to share them.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]*memoryCounter)}
+}
+
+func (s *MemoryStore) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = &memoryCounter{windowStart: windowStart}
+		s.counters[key] = counter
+	}
+
+	if !counter.windowStart.Equal(windowStart) {
+		if counter.windowStart.Add(window).Equal(windowStart) {
+			counter.previous = counter.current
+		} else {
+			counter.previous = 0
+		}
+
+		counter.windowStart = windowStart
+		counter.current = 0
+	}
+
+	counter.current++
+
+	return counter.current, counter.previous, nil
+}
+
+func (s *MemoryStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+
+	for key, counter := range s.counters {
+		if counter.windowStart.Before(before) {
+			delete(s.counters, key)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
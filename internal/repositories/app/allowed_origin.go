@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	appTypes "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func (r *AppRepository) StoreAllowedOrigin(ctx context.Context, allowedOrigin *models.AppAllowedOrigin) error {
+	err := r.queries.StoreAllowedOrigin(ctx, db.StoreAllowedOriginParams{
+		ID:     allowedOrigin.ID,
+		AppID:  allowedOrigin.AppID,
+		Origin: allowedOrigin.Origin,
+	})
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return appTypes.ErrOriginExists
+		}
+
+		appLog("StoreAllowedOrigin").Error().Err(err).Str("app_id", allowedOrigin.AppID.String()).Msg("Failed to insert allowed origin into DB")
+		return fmt.Errorf("failed to insert allowed origin: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AppRepository) GetAllowedOriginsByAppID(ctx context.Context, appID uuid.UUID) ([]*models.AppAllowedOrigin, error) {
+	dbAllowedOrigins, err := r.queries.GetAllowedOriginsByAppID(ctx, appID)
+	if err != nil {
+		appLog("GetAllowedOriginsByAppID").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query allowed origins")
+		return nil, fmt.Errorf("failed to get allowed origins: %w", err)
+	}
+
+	allowedOrigins := make([]*models.AppAllowedOrigin, len(dbAllowedOrigins))
+
+	for i, dbAllowedOrigin := range dbAllowedOrigins {
+		allowedOrigins[i] = &models.AppAllowedOrigin{
+			ID:        dbAllowedOrigin.ID,
+			AppID:     dbAllowedOrigin.AppID,
+			Origin:    dbAllowedOrigin.Origin,
+			CreatedAt: dbAllowedOrigin.CreatedAt,
+			UpdatedAt: dbAllowedOrigin.UpdatedAt,
+		}
+	}
+
+	return allowedOrigins, nil
+}
+
+func (r *AppRepository) DeleteAllowedOrigin(ctx context.Context, appID, id uuid.UUID) (bool, error) {
+	deleted, err := r.queries.DeleteAllowedOrigin(ctx, db.DeleteAllowedOriginParams{
+		AppID: appID,
+		ID:    id,
+	})
+
+	if err != nil {
+		appLog("DeleteAllowedOrigin").Error().Err(err).Str("app_id", appID.String()).Str("id", id.String()).Msg("Failed to delete allowed origin")
+		return false, fmt.Errorf("failed to delete allowed origin: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
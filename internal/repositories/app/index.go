@@ -45,28 +45,38 @@ func appLog(method string) *zerolog.Logger {
 	return &l
 }
 
-func (r *AppRepository) RegisterApp(ctx context.Context, app *models.App, appApiKey *models.AppApiKey) error {
+func (r *AppRepository) RegisterApp(ctx context.Context, app *models.App, appApiKeys []*models.AppApiKey, settings *models.AppSettings) error {
 	log := appLog("RegisterApp")
 
 	txFn := func(tx pgx.Tx) error {
 		qtx := r.queries.WithTx(tx)
 
 		if err := qtx.StoreApp(ctx, db.StoreAppParams{
-			ID:   app.ID,
-			Name: app.Name,
+			ID:            app.ID,
+			Name:          app.Name,
+			GrantedScopes: app.GrantedScopes,
 		}); err != nil {
 			log.Error().Err(err).Msg("Failed to insert app into DB")
 			return fmt.Errorf("failed to insert app: %w", err)
 		}
 
-		if err := qtx.StoreAppApiKey(ctx, db.StoreAppApiKeyParams{
-			ID:       appApiKey.ID,
-			AppID:    appApiKey.AppID,
-			KeyHash:  appApiKey.KeyHash,
-			IsActive: appApiKey.IsActive,
-		}); err != nil {
-			log.Error().Err(err).Msg("Failed to insert app api key into DB")
-			return fmt.Errorf("failed to create app api key: %w", err)
+		for _, appApiKey := range appApiKeys {
+			if err := qtx.StoreAppApiKey(ctx, db.StoreAppApiKeyParams{
+				ID:       appApiKey.ID,
+				AppID:    appApiKey.AppID,
+				KeyID:    &appApiKey.KeyID,
+				KeyHash:  appApiKey.KeyHash,
+				IsActive: appApiKey.IsActive,
+				Kind:     string(appApiKey.Kind),
+			}); err != nil {
+				log.Error().Err(err).Msg("Failed to insert app api key into DB")
+				return fmt.Errorf("failed to create app api key: %w", err)
+			}
+		}
+
+		if err := upsertAppSettings(ctx, qtx, settings); err != nil {
+			log.Error().Err(err).Msg("Failed to insert app settings into DB")
+			return fmt.Errorf("failed to create app settings: %w", err)
 		}
 		return nil
 	}
@@ -105,8 +115,9 @@ func (r *AppRepository) RegenerateAppApiKey(ctx context.Context, revokedAt time.
 
 			// Use the original `ctx` for subsequent operations as the lock is already acquired.
 			revokedAppApiKeys, err := qtx.RevokeActiveAppApiKeys(ctx, db.RevokeActiveAppApiKeysParams{
-				AppID:     appApiKey.AppID,
 				RevokedAt: utils.ToPgTimestamp(revokedAt),
+				AppID:     appApiKey.AppID,
+				Kind:      utils.StringPointer(string(appApiKey.Kind)),
 			})
 
 			if err != nil {
@@ -114,13 +125,15 @@ func (r *AppRepository) RegenerateAppApiKey(ctx context.Context, revokedAt time.
 				return fmt.Errorf("failed to revoke old token: %w", err)
 			}
 
-			log.Info().Int64("revoked_keys", revokedAppApiKeys).Msg("Revoked active keys")
+			log.Info().Int64("revoked_keys", revokedAppApiKeys).Str("kind", string(appApiKey.Kind)).Msg("Revoked active keys")
 
 			err = qtx.StoreAppApiKey(ctx, db.StoreAppApiKeyParams{
 				ID:       appApiKey.ID,
 				AppID:    appApiKey.AppID,
+				KeyID:    &appApiKey.KeyID,
 				KeyHash:  appApiKey.KeyHash,
 				IsActive: appApiKey.IsActive,
+				Kind:     string(appApiKey.Kind),
 			})
 
 			if err != nil {
@@ -197,10 +210,11 @@ func (r *AppRepository) GetAllApps(ctx context.Context) ([]*models.App, error) {
 func (r *AppRepository) GetAppById(ctx context.Context, id uuid.UUID) (*models.App, error) {
 	result, err := r.queries.GetAppByID(ctx, id)
 	app := &models.App{
-		ID:        result.ID,
-		Name:      result.Name,
-		CreatedAt: result.CreatedAt,
-		UpdatedAt: result.UpdatedAt,
+		ID:            result.ID,
+		Name:          result.Name,
+		GrantedScopes: result.GrantedScopes,
+		CreatedAt:     result.CreatedAt,
+		UpdatedAt:     result.UpdatedAt,
 	}
 
 	if err != nil {
@@ -215,6 +229,180 @@ func (r *AppRepository) GetAppById(ctx context.Context, id uuid.UUID) (*models.A
 	return app, nil
 }
 
+func (r *AppRepository) UpdateAppName(ctx context.Context, id uuid.UUID, name []byte) (bool, error) {
+	updated, err := r.queries.UpdateAppName(ctx, db.UpdateAppNameParams{
+		ID:   id,
+		Name: name,
+	})
+
+	if err != nil {
+		appLog("UpdateAppName").Error().Err(err).Str("app_id", id.String()).Msg("Failed to update app name")
+		return false, fmt.Errorf("failed to update app name: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+func (r *AppRepository) UpdateAppGrantedScopes(ctx context.Context, id uuid.UUID, grantedScopes []string) (bool, error) {
+	updated, err := r.queries.UpdateAppGrantedScopes(ctx, db.UpdateAppGrantedScopesParams{
+		ID:            id,
+		GrantedScopes: grantedScopes,
+	})
+
+	if err != nil {
+		appLog("UpdateAppGrantedScopes").Error().Err(err).Str("app_id", id.String()).Msg("Failed to update app granted scopes")
+		return false, fmt.Errorf("failed to update app granted scopes: %w", err)
+	}
+
+	return updated > 0, nil
+}
+
+// SoftDeleteApp marks the app deleted and signs everything out of it: its
+// API keys are revoked and its users' sessions ended. The rows themselves
+// stay until PurgeDeletedApps.
+func (r *AppRepository) SoftDeleteApp(ctx context.Context, id uuid.UUID, deletedAt time.Time) (bool, error) {
+	log := appLog("SoftDeleteApp")
+
+	deleted := false
+
+	txFn := func(tx pgx.Tx) error {
+		qtx := r.queries.WithTx(tx)
+
+		deletedApps, err := qtx.SoftDeleteApp(ctx, db.SoftDeleteAppParams{
+			ID:        id,
+			DeletedAt: utils.ToPgTimestamp(deletedAt),
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("app_id", id.String()).Msg("Failed to mark app deleted")
+			return fmt.Errorf("failed to delete app: %w", err)
+		}
+
+		if deletedApps == 0 {
+			return nil
+		}
+
+		if _, err := qtx.RevokeActiveAppApiKeys(ctx, db.RevokeActiveAppApiKeysParams{
+			RevokedAt: utils.ToPgTimestamp(deletedAt),
+			AppID:     id,
+		}); err != nil {
+			log.Error().Err(err).Str("app_id", id.String()).Msg("Failed to revoke app api keys")
+			return fmt.Errorf("failed to revoke app api keys: %w", err)
+		}
+
+		if err := qtx.RevokeAppRefreshTokens(ctx, id); err != nil {
+			log.Error().Err(err).Str("app_id", id.String()).Msg("Failed to revoke app refresh tokens")
+			return fmt.Errorf("failed to revoke app refresh tokens: %w", err)
+		}
+
+		deleted = true
+		return nil
+	}
+
+	if err := r.db.WithTransaction(ctx, txFn); err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// PurgeDeletedApps removes apps deleted before deletedBefore, together with
+// everything that cascades from them.
+func (r *AppRepository) PurgeDeletedApps(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	purged, err := r.queries.PurgeDeletedApps(ctx, utils.ToPgTimestamp(deletedBefore))
+	if err != nil {
+		appLog("PurgeDeletedApps").Error().Err(err).Msg("Failed to purge deleted apps")
+		return 0, fmt.Errorf("failed to purge deleted apps: %w", err)
+	}
+
+	return purged, nil
+}
+
+func (r *AppRepository) GetActiveAppApiKeyByKeyID(ctx context.Context, keyID string) (*models.AppApiKey, error) {
+	log := appLog("GetActiveAppApiKeyByKeyID")
+
+	dbApiKey, err := r.queries.GetActiveAppApiKeyByKeyID(ctx, &keyID)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("key_id", keyID).Msg("Failed to query active app api key")
+		return nil, fmt.Errorf("failed to get active app api key: %w", err)
+	}
+
+	return &models.AppApiKey{
+		ID:        dbApiKey.ID,
+		AppID:     dbApiKey.AppID,
+		KeyID:     keyID,
+		Kind:      models.AppApiKeyKind(dbApiKey.Kind),
+		KeyHash:   dbApiKey.KeyHash,
+		CreatedAt: dbApiKey.CreatedAt,
+		IsActive:  dbApiKey.IsActive,
+		RevokedAt: utils.FromPgTimestampPtr(dbApiKey.RevokedAt),
+	}, nil
+}
+
+func (r *AppRepository) GetActiveLegacyAppApiKeys(ctx context.Context, appID uuid.UUID) ([]*models.AppApiKey, error) {
+	dbApiKeys, err := r.queries.GetActiveLegacyAppApiKeys(ctx, appID)
+	if err != nil {
+		appLog("GetActiveLegacyAppApiKeys").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query legacy app api keys")
+		return nil, fmt.Errorf("failed to get legacy app api keys: %w", err)
+	}
+
+	apiKeys := make([]*models.AppApiKey, len(dbApiKeys))
+	for i, dbApiKey := range dbApiKeys {
+		apiKeys[i] = &models.AppApiKey{
+			ID:        dbApiKey.ID,
+			AppID:     dbApiKey.AppID,
+			Kind:      models.AppApiKeyKind(dbApiKey.Kind),
+			KeyHash:   dbApiKey.KeyHash,
+			CreatedAt: dbApiKey.CreatedAt,
+			IsActive:  dbApiKey.IsActive,
+			RevokedAt: utils.FromPgTimestampPtr(dbApiKey.RevokedAt),
+		}
+	}
+
+	return apiKeys, nil
+}
+
+func (r *AppRepository) GetAppApiKeys(ctx context.Context, appID uuid.UUID) ([]*models.AppApiKey, error) {
+	dbApiKeys, err := r.queries.GetAppApiKeys(ctx, appID)
+	if err != nil {
+		appLog("GetAppApiKeys").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query app api keys")
+		return nil, fmt.Errorf("failed to get app api keys: %w", err)
+	}
+
+	apiKeys := make([]*models.AppApiKey, len(dbApiKeys))
+	for i, dbApiKey := range dbApiKeys {
+		apiKeys[i] = &models.AppApiKey{
+			ID:         dbApiKey.ID,
+			AppID:      appID,
+			Kind:       models.AppApiKeyKind(dbApiKey.Kind),
+			CreatedAt:  dbApiKey.CreatedAt,
+			IsActive:   dbApiKey.IsActive,
+			RevokedAt:  utils.FromPgTimestampPtr(dbApiKey.RevokedAt),
+			LastUsedAt: utils.FromPgTimestampPtr(dbApiKey.LastUsedAt),
+		}
+
+		if dbApiKey.KeyID != nil {
+			apiKeys[i].KeyID = *dbApiKey.KeyID
+		}
+	}
+
+	return apiKeys, nil
+}
+
+func (r *AppRepository) TouchAppApiKey(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.TouchAppApiKey(ctx, id); err != nil {
+		appLog("TouchAppApiKey").Error().Err(err).Str("id", id.String()).Msg("Failed to update app api key last_used_at")
+		return fmt.Errorf("failed to touch app api key: %w", err)
+	}
+
+	return nil
+}
+
 func isLockTimeoutError(err error) bool {
 	if err == nil {
 		return false
@@ -1,21 +1,106 @@
 -- name: GetAppByID :one
-SELECT id, name, created_at, updated_at FROM core.apps WHERE id = $1;
+SELECT id, name, created_at, updated_at, granted_scopes FROM core.apps WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetAllApps :many
-SELECT id, name FROM core.apps ORDER BY created_at DESC;
+SELECT id, name FROM core.apps WHERE deleted_at IS NULL ORDER BY created_at DESC;
 
 -- name: StoreApp :exec
-INSERT INTO core.apps (id, name) 
-VALUES ($1, $2);
+INSERT INTO core.apps (id, name, granted_scopes) 
+VALUES ($1, $2, $3);
+
+-- name: UpdateAppName :execrows
+UPDATE core.apps
+SET name = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateAppGrantedScopes :execrows
+UPDATE core.apps
+SET granted_scopes = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteApp :execrows
+UPDATE core.apps
+SET deleted_at = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RevokeAppRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND is_active = true;
+
+-- name: PurgeDeletedApps :execrows
+DELETE FROM core.apps
+WHERE deleted_at IS NOT NULL AND deleted_at < $1;
 
 -- name: StoreAppApiKey :exec
-INSERT INTO core.app_api_keys (id, app_id, key_hash, is_active) 
-VALUES ($1, $2, $3, $4);
+INSERT INTO core.app_api_keys (id, app_id, key_id, key_hash, is_active, kind) 
+VALUES ($1, $2, $3, $4, $5, $6);
 
 -- name: RevokeActiveAppApiKeys :execrows
 UPDATE core.app_api_keys 
-SET is_active = false, revoked_at = $2, updated_at = now() 
-WHERE app_id = $1 AND is_active = true;
+SET revoked_at = sqlc.arg(revoked_at), is_active = sqlc.arg(revoked_at) > now(), updated_at = now() 
+WHERE app_id = sqlc.arg(app_id) AND is_active = true AND (revoked_at IS NULL OR revoked_at > sqlc.arg(revoked_at))
+    AND (sqlc.narg(kind)::VARCHAR IS NULL OR kind = sqlc.narg(kind));
 
 -- name: LockAppForUpdate :one
-SELECT id FROM core.apps WHERE id = $1 FOR UPDATE;
\ No newline at end of file
+SELECT id FROM core.apps WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+
+-- name: GetActiveAppApiKeyByKeyID :one
+SELECT id, app_id, key_hash, created_at, is_active, revoked_at, last_used_at, updated_at, key_id, kind
+FROM core.app_api_keys
+WHERE key_id = $1 AND is_active = true AND (revoked_at IS NULL OR revoked_at > now());
+
+-- name: GetActiveLegacyAppApiKeys :many
+SELECT id, app_id, key_hash, created_at, is_active, revoked_at, last_used_at, updated_at, key_id, kind
+FROM core.app_api_keys
+WHERE app_id = $1 AND key_id IS NULL AND is_active = true AND (revoked_at IS NULL OR revoked_at > now());
+
+-- name: GetAppApiKeys :many
+SELECT id, key_id, kind, created_at, is_active, revoked_at, last_used_at
+FROM core.app_api_keys
+WHERE app_id = $1
+ORDER BY created_at DESC;
+
+-- name: TouchAppApiKey :exec
+UPDATE core.app_api_keys
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: StoreAllowedOrigin :exec
+INSERT INTO core.app_allowed_origins (id, app_id, origin)
+VALUES ($1, $2, $3);
+
+-- name: GetAllowedOriginsByAppID :many
+SELECT * FROM core.app_allowed_origins
+WHERE app_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteAllowedOrigin :execrows
+DELETE FROM core.app_allowed_origins
+WHERE app_id = $1 AND id = $2;
+
+-- name: GetAppSettings :one
+SELECT * FROM core.app_settings
+WHERE app_id = $1;
+
+-- name: UpsertAppSettings :exec
+INSERT INTO core.app_settings (
+    app_id, access_token_ttl_seconds, refresh_token_ttl_seconds, session_lifetime_seconds,
+    password_min_length, password_require_uppercase, password_require_lowercase,
+    password_require_digit, password_require_symbol, max_sessions, require_email_verification,
+    google_client_ids
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (app_id) DO UPDATE
+SET access_token_ttl_seconds = EXCLUDED.access_token_ttl_seconds,
+    refresh_token_ttl_seconds = EXCLUDED.refresh_token_ttl_seconds,
+    session_lifetime_seconds = EXCLUDED.session_lifetime_seconds,
+    password_min_length = EXCLUDED.password_min_length,
+    password_require_uppercase = EXCLUDED.password_require_uppercase,
+    password_require_lowercase = EXCLUDED.password_require_lowercase,
+    password_require_digit = EXCLUDED.password_require_digit,
+    password_require_symbol = EXCLUDED.password_require_symbol,
+    max_sessions = EXCLUDED.max_sessions,
+    require_email_verification = EXCLUDED.require_email_verification,
+    google_client_ids = EXCLUDED.google_client_ids,
+    updated_at = now();
\ No newline at end of file
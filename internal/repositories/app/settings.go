@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *AppRepository) GetAppSettings(ctx context.Context, appID uuid.UUID) (*models.AppSettings, error) {
+	dbSettings, err := r.queries.GetAppSettings(ctx, appID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		appLog("GetAppSettings").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query app settings")
+		return nil, fmt.Errorf("failed to get app settings: %w", err)
+	}
+
+	return &models.AppSettings{
+		AppID:                  dbSettings.AppID,
+		AccessTokenTTLSeconds:  int(dbSettings.AccessTokenTtlSeconds),
+		RefreshTokenTTLSeconds: int(dbSettings.RefreshTokenTtlSeconds),
+		SessionLifetimeSeconds: int(dbSettings.SessionLifetimeSeconds),
+		PasswordPolicy: models.PasswordPolicy{
+			MinLength:        int(dbSettings.PasswordMinLength),
+			RequireUppercase: dbSettings.PasswordRequireUppercase,
+			RequireLowercase: dbSettings.PasswordRequireLowercase,
+			RequireDigit:     dbSettings.PasswordRequireDigit,
+			RequireSymbol:    dbSettings.PasswordRequireSymbol,
+		},
+		MaxSessions:              int(dbSettings.MaxSessions),
+		RequireEmailVerification: dbSettings.RequireEmailVerification,
+		GoogleClientIDs:          dbSettings.GoogleClientIds,
+		UpdatedAt:                dbSettings.UpdatedAt,
+	}, nil
+}
+
+func (r *AppRepository) UpsertAppSettings(ctx context.Context, settings *models.AppSettings) error {
+	if err := upsertAppSettings(ctx, r.queries, settings); err != nil {
+		appLog("UpsertAppSettings").Error().Err(err).Str("app_id", settings.AppID.String()).Msg("Failed to upsert app settings")
+		return fmt.Errorf("failed to upsert app settings: %w", err)
+	}
+
+	return nil
+}
+
+func upsertAppSettings(ctx context.Context, queries *db.Queries, settings *models.AppSettings) error {
+	// google_client_ids is NOT NULL, a nil slice would be sent as NULL.
+	googleClientIDs := settings.GoogleClientIDs
+	if googleClientIDs == nil {
+		googleClientIDs = []string{}
+	}
+
+	return queries.UpsertAppSettings(ctx, db.UpsertAppSettingsParams{
+		AppID:                    settings.AppID,
+		AccessTokenTtlSeconds:    int32(settings.AccessTokenTTLSeconds),
+		RefreshTokenTtlSeconds:   int32(settings.RefreshTokenTTLSeconds),
+		SessionLifetimeSeconds:   int32(settings.SessionLifetimeSeconds),
+		PasswordMinLength:        int32(settings.PasswordPolicy.MinLength),
+		PasswordRequireUppercase: settings.PasswordPolicy.RequireUppercase,
+		PasswordRequireLowercase: settings.PasswordPolicy.RequireLowercase,
+		PasswordRequireDigit:     settings.PasswordPolicy.RequireDigit,
+		PasswordRequireSymbol:    settings.PasswordPolicy.RequireSymbol,
+		MaxSessions:              int32(settings.MaxSessions),
+		RequireEmailVerification: settings.RequireEmailVerification,
+		GoogleClientIds:          googleClientIDs,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/repositories/db"
 	"github.com/fransiscushermanto/backend/internal/services"
+	authTypes "github.com/fransiscushermanto/backend/internal/services/auth"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -32,15 +33,26 @@ func authLog(method string) *zerolog.Logger {
 	return &l
 }
 
+func storeRefreshTokenParams(token *models.RefreshToken) db.StoreRefreshTokenParams {
+	return db.StoreRefreshTokenParams{
+		Jti:        token.JTI,
+		UserID:     token.UserID,
+		AppID:      token.AppID,
+		Token:      token.Token,
+		ExpiresAt:  token.ExpiresAt,
+		IsActive:   token.IsActive,
+		FamilyID:   token.FamilyID,
+		ParentJti:  token.ParentJTI,
+		DeviceID:   token.DeviceID,
+		DeviceName: token.DeviceName,
+		IpAddress:  token.IPAddress,
+		UserAgent:  token.UserAgent,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
 func (r *AuthRepository) StoreRefreshToken(ctx context.Context, token *models.RefreshToken) error {
-	err := r.queries.StoreRefreshToken(ctx, db.StoreRefreshTokenParams{
-		Jti:       token.JTI,
-		UserID:    token.UserID,
-		AppID:     token.AppID,
-		Token:     token.Token,
-		ExpiresAt: token.ExpiresAt,
-		IsActive:  token.IsActive,
-	})
+	err := r.queries.StoreRefreshToken(ctx, storeRefreshTokenParams(token))
 
 	log := authLog("StoreRefreshToken")
 
@@ -59,13 +71,18 @@ func (r *AuthRepository) GetRefreshTokenByJTI(ctx context.Context, appID uuid.UU
 	})
 
 	refreshToken := &models.RefreshToken{
-		JTI:       dbRefreshToken.Jti,
-		UserID:    dbRefreshToken.UserID,
-		AppID:     dbRefreshToken.AppID,
-		Token:     dbRefreshToken.Token,
-		ExpiresAt: dbRefreshToken.ExpiresAt,
-		IsActive:  dbRefreshToken.IsActive,
-		CreatedAt: dbRefreshToken.CreatedAt,
+		JTI:        dbRefreshToken.Jti,
+		UserID:     dbRefreshToken.UserID,
+		AppID:      dbRefreshToken.AppID,
+		Token:      dbRefreshToken.Token,
+		ExpiresAt:  dbRefreshToken.ExpiresAt,
+		IsActive:   dbRefreshToken.IsActive,
+		CreatedAt:  dbRefreshToken.CreatedAt,
+		FamilyID:   dbRefreshToken.FamilyID,
+		ParentJTI:  dbRefreshToken.ParentJti,
+		RotatedAt:  utils.FromPgTimestampPtr(dbRefreshToken.RotatedAt),
+		DeviceID:   dbRefreshToken.DeviceID,
+		DeviceName: dbRefreshToken.DeviceName,
 	}
 
 	log := authLog("GetRefreshTokenByJTI")
@@ -108,13 +125,21 @@ func (r *AuthRepository) GetUserActiveRefreshTokens(ctx context.Context, appID u
 	activeRefreshTokens := make([]models.RefreshToken, 0, len(dbTokens))
 	for _, t := range dbTokens {
 		activeRefreshTokens = append(activeRefreshTokens, models.RefreshToken{
-			JTI:       t.Jti,
-			UserID:    t.UserID,
-			AppID:     t.AppID,
-			Token:     t.Token,
-			ExpiresAt: t.ExpiresAt,
-			IsActive:  t.IsActive,
-			CreatedAt: t.CreatedAt,
+			JTI:        t.Jti,
+			UserID:     t.UserID,
+			AppID:      t.AppID,
+			Token:      t.Token,
+			ExpiresAt:  t.ExpiresAt,
+			IsActive:   t.IsActive,
+			CreatedAt:  t.CreatedAt,
+			FamilyID:   t.FamilyID,
+			ParentJTI:  t.ParentJti,
+			RotatedAt:  utils.FromPgTimestampPtr(t.RotatedAt),
+			DeviceID:   t.DeviceID,
+			DeviceName: t.DeviceName,
+			IPAddress:  t.IpAddress,
+			UserAgent:  t.UserAgent,
+			LastUsedAt: t.LastUsedAt,
 		})
 	}
 
@@ -139,6 +164,162 @@ func (r *AuthRepository) RevokeRefreshToken(ctx context.Context, appID, userID u
 	return nil
 }
 
+func (r *AuthRepository) RevokeUserRefreshTokenByJTI(ctx context.Context, appID, userID uuid.UUID, jti string) (bool, error) {
+	log := authLog("RevokeUserRefreshTokenByJTI")
+
+	revokedTokens, err := r.queries.RevokeUserRefreshTokenByJTI(ctx, db.RevokeUserRefreshTokenByJTIParams{
+		AppID:  appID,
+		UserID: userID,
+		Jti:    jti,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("user_id", userID.String()).Str("jti", jti).Msg("Failed to revoke user refresh token")
+		return false, fmt.Errorf("failed to revoke user refresh token: %w", err)
+	}
+
+	return revokedTokens > 0, nil
+}
+
+func (r *AuthRepository) RevokeOtherRefreshTokens(ctx context.Context, appID, userID uuid.UUID, jti string) error {
+	log := authLog("RevokeOtherRefreshTokens")
+
+	err := r.queries.RevokeOtherRefreshTokens(ctx, db.RevokeOtherRefreshTokensParams{
+		AppID:  appID,
+		UserID: userID,
+		Jti:    jti,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("user_id", userID.String()).Str("jti", jti).Msg("Failed to revoke other refresh tokens")
+		return fmt.Errorf("failed to revoke other refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeDeviceRefreshTokens(ctx context.Context, appID, userID uuid.UUID, deviceID string) error {
+	log := authLog("RevokeDeviceRefreshTokens")
+
+	err := r.queries.RevokeDeviceRefreshTokens(ctx, db.RevokeDeviceRefreshTokensParams{
+		AppID:    appID,
+		UserID:   userID,
+		DeviceID: deviceID,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("user_id", userID.String()).Str("device_id", deviceID).Msg("Failed to revoke device refresh tokens")
+		return fmt.Errorf("failed to revoke device refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeRefreshTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) error {
+	log := authLog("RevokeRefreshTokenByJTI")
+
+	err := r.queries.RevokeRefreshTokenByJTI(ctx, db.RevokeRefreshTokenByJTIParams{
+		AppID: appID,
+		Jti:   jti,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("jti", jti).Msg("Failed to revoke refresh token from DB")
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RotateRefreshToken marks parent as rotated and stores its replacement in
+// one transaction. It reports false, storing nothing, when parent was
+// already rotated.
+func (r *AuthRepository) RotateRefreshToken(ctx context.Context, parent, token *models.RefreshToken) (bool, error) {
+	log := authLog("RotateRefreshToken")
+
+	rotated := false
+	txFn := func(tx pgx.Tx) error {
+		qtx := r.queries.WithTx(tx)
+
+		rotatedTokens, err := qtx.RotateRefreshToken(ctx, db.RotateRefreshTokenParams{
+			AppID: parent.AppID,
+			Jti:   parent.JTI,
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("app_id", parent.AppID.String()).Str("jti", parent.JTI).Msg("Failed to rotate refresh token")
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+
+		if rotatedTokens == 0 {
+			return nil
+		}
+
+		if err := qtx.StoreRefreshToken(ctx, storeRefreshTokenParams(token)); err != nil {
+			log.Error().Err(err).Str("jti", token.JTI).Msg("Failed to insert rotated refresh token into DB")
+			return fmt.Errorf("failed to insert refresh token: %w", err)
+		}
+
+		rotated = true
+		return nil
+	}
+
+	if err := r.db.WithTransaction(ctx, txFn); err != nil {
+		return false, err
+	}
+
+	return rotated, nil
+}
+
+func (r *AuthRepository) RevokeRefreshTokenFamily(ctx context.Context, appID uuid.UUID, familyID string) error {
+	log := authLog("RevokeRefreshTokenFamily")
+
+	err := r.queries.RevokeRefreshTokenFamily(ctx, db.RevokeRefreshTokenFamilyParams{
+		AppID:    appID,
+		FamilyID: familyID,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("family_id", familyID).Msg("Failed to revoke refresh token family")
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) BlacklistToken(ctx context.Context, token *models.BlacklistToken) error {
+	log := authLog("BlacklistToken")
+
+	reason := string(token.Reason)
+
+	err := r.queries.StoreBlacklistToken(ctx, db.StoreBlacklistTokenParams{
+		Jti:       token.JTI,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+		Reason:    &reason,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("jti", token.JTI).Msg("Failed to insert blacklist token into DB")
+		return fmt.Errorf("failed to blacklist token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	log := authLog("IsTokenBlacklisted")
+
+	blacklisted, err := r.queries.IsTokenBlacklisted(ctx, jti)
+
+	if err != nil {
+		log.Error().Err(err).Str("jti", jti).Msg("Failed to query blacklist token")
+		return false, fmt.Errorf("failed to check blacklist token: %w", err)
+	}
+
+	return blacklisted, nil
+}
+
 func (r *AuthRepository) StoreResetPasswordToken(ctx context.Context, token *models.ResetPasswordToken) error {
 	log := authLog("StoreResetPasswordToken")
 
@@ -173,3 +354,272 @@ func (r *AuthRepository) RevokeResetPasswordToken(ctx context.Context, appID, us
 
 	return nil
 }
+
+func (r *AuthRepository) GetResetPasswordTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.ResetPasswordToken, error) {
+	log := authLog("GetResetPasswordTokenByJTI")
+
+	dbResetToken, err := r.queries.GetResetPasswordTokenByJTI(ctx, db.GetResetPasswordTokenByJTIParams{
+		AppID: appID,
+		Jti:   jti,
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("app_id", appID.String()).Str("jti", jti).Msg("Failed to query reset password token")
+		return nil, fmt.Errorf("failed to get reset password token: %w", err)
+	}
+
+	return &models.ResetPasswordToken{
+		JTI:       dbResetToken.Jti,
+		UserID:    dbResetToken.UserID,
+		AppID:     dbResetToken.AppID,
+		Token:     dbResetToken.Token,
+		ExpiresAt: dbResetToken.ExpiresAt,
+		IsActive:  dbResetToken.IsActive,
+		CreatedAt: dbResetToken.CreatedAt,
+	}, nil
+}
+
+func (r *AuthRepository) ResetPassword(ctx context.Context, token *models.ResetPasswordToken, hashedPassword string) error {
+	log := authLog("ResetPassword")
+
+	txFn := func(tx pgx.Tx) error {
+		qtx := r.queries.WithTx(tx)
+
+		usedTokens, err := qtx.UseResetPasswordToken(ctx, db.UseResetPasswordTokenParams{
+			AppID: token.AppID,
+			Jti:   token.JTI,
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("jti", token.JTI).Msg("Failed to mark reset password token as used")
+			return fmt.Errorf("failed to use reset password token: %w", err)
+		}
+
+		if usedTokens == 0 {
+			log.Warn().Str("jti", token.JTI).Msg("Reset password token already used or expired")
+			return authTypes.ErrTokenRevoked
+		}
+
+		updatedProviders, err := qtx.UpdateUserAuthProviderPassword(ctx, db.UpdateUserAuthProviderPasswordParams{
+			Password: &hashedPassword,
+			AppID:    token.AppID,
+			UserID:   token.UserID,
+			Provider: string(models.AuthProviderLocal),
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("user_id", token.UserID.String()).Msg("Failed to update user password")
+			return fmt.Errorf("failed to update user password: %w", err)
+		}
+
+		if updatedProviders == 0 {
+			log.Error().Str("user_id", token.UserID.String()).Msg("User has no local authentication provider")
+			return fmt.Errorf("failed to update user password: %w", pgx.ErrNoRows)
+		}
+
+		if err := qtx.RevokeRefreshTokens(ctx, db.RevokeRefreshTokensParams{
+			AppID:  token.AppID,
+			UserID: token.UserID,
+		}); err != nil {
+			log.Error().Err(err).Str("user_id", token.UserID.String()).Msg("Failed to revoke refresh tokens")
+			return fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+
+		return nil
+	}
+
+	return r.db.WithTransaction(ctx, txFn)
+}
+
+func (r *AuthRepository) StoreEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error {
+	log := authLog("StoreEmailVerificationToken")
+
+	err := r.queries.StoreEmailVerificationToken(ctx, db.StoreEmailVerificationTokenParams{
+		Jti:       token.JTI,
+		UserID:    token.UserID,
+		AppID:     token.AppID,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert email verification token into DB")
+		return fmt.Errorf("failed to insert email verification token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeEmailVerificationToken(ctx context.Context, appID, userID uuid.UUID) error {
+	log := authLog("RevokeEmailVerificationToken")
+
+	err := r.queries.RevokeEmailVerificationToken(ctx, db.RevokeEmailVerificationTokenParams{
+		AppID:  appID,
+		UserID: userID,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revoke email verification token from DB")
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetEmailVerificationTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.EmailVerificationToken, error) {
+	log := authLog("GetEmailVerificationTokenByJTI")
+
+	dbVerificationToken, err := r.queries.GetEmailVerificationTokenByJTI(ctx, db.GetEmailVerificationTokenByJTIParams{
+		AppID: appID,
+		Jti:   jti,
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("app_id", appID.String()).Str("jti", jti).Msg("Failed to query email verification token")
+		return nil, fmt.Errorf("failed to get email verification token: %w", err)
+	}
+
+	return &models.EmailVerificationToken{
+		JTI:       dbVerificationToken.Jti,
+		UserID:    dbVerificationToken.UserID,
+		AppID:     dbVerificationToken.AppID,
+		Token:     dbVerificationToken.Token,
+		ExpiresAt: dbVerificationToken.ExpiresAt,
+		IsActive:  dbVerificationToken.IsActive,
+		CreatedAt: dbVerificationToken.CreatedAt,
+	}, nil
+}
+
+func (r *AuthRepository) ConfirmEmailVerification(ctx context.Context, token *models.EmailVerificationToken) error {
+	log := authLog("ConfirmEmailVerification")
+
+	txFn := func(tx pgx.Tx) error {
+		qtx := r.queries.WithTx(tx)
+
+		usedTokens, err := qtx.UseEmailVerificationToken(ctx, db.UseEmailVerificationTokenParams{
+			AppID: token.AppID,
+			Jti:   token.JTI,
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("jti", token.JTI).Msg("Failed to mark email verification token as used")
+			return fmt.Errorf("failed to use email verification token: %w", err)
+		}
+
+		if usedTokens == 0 {
+			log.Warn().Str("jti", token.JTI).Msg("Email verification token already used or expired")
+			return authTypes.ErrTokenRevoked
+		}
+
+		updatedUsers, err := qtx.MarkUserEmailVerified(ctx, db.MarkUserEmailVerifiedParams{
+			AppID: token.AppID,
+			ID:    token.UserID,
+		})
+
+		if err != nil {
+			log.Error().Err(err).Str("user_id", token.UserID.String()).Msg("Failed to mark user email as verified")
+			return fmt.Errorf("failed to mark user email as verified: %w", err)
+		}
+
+		if updatedUsers == 0 {
+			log.Error().Str("user_id", token.UserID.String()).Msg("No user found")
+			return utils.ErrNotFound
+		}
+
+		return nil
+	}
+
+	return r.db.WithTransaction(ctx, txFn)
+}
+
+func (r *AuthRepository) StoreMagicLinkToken(ctx context.Context, token *models.MagicLinkToken) error {
+	log := authLog("StoreMagicLinkToken")
+
+	err := r.queries.StoreMagicLinkToken(ctx, db.StoreMagicLinkTokenParams{
+		Jti:       token.JTI,
+		UserID:    token.UserID,
+		AppID:     token.AppID,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to insert magic link token into DB")
+		return fmt.Errorf("failed to insert magic link token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) RevokeMagicLinkToken(ctx context.Context, appID, userID uuid.UUID) error {
+	log := authLog("RevokeMagicLinkToken")
+
+	err := r.queries.RevokeMagicLinkToken(ctx, db.RevokeMagicLinkTokenParams{
+		AppID:  appID,
+		UserID: userID,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revoke magic link token from DB")
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepository) GetMagicLinkTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.MagicLinkToken, error) {
+	log := authLog("GetMagicLinkTokenByJTI")
+
+	dbMagicLinkToken, err := r.queries.GetMagicLinkTokenByJTI(ctx, db.GetMagicLinkTokenByJTIParams{
+		AppID: appID,
+		Jti:   jti,
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("app_id", appID.String()).Str("jti", jti).Msg("Failed to query magic link token")
+		return nil, fmt.Errorf("failed to get magic link token: %w", err)
+	}
+
+	return &models.MagicLinkToken{
+		JTI:       dbMagicLinkToken.Jti,
+		UserID:    dbMagicLinkToken.UserID,
+		AppID:     dbMagicLinkToken.AppID,
+		Token:     dbMagicLinkToken.Token,
+		ExpiresAt: dbMagicLinkToken.ExpiresAt,
+		IsActive:  dbMagicLinkToken.IsActive,
+		CreatedAt: dbMagicLinkToken.CreatedAt,
+	}, nil
+}
+
+func (r *AuthRepository) UseMagicLinkToken(ctx context.Context, appID uuid.UUID, jti string) error {
+	log := authLog("UseMagicLinkToken")
+
+	usedTokens, err := r.queries.UseMagicLinkToken(ctx, db.UseMagicLinkTokenParams{
+		AppID: appID,
+		Jti:   jti,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("jti", jti).Msg("Failed to mark magic link token as used")
+		return fmt.Errorf("failed to use magic link token: %w", err)
+	}
+
+	if usedTokens == 0 {
+		log.Warn().Str("jti", jti).Msg("Magic link token already used or expired")
+		return authTypes.ErrTokenRevoked
+	}
+
+	return nil
+}
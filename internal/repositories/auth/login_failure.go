@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *AuthRepository) RecordLoginFailure(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string, windowStart time.Time) (int, error) {
+	failedAttempts, err := r.queries.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+		AppID:       appID,
+		SubjectType: string(subjectType),
+		Subject:     subject,
+		WindowStart: windowStart,
+	})
+
+	if err != nil {
+		authLog("RecordLoginFailure").Error().Err(err).Str("app_id", appID.String()).Str("subject_type", string(subjectType)).Msg("Failed to record login failure")
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return int(failedAttempts), nil
+}
+
+func (r *AuthRepository) LockLogin(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string, lockedUntil time.Time) error {
+	err := r.queries.LockLogin(ctx, db.LockLoginParams{
+		AppID:       appID,
+		SubjectType: string(subjectType),
+		Subject:     subject,
+		LockedUntil: utils.ToPgTimestamp(lockedUntil),
+	})
+
+	if err != nil {
+		authLog("LockLogin").Error().Err(err).Str("app_id", appID.String()).Str("subject_type", string(subjectType)).Msg("Failed to lock login")
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+// GetLoginLockedUntil returns when the latest lock on the email or IP ends,
+// or nil when neither is locked.
+func (r *AuthRepository) GetLoginLockedUntil(ctx context.Context, appID uuid.UUID, email, ipAddress string) (*time.Time, error) {
+	lockedUntil, err := r.queries.GetLoginLockedUntil(ctx, db.GetLoginLockedUntilParams{
+		AppID:     appID,
+		Email:     email,
+		IpAddress: ipAddress,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		authLog("GetLoginLockedUntil").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query login lock")
+		return nil, fmt.Errorf("failed to get login lock: %w", err)
+	}
+
+	return utils.FromPgTimestampPtr(lockedUntil), nil
+}
+
+func (r *AuthRepository) DeleteLoginFailures(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string) (bool, error) {
+	deleted, err := r.queries.DeleteLoginFailures(ctx, db.DeleteLoginFailuresParams{
+		AppID:       appID,
+		SubjectType: string(subjectType),
+		Subject:     subject,
+	})
+
+	if err != nil {
+		authLog("DeleteLoginFailures").Error().Err(err).Str("app_id", appID.String()).Str("subject_type", string(subjectType)).Msg("Failed to delete login failures")
+		return false, fmt.Errorf("failed to delete login failures: %w", err)
+	}
+
+	return deleted > 0, nil
+}
+
+func (r *AuthRepository) PurgeStaleLoginFailures(ctx context.Context, lastFailedBefore time.Time) (int64, error) {
+	purged, err := r.queries.PurgeStaleLoginFailures(ctx, lastFailedBefore)
+	if err != nil {
+		authLog("PurgeStaleLoginFailures").Error().Err(err).Msg("Failed to purge stale login failures")
+		return 0, fmt.Errorf("failed to purge stale login failures: %w", err)
+	}
+
+	return purged, nil
+}
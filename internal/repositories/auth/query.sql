@@ -1,9 +1,9 @@
 -- name: StoreRefreshToken :exec
-INSERT INTO core.refresh_tokens (jti, user_id, app_id, token, expires_at, is_active) 
-VALUES ($1, $2, $3, $4, $5, $6);
+INSERT INTO core.refresh_tokens (jti, user_id, app_id, token, expires_at, is_active, family_id, parent_jti, device_id, device_name, ip_address, user_agent, created_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
 
 -- name: GetRefreshTokenByJTI :one
-SELECT jti, user_id, app_id, token, expires_at, is_active, created_at 
+SELECT jti, user_id, app_id, token, expires_at, is_active, created_at, family_id, parent_jti, rotated_at, device_id, device_name 
 FROM core.refresh_tokens 
 WHERE app_id = $1 AND jti = $2 
 ORDER BY created_at;
@@ -23,6 +23,21 @@ UPDATE core.refresh_tokens
 SET is_active = false, updated_at = now()
 WHERE app_id = $1 AND user_id = $2 AND is_active = true;
 
+-- name: RevokeUserRefreshTokenByJTI :execrows
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND jti = $3 AND is_active = true;
+
+-- name: RevokeOtherRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND jti <> $3 AND is_active = true;
+
+-- name: RevokeDeviceRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND device_id = $3 AND is_active = true;
+
 -- name: StoreResetPasswordToken :exec
 INSERT INTO core.reset_password_tokens (jti, user_id, app_id, token, expires_at)
 VALUES ($1, $2, $3, $4, $5);
@@ -30,4 +45,111 @@ VALUES ($1, $2, $3, $4, $5);
 -- name: RevokeResetPasswordToken :exec
 UPDATE core.reset_password_tokens
 SET is_active = false, updated_at = now()
-WHERE app_id = $1 AND user_id = $2 AND is_active = true;
\ No newline at end of file
+WHERE app_id = $1 AND user_id = $2 AND is_active = true;
+
+-- name: RevokeRefreshTokenByJTI :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true;
+
+-- name: RotateRefreshToken :execrows
+UPDATE core.refresh_tokens
+SET is_active = false, rotated_at = now(), updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND family_id = $2 AND is_active = true;
+
+-- name: StoreBlacklistToken :exec
+INSERT INTO core.blacklist_tokens (jti, token, expires_at, reason)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsTokenBlacklisted :one
+SELECT EXISTS (
+    SELECT 1 FROM core.blacklist_tokens
+    WHERE jti = $1 AND expires_at > now()
+);
+
+-- name: GetResetPasswordTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.reset_password_tokens
+WHERE app_id = $1 AND jti = $2;
+
+-- name: UseResetPasswordToken :execrows
+UPDATE core.reset_password_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now();
+
+-- name: StoreEmailVerificationToken :exec
+INSERT INTO core.email_verification_tokens (jti, user_id, app_id, token, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: RevokeEmailVerificationToken :exec
+UPDATE core.email_verification_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND is_active = true;
+
+-- name: GetEmailVerificationTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.email_verification_tokens
+WHERE app_id = $1 AND jti = $2;
+
+-- name: UseEmailVerificationToken :execrows
+UPDATE core.email_verification_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now();
+
+-- name: StoreMagicLinkToken :exec
+INSERT INTO core.magic_link_tokens (jti, user_id, app_id, token, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: RevokeMagicLinkToken :exec
+UPDATE core.magic_link_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND is_active = true;
+
+-- name: GetMagicLinkTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.magic_link_tokens
+WHERE app_id = $1 AND jti = $2;
+
+-- name: UseMagicLinkToken :execrows
+UPDATE core.magic_link_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now();
+
+-- name: RecordLoginFailure :one
+INSERT INTO core.login_failures (app_id, subject_type, subject, failed_attempts, last_failed_at)
+VALUES (sqlc.arg(app_id), sqlc.arg(subject_type), sqlc.arg(subject), 1, now())
+ON CONFLICT (app_id, subject_type, subject) DO UPDATE
+SET failed_attempts = CASE
+        WHEN core.login_failures.last_failed_at < sqlc.arg(window_start) THEN 1
+        ELSE core.login_failures.failed_attempts + 1
+    END,
+    last_failed_at = now(),
+    updated_at = now()
+RETURNING failed_attempts;
+
+-- name: LockLogin :exec
+UPDATE core.login_failures
+SET locked_until = $4, updated_at = now()
+WHERE app_id = $1 AND subject_type = $2 AND subject = $3;
+
+-- name: GetLoginLockedUntil :one
+SELECT locked_until FROM core.login_failures
+WHERE app_id = sqlc.arg(app_id)
+    AND ((subject_type = 'email' AND subject = sqlc.arg(email)) OR (subject_type = 'ip' AND subject = sqlc.arg(ip_address)))
+    AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: DeleteLoginFailures :execrows
+DELETE FROM core.login_failures
+WHERE app_id = $1 AND subject_type = $2 AND subject = $3;
+
+-- name: PurgeStaleLoginFailures :execrows
+DELETE FROM core.login_failures
+WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < now());
\ No newline at end of file
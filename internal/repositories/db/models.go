@@ -12,8 +12,18 @@ import (
 )
 
 type CoreApp struct {
+	ID            uuid.UUID          `json:"id"`
+	Name          []byte             `json:"name"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	GrantedScopes []string           `json:"granted_scopes"`
+	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type CoreAppAllowedOrigin struct {
 	ID        uuid.UUID `json:"id"`
-	Name      []byte    `json:"name"`
+	AppID     uuid.UUID `json:"app_id"`
+	Origin    string    `json:"origin"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -27,6 +37,58 @@ type CoreAppApiKey struct {
 	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
 	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
 	UpdatedAt  time.Time          `json:"updated_at"`
+	KeyID      *string            `json:"key_id"`
+	Kind       string             `json:"kind"`
+}
+
+type CoreAppIdentityProvider struct {
+	AppID         uuid.UUID `json:"app_id"`
+	Provider      string    `json:"provider"`
+	Issuer        string    `json:"issuer"`
+	ClientID      string    `json:"client_id"`
+	ClientSecret  []byte    `json:"client_secret"`
+	Scopes        []string  `json:"scopes"`
+	ClaimMappings []byte    `json:"claim_mappings"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	TrustEmail    bool      `json:"trust_email"`
+}
+
+type CoreAppRedirectUri struct {
+	ID          uuid.UUID `json:"id"`
+	AppID       uuid.UUID `json:"app_id"`
+	RedirectUri string    `json:"redirect_uri"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CoreAppSetting struct {
+	AppID                    uuid.UUID `json:"app_id"`
+	AccessTokenTtlSeconds    int32     `json:"access_token_ttl_seconds"`
+	RefreshTokenTtlSeconds   int32     `json:"refresh_token_ttl_seconds"`
+	SessionLifetimeSeconds   int32     `json:"session_lifetime_seconds"`
+	PasswordMinLength        int32     `json:"password_min_length"`
+	PasswordRequireUppercase bool      `json:"password_require_uppercase"`
+	PasswordRequireLowercase bool      `json:"password_require_lowercase"`
+	PasswordRequireDigit     bool      `json:"password_require_digit"`
+	PasswordRequireSymbol    bool      `json:"password_require_symbol"`
+	MaxSessions              int32     `json:"max_sessions"`
+	RequireEmailVerification bool      `json:"require_email_verification"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+	GoogleClientIds          []string  `json:"google_client_ids"`
+}
+
+type CoreAuthorizationCode struct {
+	CodeHash            string             `json:"code_hash"`
+	AppID               uuid.UUID          `json:"app_id"`
+	UserID              uuid.UUID          `json:"user_id"`
+	RedirectUri         string             `json:"redirect_uri"`
+	CodeChallenge       string             `json:"code_challenge"`
+	CodeChallengeMethod string             `json:"code_challenge_method"`
+	CreatedAt           time.Time          `json:"created_at"`
+	ExpiresAt           time.Time          `json:"expires_at"`
+	UsedAt              pgtype.Timestamptz `json:"used_at"`
 }
 
 type CoreBlacklistToken struct {
@@ -38,17 +100,77 @@ type CoreBlacklistToken struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+type CoreEmailVerificationToken struct {
+	Jti       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CoreFederationState struct {
+	State        string    `json:"state"`
+	AppID        uuid.UUID `json:"app_id"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ResponseType string    `json:"response_type"`
+	CallbackUrl  *string   `json:"callback_url"`
+	RedirectUrl  *string   `json:"redirect_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	SetCookie    bool      `json:"set_cookie"`
+	CookieDomain *string   `json:"cookie_domain"`
+}
+
+type CoreLoginFailure struct {
+	AppID          uuid.UUID          `json:"app_id"`
+	SubjectType    string             `json:"subject_type"`
+	Subject        string             `json:"subject"`
+	FailedAttempts int32              `json:"failed_attempts"`
+	LastFailedAt   time.Time          `json:"last_failed_at"`
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+type CoreMagicLinkToken struct {
+	Jti       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CoreRateLimitCounter struct {
+	Key         string    `json:"key"`
+	WindowStart time.Time `json:"window_start"`
+	Hits        int32     `json:"hits"`
+}
+
 type CoreRefreshToken struct {
-	Jti        string    `json:"jti"`
-	UserID     uuid.UUID `json:"user_id"`
-	AppID      uuid.UUID `json:"app_id"`
-	DeviceID   string    `json:"device_id"`
-	DeviceName *string   `json:"device_name"`
-	Token      string    `json:"token"`
-	IsActive   bool      `json:"is_active"`
-	CreatedAt  time.Time `json:"created_at"`
-	ExpiresAt  time.Time `json:"expires_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	Jti        string             `json:"jti"`
+	UserID     uuid.UUID          `json:"user_id"`
+	AppID      uuid.UUID          `json:"app_id"`
+	DeviceID   string             `json:"device_id"`
+	DeviceName *string            `json:"device_name"`
+	Token      string             `json:"token"`
+	IsActive   bool               `json:"is_active"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	FamilyID   string             `json:"family_id"`
+	ParentJti  *string            `json:"parent_jti"`
+	RotatedAt  pgtype.Timestamptz `json:"rotated_at"`
+	LastUsedAt time.Time          `json:"last_used_at"`
+	IpAddress  *string            `json:"ip_address"`
+	UserAgent  *string            `json:"user_agent"`
 }
 
 type CoreResetPasswordToken struct {
@@ -62,6 +184,17 @@ type CoreResetPasswordToken struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type CoreSigningKey struct {
+	Kid         string             `json:"kid"`
+	Algorithm   string             `json:"algorithm"`
+	PrivateKey  []byte             `json:"private_key"`
+	PublicKey   string             `json:"public_key"`
+	ActivatesAt pgtype.Timestamptz `json:"activates_at"`
+	RetiresAt   pgtype.Timestamptz `json:"retires_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
 type CoreUser struct {
 	ID              uuid.UUID          `json:"id"`
 	AppID           uuid.UUID          `json:"app_id"`
@@ -6,31 +6,90 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	ConsumeFederationState(ctx context.Context, state string) (CoreFederationState, error)
+	DeleteAllowedOrigin(ctx context.Context, arg DeleteAllowedOriginParams) (int64, error)
+	DeleteIdentityProvider(ctx context.Context, arg DeleteIdentityProviderParams) (int64, error)
+	DeleteLoginFailures(ctx context.Context, arg DeleteLoginFailuresParams) (int64, error)
+	DeleteRedirectURI(ctx context.Context, arg DeleteRedirectURIParams) (int64, error)
+	GetActiveAppApiKeyByKeyID(ctx context.Context, keyID *string) (CoreAppApiKey, error)
+	GetActiveLegacyAppApiKeys(ctx context.Context, appID uuid.UUID) ([]CoreAppApiKey, error)
 	GetAllApps(ctx context.Context) ([]GetAllAppsRow, error)
 	GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error)
 	GetAllUsersByAppID(ctx context.Context, appID uuid.UUID) ([]GetAllUsersByAppIDRow, error)
-	GetAppByID(ctx context.Context, id uuid.UUID) (CoreApp, error)
+	GetAllowedOriginsByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppAllowedOrigin, error)
+	GetAppApiKeys(ctx context.Context, appID uuid.UUID) ([]GetAppApiKeysRow, error)
+	GetAppByID(ctx context.Context, id uuid.UUID) (GetAppByIDRow, error)
+	GetAppSettings(ctx context.Context, appID uuid.UUID) (CoreAppSetting, error)
 	GetAppUserByID(ctx context.Context, arg GetAppUserByIDParams) (GetAppUserByIDRow, error)
+	GetAuthorizationCode(ctx context.Context, codeHash string) (CoreAuthorizationCode, error)
+	GetEmailVerificationTokenByJTI(ctx context.Context, arg GetEmailVerificationTokenByJTIParams) (GetEmailVerificationTokenByJTIRow, error)
+	GetIdentityProvider(ctx context.Context, arg GetIdentityProviderParams) (CoreAppIdentityProvider, error)
+	GetIdentityProvidersByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppIdentityProvider, error)
+	GetLoginLockedUntil(ctx context.Context, arg GetLoginLockedUntilParams) (pgtype.Timestamptz, error)
+	GetMagicLinkTokenByJTI(ctx context.Context, arg GetMagicLinkTokenByJTIParams) (GetMagicLinkTokenByJTIRow, error)
+	GetRedirectURIsByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppRedirectUri, error)
 	GetRefreshTokenByJTI(ctx context.Context, arg GetRefreshTokenByJTIParams) (GetRefreshTokenByJTIRow, error)
+	GetResetPasswordTokenByJTI(ctx context.Context, arg GetResetPasswordTokenByJTIParams) (GetResetPasswordTokenByJTIRow, error)
+	GetSigningKeys(ctx context.Context) ([]CoreSigningKey, error)
 	GetUserActiveRefreshTokensByJTI(ctx context.Context, arg GetUserActiveRefreshTokensByJTIParams) ([]CoreRefreshToken, error)
 	GetUserActiveRefreshTokensByUserID(ctx context.Context, arg GetUserActiveRefreshTokensByUserIDParams) ([]CoreRefreshToken, error)
 	GetUserAuthenticationByProvider(ctx context.Context, arg GetUserAuthenticationByProviderParams) (CoreUserAuthProvider, error)
+	GetUserByAuthProvider(ctx context.Context, arg GetUserByAuthProviderParams) (CoreUser, error)
 	GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (CoreUser, error)
+	HitRateLimitCounter(ctx context.Context, arg HitRateLimitCounterParams) (HitRateLimitCounterRow, error)
+	IsRedirectURIRegistered(ctx context.Context, arg IsRedirectURIRegisteredParams) (bool, error)
+	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error)
 	LockAppForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	LockLogin(ctx context.Context, arg LockLoginParams) error
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	PurgeDeletedApps(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeRateLimitCounters(ctx context.Context, windowStart time.Time) (int64, error)
+	PurgeStaleLoginFailures(ctx context.Context, lastFailedAt time.Time) (int64, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error)
 	RevokeActiveAppApiKeys(ctx context.Context, arg RevokeActiveAppApiKeysParams) (int64, error)
+	RevokeAppRefreshTokens(ctx context.Context, appID uuid.UUID) error
+	RevokeDeviceRefreshTokens(ctx context.Context, arg RevokeDeviceRefreshTokensParams) error
+	RevokeEmailVerificationToken(ctx context.Context, arg RevokeEmailVerificationTokenParams) error
+	RevokeMagicLinkToken(ctx context.Context, arg RevokeMagicLinkTokenParams) error
+	RevokeOtherRefreshTokens(ctx context.Context, arg RevokeOtherRefreshTokensParams) error
+	RevokeRefreshTokenByJTI(ctx context.Context, arg RevokeRefreshTokenByJTIParams) error
+	RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) error
 	RevokeRefreshTokens(ctx context.Context, arg RevokeRefreshTokensParams) error
 	RevokeResetPasswordToken(ctx context.Context, arg RevokeResetPasswordTokenParams) error
+	RevokeUserRefreshTokenByJTI(ctx context.Context, arg RevokeUserRefreshTokenByJTIParams) (int64, error)
+	RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error)
+	SoftDeleteApp(ctx context.Context, arg SoftDeleteAppParams) (int64, error)
+	StoreAllowedOrigin(ctx context.Context, arg StoreAllowedOriginParams) error
 	StoreApp(ctx context.Context, arg StoreAppParams) error
 	StoreAppApiKey(ctx context.Context, arg StoreAppApiKeyParams) error
+	StoreAuthorizationCode(ctx context.Context, arg StoreAuthorizationCodeParams) error
+	StoreBlacklistToken(ctx context.Context, arg StoreBlacklistTokenParams) error
+	StoreEmailVerificationToken(ctx context.Context, arg StoreEmailVerificationTokenParams) error
+	StoreFederationState(ctx context.Context, arg StoreFederationStateParams) error
+	StoreIdentityProvider(ctx context.Context, arg StoreIdentityProviderParams) error
+	StoreMagicLinkToken(ctx context.Context, arg StoreMagicLinkTokenParams) error
+	StoreRedirectURI(ctx context.Context, arg StoreRedirectURIParams) error
 	StoreRefreshToken(ctx context.Context, arg StoreRefreshTokenParams) error
 	StoreResetPasswordToken(ctx context.Context, arg StoreResetPasswordTokenParams) error
 	StoreUser(ctx context.Context, arg StoreUserParams) error
 	StoreUserAuthProvider(ctx context.Context, arg StoreUserAuthProviderParams) error
+	TouchAppApiKey(ctx context.Context, id uuid.UUID) error
+	UpdateAppGrantedScopes(ctx context.Context, arg UpdateAppGrantedScopesParams) (int64, error)
+	UpdateAppName(ctx context.Context, arg UpdateAppNameParams) (int64, error)
+	UpdateUserAuthProviderPassword(ctx context.Context, arg UpdateUserAuthProviderPasswordParams) (int64, error)
+	UpsertAppSettings(ctx context.Context, arg UpsertAppSettingsParams) error
+	UpsertSigningKey(ctx context.Context, arg UpsertSigningKeyParams) error
+	UseAuthorizationCode(ctx context.Context, codeHash string) (int64, error)
+	UseEmailVerificationToken(ctx context.Context, arg UseEmailVerificationTokenParams) (int64, error)
+	UseMagicLinkToken(ctx context.Context, arg UseMagicLinkTokenParams) (int64, error)
+	UseResetPasswordToken(ctx context.Context, arg UseResetPasswordTokenParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -13,8 +13,168 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const consumeFederationState = `-- name: ConsumeFederationState :one
+DELETE FROM core.federation_states
+WHERE state = $1 AND expires_at > now()
+RETURNING state, app_id, provider, nonce, code_verifier, response_type, callback_url, redirect_url, created_at, expires_at, set_cookie, cookie_domain
+`
+
+func (q *Queries) ConsumeFederationState(ctx context.Context, state string) (CoreFederationState, error) {
+	row := q.db.QueryRow(ctx, consumeFederationState, state)
+	var i CoreFederationState
+	err := row.Scan(
+		&i.State,
+		&i.AppID,
+		&i.Provider,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ResponseType,
+		&i.CallbackUrl,
+		&i.RedirectUrl,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SetCookie,
+		&i.CookieDomain,
+	)
+	return i, err
+}
+
+const deleteAllowedOrigin = `-- name: DeleteAllowedOrigin :execrows
+DELETE FROM core.app_allowed_origins
+WHERE app_id = $1 AND id = $2
+`
+
+type DeleteAllowedOriginParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) DeleteAllowedOrigin(ctx context.Context, arg DeleteAllowedOriginParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAllowedOrigin, arg.AppID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteIdentityProvider = `-- name: DeleteIdentityProvider :execrows
+DELETE FROM core.app_identity_providers
+WHERE app_id = $1 AND provider = $2
+`
+
+type DeleteIdentityProviderParams struct {
+	AppID    uuid.UUID `json:"app_id"`
+	Provider string    `json:"provider"`
+}
+
+func (q *Queries) DeleteIdentityProvider(ctx context.Context, arg DeleteIdentityProviderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIdentityProvider, arg.AppID, arg.Provider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteLoginFailures = `-- name: DeleteLoginFailures :execrows
+DELETE FROM core.login_failures
+WHERE app_id = $1 AND subject_type = $2 AND subject = $3
+`
+
+type DeleteLoginFailuresParams struct {
+	AppID       uuid.UUID `json:"app_id"`
+	SubjectType string    `json:"subject_type"`
+	Subject     string    `json:"subject"`
+}
+
+func (q *Queries) DeleteLoginFailures(ctx context.Context, arg DeleteLoginFailuresParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLoginFailures, arg.AppID, arg.SubjectType, arg.Subject)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRedirectURI = `-- name: DeleteRedirectURI :execrows
+DELETE FROM core.app_redirect_uris
+WHERE app_id = $1 AND id = $2
+`
+
+type DeleteRedirectURIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) DeleteRedirectURI(ctx context.Context, arg DeleteRedirectURIParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRedirectURI, arg.AppID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getActiveAppApiKeyByKeyID = `-- name: GetActiveAppApiKeyByKeyID :one
+SELECT id, app_id, key_hash, created_at, is_active, revoked_at, last_used_at, updated_at, key_id, kind
+FROM core.app_api_keys
+WHERE key_id = $1 AND is_active = true AND (revoked_at IS NULL OR revoked_at > now())
+`
+
+func (q *Queries) GetActiveAppApiKeyByKeyID(ctx context.Context, keyID *string) (CoreAppApiKey, error) {
+	row := q.db.QueryRow(ctx, getActiveAppApiKeyByKeyID, keyID)
+	var i CoreAppApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.KeyHash,
+		&i.CreatedAt,
+		&i.IsActive,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+		&i.UpdatedAt,
+		&i.KeyID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const getActiveLegacyAppApiKeys = `-- name: GetActiveLegacyAppApiKeys :many
+SELECT id, app_id, key_hash, created_at, is_active, revoked_at, last_used_at, updated_at, key_id, kind
+FROM core.app_api_keys
+WHERE app_id = $1 AND key_id IS NULL AND is_active = true AND (revoked_at IS NULL OR revoked_at > now())
+`
+
+func (q *Queries) GetActiveLegacyAppApiKeys(ctx context.Context, appID uuid.UUID) ([]CoreAppApiKey, error) {
+	rows, err := q.db.Query(ctx, getActiveLegacyAppApiKeys, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CoreAppApiKey{}
+	for rows.Next() {
+		var i CoreAppApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.KeyHash,
+			&i.CreatedAt,
+			&i.IsActive,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+			&i.UpdatedAt,
+			&i.KeyID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllApps = `-- name: GetAllApps :many
-SELECT id, name FROM core.apps ORDER BY created_at DESC
+SELECT id, name FROM core.apps WHERE deleted_at IS NULL ORDER BY created_at DESC
 `
 
 type GetAllAppsRow struct {
@@ -113,18 +273,131 @@ func (q *Queries) GetAllUsersByAppID(ctx context.Context, appID uuid.UUID) ([]Ge
 	return items, nil
 }
 
+const getAllowedOriginsByAppID = `-- name: GetAllowedOriginsByAppID :many
+SELECT id, app_id, origin, created_at, updated_at FROM core.app_allowed_origins
+WHERE app_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetAllowedOriginsByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppAllowedOrigin, error) {
+	rows, err := q.db.Query(ctx, getAllowedOriginsByAppID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoreAppAllowedOrigin
+	for rows.Next() {
+		var i CoreAppAllowedOrigin
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.Origin,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAppApiKeys = `-- name: GetAppApiKeys :many
+SELECT id, key_id, kind, created_at, is_active, revoked_at, last_used_at
+FROM core.app_api_keys
+WHERE app_id = $1
+ORDER BY created_at DESC
+`
+
+type GetAppApiKeysRow struct {
+	ID         uuid.UUID          `json:"id"`
+	KeyID      *string            `json:"key_id"`
+	Kind       string             `json:"kind"`
+	CreatedAt  time.Time          `json:"created_at"`
+	IsActive   bool               `json:"is_active"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+}
+
+func (q *Queries) GetAppApiKeys(ctx context.Context, appID uuid.UUID) ([]GetAppApiKeysRow, error) {
+	rows, err := q.db.Query(ctx, getAppApiKeys, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAppApiKeysRow
+	for rows.Next() {
+		var i GetAppApiKeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.KeyID,
+			&i.Kind,
+			&i.CreatedAt,
+			&i.IsActive,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAppByID = `-- name: GetAppByID :one
-SELECT id, name, created_at, updated_at FROM core.apps WHERE id = $1
+SELECT id, name, created_at, updated_at, granted_scopes FROM core.apps WHERE id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetAppByID(ctx context.Context, id uuid.UUID) (CoreApp, error) {
+type GetAppByIDRow struct {
+	ID            uuid.UUID `json:"id"`
+	Name          []byte    `json:"name"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	GrantedScopes []string  `json:"granted_scopes"`
+}
+
+func (q *Queries) GetAppByID(ctx context.Context, id uuid.UUID) (GetAppByIDRow, error) {
 	row := q.db.QueryRow(ctx, getAppByID, id)
-	var i CoreApp
+	var i GetAppByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GrantedScopes,
+	)
+	return i, err
+}
+
+const getAppSettings = `-- name: GetAppSettings :one
+SELECT app_id, access_token_ttl_seconds, refresh_token_ttl_seconds, session_lifetime_seconds, password_min_length, password_require_uppercase, password_require_lowercase, password_require_digit, password_require_symbol, max_sessions, require_email_verification, created_at, updated_at, google_client_ids FROM core.app_settings
+WHERE app_id = $1
+`
+
+func (q *Queries) GetAppSettings(ctx context.Context, appID uuid.UUID) (CoreAppSetting, error) {
+	row := q.db.QueryRow(ctx, getAppSettings, appID)
+	var i CoreAppSetting
+	err := row.Scan(
+		&i.AppID,
+		&i.AccessTokenTtlSeconds,
+		&i.RefreshTokenTtlSeconds,
+		&i.SessionLifetimeSeconds,
+		&i.PasswordMinLength,
+		&i.PasswordRequireUppercase,
+		&i.PasswordRequireLowercase,
+		&i.PasswordRequireDigit,
+		&i.PasswordRequireSymbol,
+		&i.MaxSessions,
+		&i.RequireEmailVerification,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GoogleClientIds,
 	)
 	return i, err
 }
@@ -163,74 +436,121 @@ func (q *Queries) GetAppUserByID(ctx context.Context, arg GetAppUserByIDParams)
 	return i, err
 }
 
-const getRefreshTokenByJTI = `-- name: GetRefreshTokenByJTI :one
-SELECT jti, user_id, app_id, token, expires_at, is_active, created_at 
-FROM core.refresh_tokens 
-WHERE app_id = $1 AND jti = $2 
-ORDER BY created_at
+const getAuthorizationCode = `-- name: GetAuthorizationCode :one
+SELECT code_hash, app_id, user_id, redirect_uri, code_challenge, code_challenge_method, created_at, expires_at, used_at
+FROM core.authorization_codes
+WHERE code_hash = $1
 `
 
-type GetRefreshTokenByJTIParams struct {
+func (q *Queries) GetAuthorizationCode(ctx context.Context, codeHash string) (CoreAuthorizationCode, error) {
+	row := q.db.QueryRow(ctx, getAuthorizationCode, codeHash)
+	var i CoreAuthorizationCode
+	err := row.Scan(
+		&i.CodeHash,
+		&i.AppID,
+		&i.UserID,
+		&i.RedirectUri,
+		&i.CodeChallenge,
+		&i.CodeChallengeMethod,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const getEmailVerificationTokenByJTI = `-- name: GetEmailVerificationTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.email_verification_tokens
+WHERE app_id = $1 AND jti = $2
+`
+
+type GetEmailVerificationTokenByJTIParams struct {
 	AppID uuid.UUID `json:"app_id"`
 	Jti   string    `json:"jti"`
 }
 
-type GetRefreshTokenByJTIRow struct {
+type GetEmailVerificationTokenByJTIRow struct {
 	Jti       string    `json:"jti"`
 	UserID    uuid.UUID `json:"user_id"`
 	AppID     uuid.UUID `json:"app_id"`
 	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
-func (q *Queries) GetRefreshTokenByJTI(ctx context.Context, arg GetRefreshTokenByJTIParams) (GetRefreshTokenByJTIRow, error) {
-	row := q.db.QueryRow(ctx, getRefreshTokenByJTI, arg.AppID, arg.Jti)
-	var i GetRefreshTokenByJTIRow
+func (q *Queries) GetEmailVerificationTokenByJTI(ctx context.Context, arg GetEmailVerificationTokenByJTIParams) (GetEmailVerificationTokenByJTIRow, error) {
+	row := q.db.QueryRow(ctx, getEmailVerificationTokenByJTI, arg.AppID, arg.Jti)
+	var i GetEmailVerificationTokenByJTIRow
 	err := row.Scan(
 		&i.Jti,
 		&i.UserID,
 		&i.AppID,
 		&i.Token,
-		&i.ExpiresAt,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
-const getUserActiveRefreshTokensByJTI = `-- name: GetUserActiveRefreshTokensByJTI :many
-SELECT jti, user_id, app_id, device_id, device_name, token, is_active, created_at, expires_at, updated_at FROM core.refresh_tokens
-WHERE app_id = $1 AND jti = $2 AND is_active = true
-ORDER BY created_at DESC
+const getIdentityProvider = `-- name: GetIdentityProvider :one
+SELECT app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, created_at, updated_at, trust_email
+FROM core.app_identity_providers
+WHERE app_id = $1 AND provider = $2
 `
 
-type GetUserActiveRefreshTokensByJTIParams struct {
-	AppID uuid.UUID `json:"app_id"`
-	Jti   string    `json:"jti"`
+type GetIdentityProviderParams struct {
+	AppID    uuid.UUID `json:"app_id"`
+	Provider string    `json:"provider"`
 }
 
-func (q *Queries) GetUserActiveRefreshTokensByJTI(ctx context.Context, arg GetUserActiveRefreshTokensByJTIParams) ([]CoreRefreshToken, error) {
-	rows, err := q.db.Query(ctx, getUserActiveRefreshTokensByJTI, arg.AppID, arg.Jti)
+func (q *Queries) GetIdentityProvider(ctx context.Context, arg GetIdentityProviderParams) (CoreAppIdentityProvider, error) {
+	row := q.db.QueryRow(ctx, getIdentityProvider, arg.AppID, arg.Provider)
+	var i CoreAppIdentityProvider
+	err := row.Scan(
+		&i.AppID,
+		&i.Provider,
+		&i.Issuer,
+		&i.ClientID,
+		&i.ClientSecret,
+		&i.Scopes,
+		&i.ClaimMappings,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TrustEmail,
+	)
+	return i, err
+}
+
+const getIdentityProvidersByAppID = `-- name: GetIdentityProvidersByAppID :many
+SELECT app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, created_at, updated_at, trust_email
+FROM core.app_identity_providers
+WHERE app_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetIdentityProvidersByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppIdentityProvider, error) {
+	rows, err := q.db.Query(ctx, getIdentityProvidersByAppID, appID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []CoreRefreshToken{}
+	items := []CoreAppIdentityProvider{}
 	for rows.Next() {
-		var i CoreRefreshToken
+		var i CoreAppIdentityProvider
 		if err := rows.Scan(
-			&i.Jti,
-			&i.UserID,
 			&i.AppID,
-			&i.DeviceID,
-			&i.DeviceName,
-			&i.Token,
-			&i.IsActive,
+			&i.Provider,
+			&i.Issuer,
+			&i.ClientID,
+			&i.ClientSecret,
+			&i.Scopes,
+			&i.ClaimMappings,
 			&i.CreatedAt,
-			&i.ExpiresAt,
 			&i.UpdatedAt,
+			&i.TrustEmail,
 		); err != nil {
 			return nil, err
 		}
@@ -242,37 +562,300 @@ func (q *Queries) GetUserActiveRefreshTokensByJTI(ctx context.Context, arg GetUs
 	return items, nil
 }
 
-const getUserActiveRefreshTokensByUserID = `-- name: GetUserActiveRefreshTokensByUserID :many
-SELECT jti, user_id, app_id, device_id, device_name, token, is_active, created_at, expires_at, updated_at FROM core.refresh_tokens
-WHERE app_id = $1 AND user_id = $2 AND is_active = true
-ORDER BY created_at DESC
+const getLoginLockedUntil = `-- name: GetLoginLockedUntil :one
+SELECT locked_until FROM core.login_failures
+WHERE app_id = $1
+    AND ((subject_type = 'email' AND subject = $2) OR (subject_type = 'ip' AND subject = $3))
+    AND locked_until > now()
+ORDER BY locked_until DESC
+LIMIT 1
 `
 
-type GetUserActiveRefreshTokensByUserIDParams struct {
-	AppID  uuid.UUID `json:"app_id"`
-	UserID uuid.UUID `json:"user_id"`
+type GetLoginLockedUntilParams struct {
+	AppID     uuid.UUID `json:"app_id"`
+	Email     string    `json:"email"`
+	IpAddress string    `json:"ip_address"`
 }
 
-func (q *Queries) GetUserActiveRefreshTokensByUserID(ctx context.Context, arg GetUserActiveRefreshTokensByUserIDParams) ([]CoreRefreshToken, error) {
-	rows, err := q.db.Query(ctx, getUserActiveRefreshTokensByUserID, arg.AppID, arg.UserID)
+func (q *Queries) GetLoginLockedUntil(ctx context.Context, arg GetLoginLockedUntilParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLoginLockedUntil, arg.AppID, arg.Email, arg.IpAddress)
+	var locked_until pgtype.Timestamptz
+	err := row.Scan(&locked_until)
+	return locked_until, err
+}
+
+const getMagicLinkTokenByJTI = `-- name: GetMagicLinkTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.magic_link_tokens
+WHERE app_id = $1 AND jti = $2
+`
+
+type GetMagicLinkTokenByJTIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+type GetMagicLinkTokenByJTIRow struct {
+	Jti       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) GetMagicLinkTokenByJTI(ctx context.Context, arg GetMagicLinkTokenByJTIParams) (GetMagicLinkTokenByJTIRow, error) {
+	row := q.db.QueryRow(ctx, getMagicLinkTokenByJTI, arg.AppID, arg.Jti)
+	var i GetMagicLinkTokenByJTIRow
+	err := row.Scan(
+		&i.Jti,
+		&i.UserID,
+		&i.AppID,
+		&i.Token,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getRedirectURIsByAppID = `-- name: GetRedirectURIsByAppID :many
+SELECT id, app_id, redirect_uri, created_at, updated_at
+FROM core.app_redirect_uris
+WHERE app_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetRedirectURIsByAppID(ctx context.Context, appID uuid.UUID) ([]CoreAppRedirectUri, error) {
+	rows, err := q.db.Query(ctx, getRedirectURIsByAppID, appID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []CoreRefreshToken{}
+	var items []CoreAppRedirectUri
 	for rows.Next() {
-		var i CoreRefreshToken
+		var i CoreAppRedirectUri
 		if err := rows.Scan(
-			&i.Jti,
-			&i.UserID,
+			&i.ID,
 			&i.AppID,
-			&i.DeviceID,
-			&i.DeviceName,
+			&i.RedirectUri,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRefreshTokenByJTI = `-- name: GetRefreshTokenByJTI :one
+SELECT jti, user_id, app_id, token, expires_at, is_active, created_at, family_id, parent_jti, rotated_at, device_id, device_name 
+FROM core.refresh_tokens 
+WHERE app_id = $1 AND jti = $2 
+ORDER BY created_at
+`
+
+type GetRefreshTokenByJTIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+type GetRefreshTokenByJTIRow struct {
+	Jti        string             `json:"jti"`
+	UserID     uuid.UUID          `json:"user_id"`
+	AppID      uuid.UUID          `json:"app_id"`
+	Token      string             `json:"token"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	IsActive   bool               `json:"is_active"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FamilyID   string             `json:"family_id"`
+	ParentJti  *string            `json:"parent_jti"`
+	RotatedAt  pgtype.Timestamptz `json:"rotated_at"`
+	DeviceID   string             `json:"device_id"`
+	DeviceName *string            `json:"device_name"`
+}
+
+func (q *Queries) GetRefreshTokenByJTI(ctx context.Context, arg GetRefreshTokenByJTIParams) (GetRefreshTokenByJTIRow, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByJTI, arg.AppID, arg.Jti)
+	var i GetRefreshTokenByJTIRow
+	err := row.Scan(
+		&i.Jti,
+		&i.UserID,
+		&i.AppID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentJti,
+		&i.RotatedAt,
+		&i.DeviceID,
+		&i.DeviceName,
+	)
+	return i, err
+}
+
+const getResetPasswordTokenByJTI = `-- name: GetResetPasswordTokenByJTI :one
+SELECT jti, user_id, app_id, token, is_active, created_at, expires_at
+FROM core.reset_password_tokens
+WHERE app_id = $1 AND jti = $2
+`
+
+type GetResetPasswordTokenByJTIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+type GetResetPasswordTokenByJTIRow struct {
+	Jti       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) GetResetPasswordTokenByJTI(ctx context.Context, arg GetResetPasswordTokenByJTIParams) (GetResetPasswordTokenByJTIRow, error) {
+	row := q.db.QueryRow(ctx, getResetPasswordTokenByJTI, arg.AppID, arg.Jti)
+	var i GetResetPasswordTokenByJTIRow
+	err := row.Scan(
+		&i.Jti,
+		&i.UserID,
+		&i.AppID,
+		&i.Token,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getSigningKeys = `-- name: GetSigningKeys :many
+SELECT kid, algorithm, private_key, public_key, activates_at, retires_at, created_at, updated_at
+FROM core.signing_keys
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetSigningKeys(ctx context.Context) ([]CoreSigningKey, error) {
+	rows, err := q.db.Query(ctx, getSigningKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CoreSigningKey
+	for rows.Next() {
+		var i CoreSigningKey
+		if err := rows.Scan(
+			&i.Kid,
+			&i.Algorithm,
+			&i.PrivateKey,
+			&i.PublicKey,
+			&i.ActivatesAt,
+			&i.RetiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActiveRefreshTokensByJTI = `-- name: GetUserActiveRefreshTokensByJTI :many
+SELECT jti, user_id, app_id, device_id, device_name, token, is_active, created_at, expires_at, updated_at, family_id, parent_jti, rotated_at, last_used_at, ip_address, user_agent FROM core.refresh_tokens
+WHERE app_id = $1 AND jti = $2 AND is_active = true
+ORDER BY created_at DESC
+`
+
+type GetUserActiveRefreshTokensByJTIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) GetUserActiveRefreshTokensByJTI(ctx context.Context, arg GetUserActiveRefreshTokensByJTIParams) ([]CoreRefreshToken, error) {
+	rows, err := q.db.Query(ctx, getUserActiveRefreshTokensByJTI, arg.AppID, arg.Jti)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CoreRefreshToken{}
+	for rows.Next() {
+		var i CoreRefreshToken
+		if err := rows.Scan(
+			&i.Jti,
+			&i.UserID,
+			&i.AppID,
+			&i.DeviceID,
+			&i.DeviceName,
+			&i.Token,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UpdatedAt,
+			&i.FamilyID,
+			&i.ParentJti,
+			&i.RotatedAt,
+			&i.LastUsedAt,
+			&i.IpAddress,
+			&i.UserAgent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActiveRefreshTokensByUserID = `-- name: GetUserActiveRefreshTokensByUserID :many
+SELECT jti, user_id, app_id, device_id, device_name, token, is_active, created_at, expires_at, updated_at, family_id, parent_jti, rotated_at, last_used_at, ip_address, user_agent FROM core.refresh_tokens
+WHERE app_id = $1 AND user_id = $2 AND is_active = true
+ORDER BY created_at DESC
+`
+
+type GetUserActiveRefreshTokensByUserIDParams struct {
+	AppID  uuid.UUID `json:"app_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUserActiveRefreshTokensByUserID(ctx context.Context, arg GetUserActiveRefreshTokensByUserIDParams) ([]CoreRefreshToken, error) {
+	rows, err := q.db.Query(ctx, getUserActiveRefreshTokensByUserID, arg.AppID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CoreRefreshToken{}
+	for rows.Next() {
+		var i CoreRefreshToken
+		if err := rows.Scan(
+			&i.Jti,
+			&i.UserID,
+			&i.AppID,
+			&i.DeviceID,
+			&i.DeviceName,
 			&i.Token,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.ExpiresAt,
 			&i.UpdatedAt,
+			&i.FamilyID,
+			&i.ParentJti,
+			&i.RotatedAt,
+			&i.LastUsedAt,
+			&i.IpAddress,
+			&i.UserAgent,
 		); err != nil {
 			return nil, err
 		}
@@ -311,6 +894,35 @@ func (q *Queries) GetUserAuthenticationByProvider(ctx context.Context, arg GetUs
 	return i, err
 }
 
+const getUserByAuthProvider = `-- name: GetUserByAuthProvider :one
+SELECT u.id, u.app_id, u.name, u.email, u.is_email_verified, u.email_verified_at, u.created_at, u.updated_at
+FROM core.users u
+JOIN core.user_auth_providers uap ON uap.user_id = u.id AND uap.app_id = u.app_id
+WHERE uap.app_id = $1 AND uap.provider = $2 AND uap.provider_user_id = $3
+`
+
+type GetUserByAuthProviderParams struct {
+	AppID          uuid.UUID `json:"app_id"`
+	Provider       string    `json:"provider"`
+	ProviderUserID *string   `json:"provider_user_id"`
+}
+
+func (q *Queries) GetUserByAuthProvider(ctx context.Context, arg GetUserByAuthProviderParams) (CoreUser, error) {
+	row := q.db.QueryRow(ctx, getUserByAuthProvider, arg.AppID, arg.Provider, arg.ProviderUserID)
+	var i CoreUser
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.Name,
+		&i.Email,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT id, app_id, name, email, is_email_verified, email_verified_at, created_at, updated_at 
 FROM core.users 
@@ -338,8 +950,75 @@ func (q *Queries) GetUserByEmail(ctx context.Context, arg GetUserByEmailParams)
 	return i, err
 }
 
+const hitRateLimitCounter = `-- name: HitRateLimitCounter :one
+WITH hit AS (
+    INSERT INTO core.rate_limit_counters (key, window_start, hits)
+    VALUES ($1, $2, 1)
+    ON CONFLICT (key, window_start) DO UPDATE
+    SET hits = core.rate_limit_counters.hits + 1
+    RETURNING hits
+)
+SELECT hit.hits AS current_hits,
+    COALESCE((
+        SELECT previous.hits FROM core.rate_limit_counters previous
+        WHERE previous.key = $1 AND previous.window_start = $3
+    ), 0)::INTEGER AS previous_hits
+FROM hit
+`
+
+type HitRateLimitCounterParams struct {
+	Key                 string    `json:"key"`
+	WindowStart         time.Time `json:"window_start"`
+	PreviousWindowStart time.Time `json:"previous_window_start"`
+}
+
+type HitRateLimitCounterRow struct {
+	CurrentHits  int32 `json:"current_hits"`
+	PreviousHits int32 `json:"previous_hits"`
+}
+
+func (q *Queries) HitRateLimitCounter(ctx context.Context, arg HitRateLimitCounterParams) (HitRateLimitCounterRow, error) {
+	row := q.db.QueryRow(ctx, hitRateLimitCounter, arg.Key, arg.WindowStart, arg.PreviousWindowStart)
+	var i HitRateLimitCounterRow
+	err := row.Scan(&i.CurrentHits, &i.PreviousHits)
+	return i, err
+}
+
+const isRedirectURIRegistered = `-- name: IsRedirectURIRegistered :one
+SELECT EXISTS (
+    SELECT 1 FROM core.app_redirect_uris
+    WHERE app_id = $1 AND redirect_uri = $2
+)
+`
+
+type IsRedirectURIRegisteredParams struct {
+	AppID       uuid.UUID `json:"app_id"`
+	RedirectUri string    `json:"redirect_uri"`
+}
+
+func (q *Queries) IsRedirectURIRegistered(ctx context.Context, arg IsRedirectURIRegisteredParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isRedirectURIRegistered, arg.AppID, arg.RedirectUri)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isTokenBlacklisted = `-- name: IsTokenBlacklisted :one
+SELECT EXISTS (
+    SELECT 1 FROM core.blacklist_tokens
+    WHERE jti = $1 AND expires_at > now()
+)
+`
+
+func (q *Queries) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	row := q.db.QueryRow(ctx, isTokenBlacklisted, jti)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const lockAppForUpdate = `-- name: LockAppForUpdate :one
-SELECT id FROM core.apps WHERE id = $1 FOR UPDATE
+SELECT id FROM core.apps WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
 `
 
 func (q *Queries) LockAppForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
@@ -348,25 +1027,249 @@ func (q *Queries) LockAppForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID
 	return id, err
 }
 
+const lockLogin = `-- name: LockLogin :exec
+UPDATE core.login_failures
+SET locked_until = $4, updated_at = now()
+WHERE app_id = $1 AND subject_type = $2 AND subject = $3
+`
+
+type LockLoginParams struct {
+	AppID       uuid.UUID          `json:"app_id"`
+	SubjectType string             `json:"subject_type"`
+	Subject     string             `json:"subject"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+func (q *Queries) LockLogin(ctx context.Context, arg LockLoginParams) error {
+	_, err := q.db.Exec(ctx, lockLogin,
+		arg.AppID,
+		arg.SubjectType,
+		arg.Subject,
+		arg.LockedUntil,
+	)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :execrows
+UPDATE core.users
+SET is_email_verified = true, email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+WHERE app_id = $1 AND id = $2
+`
+
+type MarkUserEmailVerifiedParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markUserEmailVerified, arg.AppID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeDeletedApps = `-- name: PurgeDeletedApps :execrows
+DELETE FROM core.apps
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedApps(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedApps, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeRateLimitCounters = `-- name: PurgeRateLimitCounters :execrows
+DELETE FROM core.rate_limit_counters
+WHERE window_start < $1
+`
+
+func (q *Queries) PurgeRateLimitCounters(ctx context.Context, windowStart time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeRateLimitCounters, windowStart)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeStaleLoginFailures = `-- name: PurgeStaleLoginFailures :execrows
+DELETE FROM core.login_failures
+WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < now())
+`
+
+func (q *Queries) PurgeStaleLoginFailures(ctx context.Context, lastFailedAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeStaleLoginFailures, lastFailedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO core.login_failures (app_id, subject_type, subject, failed_attempts, last_failed_at)
+VALUES ($1, $2, $3, 1, now())
+ON CONFLICT (app_id, subject_type, subject) DO UPDATE
+SET failed_attempts = CASE
+        WHEN core.login_failures.last_failed_at < $4 THEN 1
+        ELSE core.login_failures.failed_attempts + 1
+    END,
+    last_failed_at = now(),
+    updated_at = now()
+RETURNING failed_attempts
+`
+
+type RecordLoginFailureParams struct {
+	AppID       uuid.UUID `json:"app_id"`
+	SubjectType string    `json:"subject_type"`
+	Subject     string    `json:"subject"`
+	WindowStart time.Time `json:"window_start"`
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure,
+		arg.AppID,
+		arg.SubjectType,
+		arg.Subject,
+		arg.WindowStart,
+	)
+	var failed_attempts int32
+	err := row.Scan(&failed_attempts)
+	return failed_attempts, err
+}
+
 const revokeActiveAppApiKeys = `-- name: RevokeActiveAppApiKeys :execrows
 UPDATE core.app_api_keys 
-SET is_active = false, revoked_at = $2, updated_at = now() 
-WHERE app_id = $1 AND is_active = true
+SET revoked_at = $1, is_active = $1 > now(), updated_at = now() 
+WHERE app_id = $2 AND is_active = true AND (revoked_at IS NULL OR revoked_at > $1)
+    AND ($3::VARCHAR IS NULL OR kind = $3)
 `
 
 type RevokeActiveAppApiKeysParams struct {
-	AppID     uuid.UUID          `json:"app_id"`
 	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	AppID     uuid.UUID          `json:"app_id"`
+	Kind      *string            `json:"kind"`
 }
 
 func (q *Queries) RevokeActiveAppApiKeys(ctx context.Context, arg RevokeActiveAppApiKeysParams) (int64, error) {
-	result, err := q.db.Exec(ctx, revokeActiveAppApiKeys, arg.AppID, arg.RevokedAt)
+	result, err := q.db.Exec(ctx, revokeActiveAppApiKeys, arg.RevokedAt, arg.AppID, arg.Kind)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const revokeAppRefreshTokens = `-- name: RevokeAppRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND is_active = true
+`
+
+func (q *Queries) RevokeAppRefreshTokens(ctx context.Context, appID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, revokeAppRefreshTokens, appID)
+	return err
+}
+
+const revokeDeviceRefreshTokens = `-- name: RevokeDeviceRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND device_id = $3 AND is_active = true
+`
+
+type RevokeDeviceRefreshTokensParams struct {
+	AppID    uuid.UUID `json:"app_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	DeviceID string    `json:"device_id"`
+}
+
+func (q *Queries) RevokeDeviceRefreshTokens(ctx context.Context, arg RevokeDeviceRefreshTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeDeviceRefreshTokens, arg.AppID, arg.UserID, arg.DeviceID)
+	return err
+}
+
+const revokeEmailVerificationToken = `-- name: RevokeEmailVerificationToken :exec
+UPDATE core.email_verification_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND is_active = true
+`
+
+type RevokeEmailVerificationTokenParams struct {
+	AppID  uuid.UUID `json:"app_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeEmailVerificationToken(ctx context.Context, arg RevokeEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeEmailVerificationToken, arg.AppID, arg.UserID)
+	return err
+}
+
+const revokeMagicLinkToken = `-- name: RevokeMagicLinkToken :exec
+UPDATE core.magic_link_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND is_active = true
+`
+
+type RevokeMagicLinkTokenParams struct {
+	AppID  uuid.UUID `json:"app_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokeMagicLinkToken(ctx context.Context, arg RevokeMagicLinkTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeMagicLinkToken, arg.AppID, arg.UserID)
+	return err
+}
+
+const revokeOtherRefreshTokens = `-- name: RevokeOtherRefreshTokens :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND jti <> $3 AND is_active = true
+`
+
+type RevokeOtherRefreshTokensParams struct {
+	AppID  uuid.UUID `json:"app_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Jti    string    `json:"jti"`
+}
+
+func (q *Queries) RevokeOtherRefreshTokens(ctx context.Context, arg RevokeOtherRefreshTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeOtherRefreshTokens, arg.AppID, arg.UserID, arg.Jti)
+	return err
+}
+
+const revokeRefreshTokenByJTI = `-- name: RevokeRefreshTokenByJTI :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true
+`
+
+type RevokeRefreshTokenByJTIParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) RevokeRefreshTokenByJTI(ctx context.Context, arg RevokeRefreshTokenByJTIParams) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenByJTI, arg.AppID, arg.Jti)
+	return err
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND family_id = $2 AND is_active = true
+`
+
+type RevokeRefreshTokenFamilyParams struct {
+	AppID    uuid.UUID `json:"app_id"`
+	FamilyID string    `json:"family_id"`
+}
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, arg.AppID, arg.FamilyID)
+	return err
+}
+
 const revokeRefreshTokens = `-- name: RevokeRefreshTokens :exec
 UPDATE core.refresh_tokens 
 SET is_active = false, updated_at = now()
@@ -399,55 +1302,322 @@ func (q *Queries) RevokeResetPasswordToken(ctx context.Context, arg RevokeResetP
 	return err
 }
 
+const revokeUserRefreshTokenByJTI = `-- name: RevokeUserRefreshTokenByJTI :execrows
+UPDATE core.refresh_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND user_id = $2 AND jti = $3 AND is_active = true
+`
+
+type RevokeUserRefreshTokenByJTIParams struct {
+	AppID  uuid.UUID `json:"app_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Jti    string    `json:"jti"`
+}
+
+func (q *Queries) RevokeUserRefreshTokenByJTI(ctx context.Context, arg RevokeUserRefreshTokenByJTIParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserRefreshTokenByJTI, arg.AppID, arg.UserID, arg.Jti)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :execrows
+UPDATE core.refresh_tokens
+SET is_active = false, rotated_at = now(), updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true
+`
+
+type RotateRefreshTokenParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rotateRefreshToken, arg.AppID, arg.Jti)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteApp = `-- name: SoftDeleteApp :execrows
+UPDATE core.apps
+SET deleted_at = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeleteAppParams struct {
+	ID        uuid.UUID          `json:"id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) SoftDeleteApp(ctx context.Context, arg SoftDeleteAppParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteApp, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const storeAllowedOrigin = `-- name: StoreAllowedOrigin :exec
+INSERT INTO core.app_allowed_origins (id, app_id, origin)
+VALUES ($1, $2, $3)
+`
+
+type StoreAllowedOriginParams struct {
+	ID     uuid.UUID `json:"id"`
+	AppID  uuid.UUID `json:"app_id"`
+	Origin string    `json:"origin"`
+}
+
+func (q *Queries) StoreAllowedOrigin(ctx context.Context, arg StoreAllowedOriginParams) error {
+	_, err := q.db.Exec(ctx, storeAllowedOrigin, arg.ID, arg.AppID, arg.Origin)
+	return err
+}
+
 const storeApp = `-- name: StoreApp :exec
-INSERT INTO core.apps (id, name) 
-VALUES ($1, $2)
+INSERT INTO core.apps (id, name, granted_scopes) 
+VALUES ($1, $2, $3)
 `
 
 type StoreAppParams struct {
-	ID   uuid.UUID `json:"id"`
-	Name []byte    `json:"name"`
+	ID            uuid.UUID `json:"id"`
+	Name          []byte    `json:"name"`
+	GrantedScopes []string  `json:"granted_scopes"`
 }
 
 func (q *Queries) StoreApp(ctx context.Context, arg StoreAppParams) error {
-	_, err := q.db.Exec(ctx, storeApp, arg.ID, arg.Name)
+	_, err := q.db.Exec(ctx, storeApp, arg.ID, arg.Name, arg.GrantedScopes)
 	return err
 }
 
 const storeAppApiKey = `-- name: StoreAppApiKey :exec
-INSERT INTO core.app_api_keys (id, app_id, key_hash, is_active) 
-VALUES ($1, $2, $3, $4)
+INSERT INTO core.app_api_keys (id, app_id, key_id, key_hash, is_active, kind) 
+VALUES ($1, $2, $3, $4, $5, $6)
 `
 
 type StoreAppApiKeyParams struct {
 	ID       uuid.UUID `json:"id"`
 	AppID    uuid.UUID `json:"app_id"`
+	KeyID    *string   `json:"key_id"`
 	KeyHash  string    `json:"key_hash"`
 	IsActive bool      `json:"is_active"`
+	Kind     string    `json:"kind"`
 }
 
 func (q *Queries) StoreAppApiKey(ctx context.Context, arg StoreAppApiKeyParams) error {
 	_, err := q.db.Exec(ctx, storeAppApiKey,
 		arg.ID,
 		arg.AppID,
+		arg.KeyID,
 		arg.KeyHash,
 		arg.IsActive,
+		arg.Kind,
 	)
 	return err
 }
 
-const storeRefreshToken = `-- name: StoreRefreshToken :exec
-INSERT INTO core.refresh_tokens (jti, user_id, app_id, token, expires_at, is_active) 
-VALUES ($1, $2, $3, $4, $5, $6)
+const storeAuthorizationCode = `-- name: StoreAuthorizationCode :exec
+INSERT INTO core.authorization_codes (code_hash, app_id, user_id, redirect_uri, code_challenge, code_challenge_method, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
-type StoreRefreshTokenParams struct {
+type StoreAuthorizationCodeParams struct {
+	CodeHash            string    `json:"code_hash"`
+	AppID               uuid.UUID `json:"app_id"`
+	UserID              uuid.UUID `json:"user_id"`
+	RedirectUri         string    `json:"redirect_uri"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	ExpiresAt           time.Time `json:"expires_at"`
+}
+
+func (q *Queries) StoreAuthorizationCode(ctx context.Context, arg StoreAuthorizationCodeParams) error {
+	_, err := q.db.Exec(ctx, storeAuthorizationCode,
+		arg.CodeHash,
+		arg.AppID,
+		arg.UserID,
+		arg.RedirectUri,
+		arg.CodeChallenge,
+		arg.CodeChallengeMethod,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const storeBlacklistToken = `-- name: StoreBlacklistToken :exec
+INSERT INTO core.blacklist_tokens (jti, token, expires_at, reason)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (jti) DO NOTHING
+`
+
+type StoreBlacklistTokenParams struct {
+	Jti       string    `json:"jti"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    *string   `json:"reason"`
+}
+
+func (q *Queries) StoreBlacklistToken(ctx context.Context, arg StoreBlacklistTokenParams) error {
+	_, err := q.db.Exec(ctx, storeBlacklistToken,
+		arg.Jti,
+		arg.Token,
+		arg.ExpiresAt,
+		arg.Reason,
+	)
+	return err
+}
+
+const storeEmailVerificationToken = `-- name: StoreEmailVerificationToken :exec
+INSERT INTO core.email_verification_tokens (jti, user_id, app_id, token, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type StoreEmailVerificationTokenParams struct {
 	Jti       string    `json:"jti"`
 	UserID    uuid.UUID `json:"user_id"`
 	AppID     uuid.UUID `json:"app_id"`
 	Token     string    `json:"token"`
 	ExpiresAt time.Time `json:"expires_at"`
-	IsActive  bool      `json:"is_active"`
+}
+
+func (q *Queries) StoreEmailVerificationToken(ctx context.Context, arg StoreEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, storeEmailVerificationToken,
+		arg.Jti,
+		arg.UserID,
+		arg.AppID,
+		arg.Token,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const storeFederationState = `-- name: StoreFederationState :exec
+INSERT INTO core.federation_states (state, app_id, provider, nonce, code_verifier, response_type, callback_url, redirect_url, expires_at, set_cookie, cookie_domain)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+type StoreFederationStateParams struct {
+	State        string    `json:"state"`
+	AppID        uuid.UUID `json:"app_id"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ResponseType string    `json:"response_type"`
+	CallbackUrl  *string   `json:"callback_url"`
+	RedirectUrl  *string   `json:"redirect_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	SetCookie    bool      `json:"set_cookie"`
+	CookieDomain *string   `json:"cookie_domain"`
+}
+
+func (q *Queries) StoreFederationState(ctx context.Context, arg StoreFederationStateParams) error {
+	_, err := q.db.Exec(ctx, storeFederationState,
+		arg.State,
+		arg.AppID,
+		arg.Provider,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.ResponseType,
+		arg.CallbackUrl,
+		arg.RedirectUrl,
+		arg.ExpiresAt,
+		arg.SetCookie,
+		arg.CookieDomain,
+	)
+	return err
+}
+
+const storeIdentityProvider = `-- name: StoreIdentityProvider :exec
+INSERT INTO core.app_identity_providers (app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, trust_email)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type StoreIdentityProviderParams struct {
+	AppID         uuid.UUID `json:"app_id"`
+	Provider      string    `json:"provider"`
+	Issuer        string    `json:"issuer"`
+	ClientID      string    `json:"client_id"`
+	ClientSecret  []byte    `json:"client_secret"`
+	Scopes        []string  `json:"scopes"`
+	ClaimMappings []byte    `json:"claim_mappings"`
+	TrustEmail    bool      `json:"trust_email"`
+}
+
+func (q *Queries) StoreIdentityProvider(ctx context.Context, arg StoreIdentityProviderParams) error {
+	_, err := q.db.Exec(ctx, storeIdentityProvider,
+		arg.AppID,
+		arg.Provider,
+		arg.Issuer,
+		arg.ClientID,
+		arg.ClientSecret,
+		arg.Scopes,
+		arg.ClaimMappings,
+		arg.TrustEmail,
+	)
+	return err
+}
+
+const storeMagicLinkToken = `-- name: StoreMagicLinkToken :exec
+INSERT INTO core.magic_link_tokens (jti, user_id, app_id, token, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type StoreMagicLinkTokenParams struct {
+	Jti       string    `json:"jti"`
+	UserID    uuid.UUID `json:"user_id"`
+	AppID     uuid.UUID `json:"app_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) StoreMagicLinkToken(ctx context.Context, arg StoreMagicLinkTokenParams) error {
+	_, err := q.db.Exec(ctx, storeMagicLinkToken,
+		arg.Jti,
+		arg.UserID,
+		arg.AppID,
+		arg.Token,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const storeRedirectURI = `-- name: StoreRedirectURI :exec
+INSERT INTO core.app_redirect_uris (id, app_id, redirect_uri)
+VALUES ($1, $2, $3)
+`
+
+type StoreRedirectURIParams struct {
+	ID          uuid.UUID `json:"id"`
+	AppID       uuid.UUID `json:"app_id"`
+	RedirectUri string    `json:"redirect_uri"`
+}
+
+func (q *Queries) StoreRedirectURI(ctx context.Context, arg StoreRedirectURIParams) error {
+	_, err := q.db.Exec(ctx, storeRedirectURI, arg.ID, arg.AppID, arg.RedirectUri)
+	return err
+}
+
+const storeRefreshToken = `-- name: StoreRefreshToken :exec
+INSERT INTO core.refresh_tokens (jti, user_id, app_id, token, expires_at, is_active, family_id, parent_jti, device_id, device_name, ip_address, user_agent, created_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+`
+
+type StoreRefreshTokenParams struct {
+	Jti        string    `json:"jti"`
+	UserID     uuid.UUID `json:"user_id"`
+	AppID      uuid.UUID `json:"app_id"`
+	Token      string    `json:"token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsActive   bool      `json:"is_active"`
+	FamilyID   string    `json:"family_id"`
+	ParentJti  *string   `json:"parent_jti"`
+	DeviceID   string    `json:"device_id"`
+	DeviceName *string   `json:"device_name"`
+	IpAddress  *string   `json:"ip_address"`
+	UserAgent  *string   `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 func (q *Queries) StoreRefreshToken(ctx context.Context, arg StoreRefreshTokenParams) error {
@@ -458,6 +1628,13 @@ func (q *Queries) StoreRefreshToken(ctx context.Context, arg StoreRefreshTokenPa
 		arg.Token,
 		arg.ExpiresAt,
 		arg.IsActive,
+		arg.FamilyID,
+		arg.ParentJti,
+		arg.DeviceID,
+		arg.DeviceName,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.CreatedAt,
 	)
 	return err
 }
@@ -535,3 +1712,235 @@ func (q *Queries) StoreUserAuthProvider(ctx context.Context, arg StoreUserAuthPr
 	)
 	return err
 }
+
+const touchAppApiKey = `-- name: TouchAppApiKey :exec
+UPDATE core.app_api_keys
+SET last_used_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchAppApiKey(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, touchAppApiKey, id)
+	return err
+}
+
+const updateAppGrantedScopes = `-- name: UpdateAppGrantedScopes :execrows
+UPDATE core.apps
+SET granted_scopes = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateAppGrantedScopesParams struct {
+	ID            uuid.UUID `json:"id"`
+	GrantedScopes []string  `json:"granted_scopes"`
+}
+
+func (q *Queries) UpdateAppGrantedScopes(ctx context.Context, arg UpdateAppGrantedScopesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAppGrantedScopes, arg.ID, arg.GrantedScopes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateAppName = `-- name: UpdateAppName :execrows
+UPDATE core.apps
+SET name = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateAppNameParams struct {
+	ID   uuid.UUID `json:"id"`
+	Name []byte    `json:"name"`
+}
+
+func (q *Queries) UpdateAppName(ctx context.Context, arg UpdateAppNameParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAppName, arg.ID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserAuthProviderPassword = `-- name: UpdateUserAuthProviderPassword :execrows
+UPDATE core.user_auth_providers
+SET password = $1, updated_at = now()
+WHERE app_id = $2 AND user_id = $3 AND provider = $4
+`
+
+type UpdateUserAuthProviderPasswordParams struct {
+	Password *string   `json:"password"`
+	AppID    uuid.UUID `json:"app_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Provider string    `json:"provider"`
+}
+
+func (q *Queries) UpdateUserAuthProviderPassword(ctx context.Context, arg UpdateUserAuthProviderPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserAuthProviderPassword,
+		arg.Password,
+		arg.AppID,
+		arg.UserID,
+		arg.Provider,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertAppSettings = `-- name: UpsertAppSettings :exec
+INSERT INTO core.app_settings (
+    app_id, access_token_ttl_seconds, refresh_token_ttl_seconds, session_lifetime_seconds,
+    password_min_length, password_require_uppercase, password_require_lowercase,
+    password_require_digit, password_require_symbol, max_sessions, require_email_verification,
+    google_client_ids
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (app_id) DO UPDATE
+SET access_token_ttl_seconds = EXCLUDED.access_token_ttl_seconds,
+    refresh_token_ttl_seconds = EXCLUDED.refresh_token_ttl_seconds,
+    session_lifetime_seconds = EXCLUDED.session_lifetime_seconds,
+    password_min_length = EXCLUDED.password_min_length,
+    password_require_uppercase = EXCLUDED.password_require_uppercase,
+    password_require_lowercase = EXCLUDED.password_require_lowercase,
+    password_require_digit = EXCLUDED.password_require_digit,
+    password_require_symbol = EXCLUDED.password_require_symbol,
+    max_sessions = EXCLUDED.max_sessions,
+    require_email_verification = EXCLUDED.require_email_verification,
+    google_client_ids = EXCLUDED.google_client_ids,
+    updated_at = now()
+`
+
+type UpsertAppSettingsParams struct {
+	AppID                    uuid.UUID `json:"app_id"`
+	AccessTokenTtlSeconds    int32     `json:"access_token_ttl_seconds"`
+	RefreshTokenTtlSeconds   int32     `json:"refresh_token_ttl_seconds"`
+	SessionLifetimeSeconds   int32     `json:"session_lifetime_seconds"`
+	PasswordMinLength        int32     `json:"password_min_length"`
+	PasswordRequireUppercase bool      `json:"password_require_uppercase"`
+	PasswordRequireLowercase bool      `json:"password_require_lowercase"`
+	PasswordRequireDigit     bool      `json:"password_require_digit"`
+	PasswordRequireSymbol    bool      `json:"password_require_symbol"`
+	MaxSessions              int32     `json:"max_sessions"`
+	RequireEmailVerification bool      `json:"require_email_verification"`
+	GoogleClientIds          []string  `json:"google_client_ids"`
+}
+
+func (q *Queries) UpsertAppSettings(ctx context.Context, arg UpsertAppSettingsParams) error {
+	_, err := q.db.Exec(ctx, upsertAppSettings,
+		arg.AppID,
+		arg.AccessTokenTtlSeconds,
+		arg.RefreshTokenTtlSeconds,
+		arg.SessionLifetimeSeconds,
+		arg.PasswordMinLength,
+		arg.PasswordRequireUppercase,
+		arg.PasswordRequireLowercase,
+		arg.PasswordRequireDigit,
+		arg.PasswordRequireSymbol,
+		arg.MaxSessions,
+		arg.RequireEmailVerification,
+		arg.GoogleClientIds,
+	)
+	return err
+}
+
+const upsertSigningKey = `-- name: UpsertSigningKey :exec
+INSERT INTO core.signing_keys (kid, algorithm, private_key, public_key, activates_at, retires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (kid) DO UPDATE
+SET activates_at = EXCLUDED.activates_at, retires_at = EXCLUDED.retires_at, updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertSigningKeyParams struct {
+	Kid         string             `json:"kid"`
+	Algorithm   string             `json:"algorithm"`
+	PrivateKey  []byte             `json:"private_key"`
+	PublicKey   string             `json:"public_key"`
+	ActivatesAt pgtype.Timestamptz `json:"activates_at"`
+	RetiresAt   pgtype.Timestamptz `json:"retires_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (q *Queries) UpsertSigningKey(ctx context.Context, arg UpsertSigningKeyParams) error {
+	_, err := q.db.Exec(ctx, upsertSigningKey,
+		arg.Kid,
+		arg.Algorithm,
+		arg.PrivateKey,
+		arg.PublicKey,
+		arg.ActivatesAt,
+		arg.RetiresAt,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const useAuthorizationCode = `-- name: UseAuthorizationCode :execrows
+UPDATE core.authorization_codes
+SET used_at = now()
+WHERE code_hash = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UseAuthorizationCode(ctx context.Context, codeHash string) (int64, error) {
+	result, err := q.db.Exec(ctx, useAuthorizationCode, codeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useEmailVerificationToken = `-- name: UseEmailVerificationToken :execrows
+UPDATE core.email_verification_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now()
+`
+
+type UseEmailVerificationTokenParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) UseEmailVerificationToken(ctx context.Context, arg UseEmailVerificationTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useEmailVerificationToken, arg.AppID, arg.Jti)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useMagicLinkToken = `-- name: UseMagicLinkToken :execrows
+UPDATE core.magic_link_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now()
+`
+
+type UseMagicLinkTokenParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) UseMagicLinkToken(ctx context.Context, arg UseMagicLinkTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useMagicLinkToken, arg.AppID, arg.Jti)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useResetPasswordToken = `-- name: UseResetPasswordToken :execrows
+UPDATE core.reset_password_tokens
+SET is_active = false, updated_at = now()
+WHERE app_id = $1 AND jti = $2 AND is_active = true AND expires_at > now()
+`
+
+type UseResetPasswordTokenParams struct {
+	AppID uuid.UUID `json:"app_id"`
+	Jti   string    `json:"jti"`
+}
+
+func (q *Queries) UseResetPasswordToken(ctx context.Context, arg UseResetPasswordTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useResetPasswordToken, arg.AppID, arg.Jti)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,204 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/fransiscushermanto/backend/internal/services"
+	federationTypes "github.com/fransiscushermanto/backend/internal/services/federation"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+)
+
+type FederationRepository struct {
+	db      *utils.Database
+	queries *db.Queries
+}
+
+func NewFederationRepository(database *utils.Database) *FederationRepository {
+	return &FederationRepository{
+		db:      database,
+		queries: db.New(database.Pool),
+	}
+}
+
+var _ services.FederationRepository = (*FederationRepository)(nil)
+
+func federationLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("repository", "Federation").Str("method", method).Logger()
+	return &l
+}
+
+func (r *FederationRepository) StoreIdentityProvider(ctx context.Context, provider *models.IdentityProvider) error {
+	log := federationLog("StoreIdentityProvider")
+
+	claimMappings, err := json.Marshal(provider.ClaimMappings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal claim mappings: %w", err)
+	}
+
+	err = r.queries.StoreIdentityProvider(ctx, db.StoreIdentityProviderParams{
+		AppID:         provider.AppID,
+		Provider:      provider.Provider,
+		Issuer:        provider.Issuer,
+		ClientID:      provider.ClientID,
+		ClientSecret:  provider.ClientSecret,
+		Scopes:        provider.Scopes,
+		ClaimMappings: claimMappings,
+		TrustEmail:    provider.TrustEmail,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return federationTypes.ErrIdentityProviderExists
+		}
+
+		log.Error().Err(err).Str("app_id", provider.AppID.String()).Str("provider", provider.Provider).Msg("Failed to insert identity provider into DB")
+		return fmt.Errorf("failed to insert identity provider: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FederationRepository) GetIdentityProvider(ctx context.Context, appID uuid.UUID, provider string) (*models.IdentityProvider, error) {
+	log := federationLog("GetIdentityProvider")
+
+	dbProvider, err := r.queries.GetIdentityProvider(ctx, db.GetIdentityProviderParams{
+		AppID:    appID,
+		Provider: provider,
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("app_id", appID.String()).Str("provider", provider).Msg("Failed to query identity provider")
+		return nil, fmt.Errorf("failed to get identity provider: %w", err)
+	}
+
+	return toIdentityProvider(dbProvider)
+}
+
+func (r *FederationRepository) GetIdentityProvidersByAppID(ctx context.Context, appID uuid.UUID) ([]*models.IdentityProvider, error) {
+	log := federationLog("GetIdentityProvidersByAppID")
+
+	dbProviders, err := r.queries.GetIdentityProvidersByAppID(ctx, appID)
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query identity providers")
+		return nil, fmt.Errorf("failed to get identity providers: %w", err)
+	}
+
+	providers := make([]*models.IdentityProvider, len(dbProviders))
+
+	for i, dbProvider := range dbProviders {
+		provider, err := toIdentityProvider(dbProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[i] = provider
+	}
+
+	return providers, nil
+}
+
+func (r *FederationRepository) DeleteIdentityProvider(ctx context.Context, appID uuid.UUID, provider string) (bool, error) {
+	log := federationLog("DeleteIdentityProvider")
+
+	deletedProviders, err := r.queries.DeleteIdentityProvider(ctx, db.DeleteIdentityProviderParams{
+		AppID:    appID,
+		Provider: provider,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("provider", provider).Msg("Failed to delete identity provider")
+		return false, fmt.Errorf("failed to delete identity provider: %w", err)
+	}
+
+	return deletedProviders > 0, nil
+}
+
+func (r *FederationRepository) StoreFederationState(ctx context.Context, state *models.FederationState) error {
+	log := federationLog("StoreFederationState")
+
+	err := r.queries.StoreFederationState(ctx, db.StoreFederationStateParams{
+		State:        state.State,
+		AppID:        state.AppID,
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ResponseType: string(state.ResponseType),
+		CallbackUrl:  state.CallbackURL,
+		RedirectUrl:  state.RedirectURL,
+		ExpiresAt:    state.ExpiresAt,
+		SetCookie:    state.SetCookie,
+		CookieDomain: state.CookieDomain,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("provider", state.Provider).Msg("Failed to insert federation state into DB")
+		return fmt.Errorf("failed to insert federation state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FederationRepository) ConsumeFederationState(ctx context.Context, state string) (*models.FederationState, error) {
+	log := federationLog("ConsumeFederationState")
+
+	dbState, err := r.queries.ConsumeFederationState(ctx, state)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Msg("Failed to consume federation state")
+		return nil, fmt.Errorf("failed to consume federation state: %w", err)
+	}
+
+	return &models.FederationState{
+		State:        dbState.State,
+		AppID:        dbState.AppID,
+		Provider:     dbState.Provider,
+		Nonce:        dbState.Nonce,
+		CodeVerifier: dbState.CodeVerifier,
+		ResponseType: models.AuthResponseType(dbState.ResponseType),
+		CallbackURL:  dbState.CallbackUrl,
+		RedirectURL:  dbState.RedirectUrl,
+		SetCookie:    dbState.SetCookie,
+		CookieDomain: dbState.CookieDomain,
+		CreatedAt:    dbState.CreatedAt,
+		ExpiresAt:    dbState.ExpiresAt,
+	}, nil
+}
+
+func toIdentityProvider(dbProvider db.CoreAppIdentityProvider) (*models.IdentityProvider, error) {
+	provider := &models.IdentityProvider{
+		AppID:        dbProvider.AppID,
+		Provider:     dbProvider.Provider,
+		Issuer:       dbProvider.Issuer,
+		ClientID:     dbProvider.ClientID,
+		ClientSecret: dbProvider.ClientSecret,
+		Scopes:       dbProvider.Scopes,
+		TrustEmail:   dbProvider.TrustEmail,
+		CreatedAt:    dbProvider.CreatedAt,
+		UpdatedAt:    dbProvider.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(dbProvider.ClaimMappings, &provider.ClaimMappings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal claim mappings: %w", err)
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,27 @@
+-- name: StoreIdentityProvider :exec
+INSERT INTO core.app_identity_providers (app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, trust_email)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetIdentityProvider :one
+SELECT app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, created_at, updated_at, trust_email
+FROM core.app_identity_providers
+WHERE app_id = $1 AND provider = $2;
+
+-- name: GetIdentityProvidersByAppID :many
+SELECT app_id, provider, issuer, client_id, client_secret, scopes, claim_mappings, created_at, updated_at, trust_email
+FROM core.app_identity_providers
+WHERE app_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteIdentityProvider :execrows
+DELETE FROM core.app_identity_providers
+WHERE app_id = $1 AND provider = $2;
+
+-- name: StoreFederationState :exec
+INSERT INTO core.federation_states (state, app_id, provider, nonce, code_verifier, response_type, callback_url, redirect_url, expires_at, set_cookie, cookie_domain)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: ConsumeFederationState :one
+DELETE FROM core.federation_states
+WHERE state = $1 AND expires_at > now()
+RETURNING state, app_id, provider, nonce, code_verifier, response_type, callback_url, redirect_url, created_at, expires_at, set_cookie, cookie_domain;
\ No newline at end of file
@@ -3,6 +3,10 @@ package repositories
 import (
 	"github.com/fransiscushermanto/backend/internal/repositories/app"
 	"github.com/fransiscushermanto/backend/internal/repositories/auth"
+	"github.com/fransiscushermanto/backend/internal/repositories/federation"
+	"github.com/fransiscushermanto/backend/internal/repositories/oauth"
+	"github.com/fransiscushermanto/backend/internal/repositories/ratelimit"
+	"github.com/fransiscushermanto/backend/internal/repositories/signingkey"
 	"github.com/fransiscushermanto/backend/internal/repositories/user"
 	"github.com/fransiscushermanto/backend/internal/utils"
 )
@@ -22,3 +26,19 @@ func NewAuthRepository(database *utils.Database) *auth.AuthRepository {
 func NewUserRepository(database *utils.Database) *user.UserRepository {
 	return user.NewUserRepository(database)
 }
+
+func NewFederationRepository(database *utils.Database) *federation.FederationRepository {
+	return federation.NewFederationRepository(database)
+}
+
+func NewOAuthRepository(database *utils.Database) *oauth.OAuthRepository {
+	return oauth.NewOAuthRepository(database)
+}
+
+func NewSigningKeyRepository(database *utils.Database, secretKey string) *signingkey.SigningKeyRepository {
+	return signingkey.NewSigningKeyRepository(database, secretKey)
+}
+
+func NewRateLimitRepository(database *utils.Database) *ratelimit.RateLimitRepository {
+	return ratelimit.NewRateLimitRepository(database)
+}
@@ -0,0 +1,176 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/fransiscushermanto/backend/internal/services"
+	oauthTypes "github.com/fransiscushermanto/backend/internal/services/oauth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+)
+
+type OAuthRepository struct {
+	db      *utils.Database
+	queries *db.Queries
+}
+
+func NewOAuthRepository(database *utils.Database) *OAuthRepository {
+	return &OAuthRepository{
+		db:      database,
+		queries: db.New(database.Pool),
+	}
+}
+
+var _ services.OAuthRepository = (*OAuthRepository)(nil)
+
+func oauthLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("repository", "OAuth").Str("method", method).Logger()
+	return &l
+}
+
+func (r *OAuthRepository) StoreRedirectURI(ctx context.Context, redirectURI *models.AppRedirectURI) error {
+	log := oauthLog("StoreRedirectURI")
+
+	err := r.queries.StoreRedirectURI(ctx, db.StoreRedirectURIParams{
+		ID:          redirectURI.ID,
+		AppID:       redirectURI.AppID,
+		RedirectUri: redirectURI.RedirectURI,
+	})
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return oauthTypes.ErrRedirectURIExists
+		}
+
+		log.Error().Err(err).Str("app_id", redirectURI.AppID.String()).Msg("Failed to insert redirect uri into DB")
+		return fmt.Errorf("failed to insert redirect uri: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OAuthRepository) GetRedirectURIsByAppID(ctx context.Context, appID uuid.UUID) ([]*models.AppRedirectURI, error) {
+	log := oauthLog("GetRedirectURIsByAppID")
+
+	dbRedirectURIs, err := r.queries.GetRedirectURIsByAppID(ctx, appID)
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to query redirect uris")
+		return nil, fmt.Errorf("failed to get redirect uris: %w", err)
+	}
+
+	redirectURIs := make([]*models.AppRedirectURI, len(dbRedirectURIs))
+
+	for i, dbRedirectURI := range dbRedirectURIs {
+		redirectURIs[i] = &models.AppRedirectURI{
+			ID:          dbRedirectURI.ID,
+			AppID:       dbRedirectURI.AppID,
+			RedirectURI: dbRedirectURI.RedirectUri,
+			CreatedAt:   dbRedirectURI.CreatedAt,
+			UpdatedAt:   dbRedirectURI.UpdatedAt,
+		}
+	}
+
+	return redirectURIs, nil
+}
+
+func (r *OAuthRepository) IsRedirectURIRegistered(ctx context.Context, appID uuid.UUID, redirectURI string) (bool, error) {
+	log := oauthLog("IsRedirectURIRegistered")
+
+	registered, err := r.queries.IsRedirectURIRegistered(ctx, db.IsRedirectURIRegisteredParams{
+		AppID:       appID,
+		RedirectUri: redirectURI,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to check redirect uri")
+		return false, fmt.Errorf("failed to check redirect uri: %w", err)
+	}
+
+	return registered, nil
+}
+
+func (r *OAuthRepository) DeleteRedirectURI(ctx context.Context, appID, id uuid.UUID) (bool, error) {
+	log := oauthLog("DeleteRedirectURI")
+
+	deletedRedirectURIs, err := r.queries.DeleteRedirectURI(ctx, db.DeleteRedirectURIParams{
+		AppID: appID,
+		ID:    id,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", appID.String()).Str("id", id.String()).Msg("Failed to delete redirect uri")
+		return false, fmt.Errorf("failed to delete redirect uri: %w", err)
+	}
+
+	return deletedRedirectURIs > 0, nil
+}
+
+func (r *OAuthRepository) StoreAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error {
+	log := oauthLog("StoreAuthorizationCode")
+
+	err := r.queries.StoreAuthorizationCode(ctx, db.StoreAuthorizationCodeParams{
+		CodeHash:            code.CodeHash,
+		AppID:               code.AppID,
+		UserID:              code.UserID,
+		RedirectUri:         code.RedirectURI,
+		CodeChallenge:       code.CodeChallenge,
+		CodeChallengeMethod: code.CodeChallengeMethod,
+		ExpiresAt:           code.ExpiresAt,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("app_id", code.AppID.String()).Str("user_id", code.UserID.String()).Msg("Failed to insert authorization code into DB")
+		return fmt.Errorf("failed to insert authorization code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OAuthRepository) GetAuthorizationCode(ctx context.Context, codeHash string) (*models.AuthorizationCode, error) {
+	log := oauthLog("GetAuthorizationCode")
+
+	dbCode, err := r.queries.GetAuthorizationCode(ctx, codeHash)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Msg("Failed to query authorization code")
+		return nil, fmt.Errorf("failed to get authorization code: %w", err)
+	}
+
+	return &models.AuthorizationCode{
+		CodeHash:            dbCode.CodeHash,
+		AppID:               dbCode.AppID,
+		UserID:              dbCode.UserID,
+		RedirectURI:         dbCode.RedirectUri,
+		CodeChallenge:       dbCode.CodeChallenge,
+		CodeChallengeMethod: dbCode.CodeChallengeMethod,
+		CreatedAt:           dbCode.CreatedAt,
+		ExpiresAt:           dbCode.ExpiresAt,
+		UsedAt:              utils.FromPgTimestampPtr(dbCode.UsedAt),
+	}, nil
+}
+
+func (r *OAuthRepository) UseAuthorizationCode(ctx context.Context, codeHash string) (bool, error) {
+	log := oauthLog("UseAuthorizationCode")
+
+	usedCodes, err := r.queries.UseAuthorizationCode(ctx, codeHash)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to mark authorization code as used")
+		return false, fmt.Errorf("failed to use authorization code: %w", err)
+	}
+
+	return usedCodes > 0, nil
+}
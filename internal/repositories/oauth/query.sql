@@ -0,0 +1,33 @@
+-- name: StoreRedirectURI :exec
+INSERT INTO core.app_redirect_uris (id, app_id, redirect_uri)
+VALUES ($1, $2, $3);
+
+-- name: GetRedirectURIsByAppID :many
+SELECT id, app_id, redirect_uri, created_at, updated_at
+FROM core.app_redirect_uris
+WHERE app_id = $1
+ORDER BY created_at ASC;
+
+-- name: IsRedirectURIRegistered :one
+SELECT EXISTS (
+    SELECT 1 FROM core.app_redirect_uris
+    WHERE app_id = $1 AND redirect_uri = $2
+);
+
+-- name: DeleteRedirectURI :execrows
+DELETE FROM core.app_redirect_uris
+WHERE app_id = $1 AND id = $2;
+
+-- name: StoreAuthorizationCode :exec
+INSERT INTO core.authorization_codes (code_hash, app_id, user_id, redirect_uri, code_challenge, code_challenge_method, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetAuthorizationCode :one
+SELECT code_hash, app_id, user_id, redirect_uri, code_challenge, code_challenge_method, created_at, expires_at, used_at
+FROM core.authorization_codes
+WHERE code_hash = $1;
+
+-- name: UseAuthorizationCode :execrows
+UPDATE core.authorization_codes
+SET used_at = now()
+WHERE code_hash = $1 AND used_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/ratelimit"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+type RateLimitRepository struct {
+	db      *utils.Database
+	queries *db.Queries
+}
+
+func NewRateLimitRepository(database *utils.Database) *RateLimitRepository {
+	return &RateLimitRepository{
+		db:      database,
+		queries: db.New(database.Pool),
+	}
+}
+
+var _ ratelimit.Store = (*RateLimitRepository)(nil)
+
+func rateLimitLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("repository", "RateLimit").Str("method", method).Logger()
+	return &l
+}
+
+func (r *RateLimitRepository) Hit(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int, int, error) {
+	row, err := r.queries.HitRateLimitCounter(ctx, db.HitRateLimitCounterParams{
+		Key:                 key,
+		WindowStart:         windowStart,
+		PreviousWindowStart: windowStart.Add(-window),
+	})
+
+	if err != nil {
+		rateLimitLog("Hit").Error().Err(err).Str("key", key).Msg("Failed to count rate limit hit")
+		return 0, 0, fmt.Errorf("failed to count rate limit hit: %w", err)
+	}
+
+	return int(row.CurrentHits), int(row.PreviousHits), nil
+}
+
+func (r *RateLimitRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := r.queries.PurgeRateLimitCounters(ctx, before)
+
+	if err != nil {
+		rateLimitLog("Purge").Error().Err(err).Msg("Failed to purge rate limit counters")
+		return 0, fmt.Errorf("failed to purge rate limit counters: %w", err)
+	}
+
+	return purged, nil
+}
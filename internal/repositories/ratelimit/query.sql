@@ -0,0 +1,18 @@
+-- name: HitRateLimitCounter :one
+WITH hit AS (
+    INSERT INTO core.rate_limit_counters (key, window_start, hits)
+    VALUES (sqlc.arg(key), sqlc.arg(window_start), 1)
+    ON CONFLICT (key, window_start) DO UPDATE
+    SET hits = core.rate_limit_counters.hits + 1
+    RETURNING hits
+)
+SELECT hit.hits AS current_hits,
+    COALESCE((
+        SELECT previous.hits FROM core.rate_limit_counters previous
+        WHERE previous.key = sqlc.arg(key) AND previous.window_start = sqlc.arg(previous_window_start)
+    ), 0)::INTEGER AS previous_hits
+FROM hit;
+
+-- name: PurgeRateLimitCounters :execrows
+DELETE FROM core.rate_limit_counters
+WHERE window_start < $1;
\ No newline at end of file
@@ -0,0 +1,114 @@
+package signingkey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/repositories/db"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+type SigningKeyRepository struct {
+	db        *utils.Database
+	queries   *db.Queries
+	secretKey []byte
+}
+
+// NewSigningKeyRepository stores private keys encrypted with secretKey.
+func NewSigningKeyRepository(database *utils.Database, secretKey string) *SigningKeyRepository {
+	return &SigningKeyRepository{
+		db:        database,
+		queries:   db.New(database.Pool),
+		secretKey: []byte(secretKey),
+	}
+}
+
+var _ keyring.Store = (*SigningKeyRepository)(nil)
+
+func signingKeyLog(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("repository", "SigningKey").Str("method", method).Logger()
+	return &l
+}
+
+func (r *SigningKeyRepository) Load(ctx context.Context) ([]*keyring.Key, error) {
+	log := signingKeyLog("Load")
+
+	dbKeys, err := r.queries.GetSigningKeys(ctx)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query signing keys")
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	keys := make([]*keyring.Key, len(dbKeys))
+
+	for i, dbKey := range dbKeys {
+		var privateKeyPEM []byte
+
+		if dbKey.PrivateKey != nil {
+			privateKeyPEM, err = utils.Decrypt(r.secretKey, dbKey.PrivateKey)
+			if err != nil {
+				log.Error().Err(err).Str("kid", dbKey.Kid).Msg("Failed to decrypt signing key")
+				return nil, fmt.Errorf("failed to decrypt signing key %s: %w", dbKey.Kid, err)
+			}
+		}
+
+		key, err := keyring.NewKey(dbKey.Kid, string(privateKeyPEM), dbKey.PublicKey)
+		if err != nil {
+			log.Error().Err(err).Str("kid", dbKey.Kid).Msg("Invalid signing key")
+			return nil, fmt.Errorf("invalid signing key %s: %w", dbKey.Kid, err)
+		}
+
+		key.CreatedAt = dbKey.CreatedAt
+		key.ActivatesAt = utils.FromPgTimestampPtr(dbKey.ActivatesAt)
+		key.RetiresAt = utils.FromPgTimestampPtr(dbKey.RetiresAt)
+
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+func (r *SigningKeyRepository) Save(ctx context.Context, key *keyring.Key) error {
+	log := signingKeyLog("Save")
+
+	publicKeyPEM, err := keyring.EncodePublicKey(key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	var privateKey []byte
+
+	if key.PrivateKey != nil {
+		privateKeyPEM, err := keyring.EncodePrivateKey(key.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		privateKey, err = utils.Encrypt(r.secretKey, []byte(privateKeyPEM))
+		if err != nil {
+			log.Error().Err(err).Str("kid", key.Kid).Msg("Failed to encrypt signing key")
+			return fmt.Errorf("failed to encrypt signing key: %w", err)
+		}
+	}
+
+	err = r.queries.UpsertSigningKey(ctx, db.UpsertSigningKeyParams{
+		Kid:         key.Kid,
+		Algorithm:   constants.DEFAULT_JWT_SIGNING_METHOD.Alg(),
+		PrivateKey:  privateKey,
+		PublicKey:   publicKeyPEM,
+		ActivatesAt: utils.ToPgTimestampPtr(key.ActivatesAt),
+		RetiresAt:   utils.ToPgTimestampPtr(key.RetiresAt),
+		CreatedAt:   key.CreatedAt,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("kid", key.Kid).Msg("Failed to save signing key")
+		return fmt.Errorf("failed to save signing key: %w", err)
+	}
+
+	return nil
+}
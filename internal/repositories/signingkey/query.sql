@@ -0,0 +1,10 @@
+-- name: GetSigningKeys :many
+SELECT kid, algorithm, private_key, public_key, activates_at, retires_at, created_at, updated_at
+FROM core.signing_keys
+ORDER BY created_at ASC;
+
+-- name: UpsertSigningKey :exec
+INSERT INTO core.signing_keys (kid, algorithm, private_key, public_key, activates_at, retires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (kid) DO UPDATE
+SET activates_at = EXCLUDED.activates_at, retires_at = EXCLUDED.retires_at, updated_at = CURRENT_TIMESTAMP;
\ No newline at end of file
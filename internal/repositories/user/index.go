@@ -39,22 +39,18 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User, auth
 		qtx := r.queries.WithTx(tx)
 
 		if err := qtx.StoreUser(ctx, db.StoreUserParams{
-			ID:    user.ID,
-			AppID: user.AppID,
-			Name:  user.Name,
-			Email: user.Email,
+			ID:              user.ID,
+			AppID:           user.AppID,
+			Name:            user.Name,
+			Email:           user.Email,
+			IsEmailVerified: user.IsEmailVerified,
+			EmailVerifiedAt: utils.ToPgTimestampPtr(user.EmailVerifiedAt),
 		}); err != nil {
 			log.Error().Err(err).Msg("Failed to insert user into DB")
 			return fmt.Errorf("failed to create user: %w", err)
 		}
 
-		if err := qtx.StoreUserAuthProvider(ctx, db.StoreUserAuthProviderParams{
-			AppID:          auth.AppID,
-			UserID:         auth.UserID,
-			Provider:       string(auth.Provider),
-			ProviderUserID: auth.ProviderUserID,
-			Password:       &auth.Password,
-		}); err != nil {
+		if err := qtx.StoreUserAuthProvider(ctx, storeUserAuthProviderParams(auth)); err != nil {
 			log.Error().Err(err).Msg("Failed to insert user authentication into DB")
 			return fmt.Errorf("failed to create user authentication: %w", err)
 		}
@@ -73,23 +69,26 @@ func (r *UserRepository) GetUserAuthenticationByProvider(ctx context.Context, ap
 		Provider: string(provider),
 	})
 
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("appID", appID.String()).Str("userID", userID.String()).Str("provider", string(provider)).Msg("Failed to query user authentication by provider")
+		return nil, fmt.Errorf("failed to get user authentication: %w", err)
+	}
+
 	userAuth := &models.UserAuthProvider{
-		UserID:         dbUserAuth.AppID,
+		UserID:         dbUserAuth.UserID,
 		AppID:          dbUserAuth.AppID,
 		Provider:       models.AuthProvider(dbUserAuth.Provider),
 		ProviderUserID: dbUserAuth.ProviderUserID,
-		Password:       *dbUserAuth.Password,
 		CreatedAt:      dbUserAuth.CreatedAt,
 		UpdatedAt:      dbUserAuth.UpdatedAt,
 	}
 
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
-		}
-
-		log.Error().Err(err).Str("appID", appID.String()).Str("userID", userID.String()).Str("provider", string(provider)).Msg("Failed to query user authentication by provider")
-		return nil, fmt.Errorf("failed to get user authentication: %w", err)
+	if dbUserAuth.Password != nil {
+		userAuth.Password = *dbUserAuth.Password
 	}
 
 	return userAuth, nil
@@ -201,3 +200,76 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, appID uuid.UUID, em
 
 	return user, nil
 }
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, appID, id uuid.UUID) (bool, error) {
+	log := userLog("MarkEmailVerified")
+
+	updatedUsers, err := r.queries.MarkUserEmailVerified(ctx, db.MarkUserEmailVerifiedParams{
+		AppID: appID,
+		ID:    id,
+	})
+
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("Failed to mark user email as verified")
+		return false, fmt.Errorf("failed to mark user email as verified: %w", err)
+	}
+
+	return updatedUsers > 0, nil
+}
+
+func (r *UserRepository) GetUserByAuthProvider(ctx context.Context, appID uuid.UUID, provider models.AuthProvider, providerUserID string) (*models.User, error) {
+	log := userLog("GetUserByAuthProvider")
+
+	dbUser, err := r.queries.GetUserByAuthProvider(ctx, db.GetUserByAuthProviderParams{
+		AppID:          appID,
+		Provider:       string(provider),
+		ProviderUserID: &providerUserID,
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		log.Error().Err(err).Str("provider", string(provider)).Msg("Failed to query user by auth provider")
+		return nil, fmt.Errorf("failed to get user by auth provider: %w", err)
+	}
+
+	return &models.User{
+		ID:              dbUser.ID,
+		AppID:           dbUser.AppID,
+		Name:            dbUser.Name,
+		Email:           dbUser.Email,
+		IsEmailVerified: dbUser.IsEmailVerified,
+		EmailVerifiedAt: utils.FromPgTimestampPtr(dbUser.EmailVerifiedAt),
+		CreatedAt:       dbUser.CreatedAt,
+		UpdatedAt:       dbUser.UpdatedAt,
+	}, nil
+}
+
+func (r *UserRepository) CreateUserAuthProvider(ctx context.Context, auth *models.UserAuthProvider) error {
+	log := userLog("CreateUserAuthProvider")
+
+	if err := r.queries.StoreUserAuthProvider(ctx, storeUserAuthProviderParams(auth)); err != nil {
+		log.Error().Err(err).Str("provider", string(auth.Provider)).Msg("Failed to insert user authentication into DB")
+		return fmt.Errorf("failed to create user authentication: %w", err)
+	}
+
+	return nil
+}
+
+func storeUserAuthProviderParams(auth *models.UserAuthProvider) db.StoreUserAuthProviderParams {
+	params := db.StoreUserAuthProviderParams{
+		AppID:          auth.AppID,
+		UserID:         auth.UserID,
+		Provider:       string(auth.Provider),
+		ProviderUserID: auth.ProviderUserID,
+	}
+
+	// Only local credentials carry a password; other providers store NULL.
+	if auth.Password != "" {
+		params.Password = &auth.Password
+	}
+
+	return params
+}
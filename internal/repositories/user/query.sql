@@ -30,4 +30,22 @@ WHERE app_id = $1 AND id = $2;
 -- name: GetUserByEmail :one
 SELECT id, app_id, name, email, is_email_verified, email_verified_at, created_at, updated_at 
 FROM core.users 
-WHERE app_id = $1 AND email = $2;
\ No newline at end of file
+WHERE app_id = $1 AND email = $2;
+
+-- name: UpdateUserAuthProviderPassword :execrows
+UPDATE core.user_auth_providers
+SET password = $1, updated_at = now()
+WHERE app_id = $2 AND user_id = $3 AND provider = $4;
+
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE core.users
+SET is_email_verified = true, email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+WHERE app_id = $1 AND id = $2;
+
+
+-- name: GetUserByAuthProvider :one
+SELECT u.id, u.app_id, u.name, u.email, u.is_email_verified, u.email_verified_at, u.created_at, u.updated_at
+FROM core.users u
+JOIN core.user_auth_providers uap ON uap.user_id = u.id AND uap.app_id = u.app_id
+WHERE uap.app_id = $1 AND uap.provider = $2 AND uap.provider_user_id = $3;
\ No newline at end of file
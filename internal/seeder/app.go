@@ -2,13 +2,11 @@ package seeder
 
 import (
 	"context"
-	"time"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/services"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AppSeeder struct {
@@ -42,8 +40,13 @@ func (s *AppSeeder) Seed(ctx context.Context) error {
 	return nil
 }
 
+// SeedApiKeyHash issues an API key to every app without a usable one: apps
+// that never had a key, and apps whose keys predate key ids.
 func (s *AppSeeder) SeedApiKeyHash(ctx context.Context) error {
-	query := `SELECT a.id, name FROM core.apps a LEFT JOIN core.app_api_keys aak ON a.id = aak.app_id WHERE aak.app_id IS NULL`
+	query := `SELECT a.id, a.name FROM core.apps a WHERE a.deleted_at IS NULL AND NOT EXISTS (
+		SELECT 1 FROM core.app_api_keys aak WHERE aak.app_id = a.id AND aak.is_active = true AND aak.key_id IS NOT NULL
+			AND (aak.revoked_at IS NULL OR aak.revoked_at > now())
+	)`
 
 	rows, err := s.db.Pool.Query(ctx, query)
 	if err != nil {
@@ -54,60 +57,27 @@ func (s *AppSeeder) SeedApiKeyHash(ctx context.Context) error {
 	utils.Log().Println("Starting to backfill API keys...")
 
 	for rows.Next() {
-		var appIDStr string
+		var appID uuid.UUID
 		var rawAppName []byte
 
-		if err := rows.Scan(&appIDStr, &rawAppName); err != nil {
+		if err := rows.Scan(&appID, &rawAppName); err != nil {
 			utils.Log().Error().Err(err).Msg("Failed to scan appID")
 			continue
 		}
 
-		apiKey, err := s.appService.GenerateAPIKey()
+		rotated, err := s.appService.RotateAPIKey(ctx, appID, models.AppApiKeyPublishable, 0)
 		if err != nil {
-			utils.Log().Error().Err(err).Msg("Failed to generate API key for app")
-			continue
-		}
-
-		hashedApiKey, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.DefaultCost)
-		if err != nil {
-			utils.Log().Error().Err(err).Msg("Failed to hash API key for app")
-			continue
-		}
-
-		appID, appIDErr := uuid.Parse(appIDStr)
-		if appIDErr != nil {
-			utils.Log().Error().Err(err).Msg("Failed to parse appIDStr")
-			continue
-		}
-
-		appApiKeyID, appApiKeyIDerr := uuid.NewV7()
-		if appApiKeyIDerr != nil {
-			utils.Log().Error().Err(err).Msg("Failed to generate appApiKeyID")
-			continue
-		}
-
-		appApiKey := &models.AppApiKey{
-			ID:        appApiKeyID,
-			AppID:     appID,
-			KeyHash:   string(hashedApiKey),
-			CreatedAt: time.Now(),
-			IsActive:  true,
-		}
-
-		_, err = s.db.Pool.Exec(ctx, `INSERT INTO core.app_api_keys (id, app_id, key_hash, created_at, is_active) VALUES ($1, $2, $3, $4, $5)`, appApiKey.ID, appApiKey.AppID, appApiKey.KeyHash, appApiKey.CreatedAt, appApiKey.IsActive)
-		if err != nil {
-			utils.Log().Error().Err(err).Msg("Failed to update app")
+			utils.Log().Error().Err(err).Str("app_id", appID.String()).Msg("Failed to issue API key for app")
 			continue
 		}
 
+		// The key is only ever shown here, so log it even if the name is unreadable.
 		appName, err := s.appService.ParseAppName(string(rawAppName))
-
 		if err != nil {
 			utils.Log().Error().Err(err).Msg("Failed to parsed appName")
-			continue
 		}
 
-		utils.Log().Info().Str("app_name", appName).Str("api_key", apiKey).Msg("✅ Backfilled app with NEW API Key")
+		utils.Log().Info().Str("app_id", appID.String()).Str("app_name", appName).Str("api_key", rotated.APIKey).Msg("✅ Backfilled app with NEW API Key")
 	}
 
 	utils.Log().Info().Msg("Backfill complete. SAVE THE LOG OUTPUT ABOVE!")
@@ -7,6 +7,8 @@ import (
 	v1 "github.com/fransiscushermanto/backend/internal/controllers/v1"
 	"github.com/fransiscushermanto/backend/internal/controllers/v1/app"
 	"github.com/fransiscushermanto/backend/internal/middlewares"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/ratelimit"
 	"github.com/fransiscushermanto/backend/internal/services"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/rs/cors"
@@ -15,9 +17,12 @@ import (
 )
 
 type Services struct {
-	AppService  *services.AppService
-	AuthService *services.AuthService
-	UserService *services.UserService
+	AppService        *services.AppService
+	AuthService       *services.AuthService
+	UserService       *services.UserService
+	FederationService *services.FederationService
+	OAuthService      *services.OAuthService
+	RateLimiter       *ratelimit.Limiter
 }
 
 type RoutesOptions struct {
@@ -30,6 +35,12 @@ func RoutesV1(router chi.Router, options *RoutesOptions) {
 	services := options.Services
 
 	authMiddleware := middlewares.NewAuthMiddleware(services.AuthService)
+	appKeyMiddleware := middlewares.NewAppKeyMiddleware(services.AppService)
+	rateLimitMiddleware := middlewares.NewRateLimitMiddleware(services.RateLimiter)
+
+	authLimit := ratelimit.Limit{Requests: config.RateLimitAuthRequests, Window: config.RateLimitWindow(), FailClosed: config.RateLimitAuthFailClosed}
+	appLimit := ratelimit.Limit{Requests: config.RateLimitAppRequests, Window: config.RateLimitWindow()}
+	userLimit := ratelimit.Limit{Requests: config.RateLimitUserRequests, Window: config.RateLimitWindow()}
 
 	router.Route("/v1", func(r chi.Router) {
 		// This middleware will run for every request to /api/v1/*
@@ -49,33 +60,94 @@ func RoutesV1(router chi.Router, options *RoutesOptions) {
 		corsCfg := cors.Options{
 			AllowedOrigins:   config.AllowedOrigins,
 			AllowCredentials: true,
+			ExposedHeaders:   []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 			Debug:            utils.IsDevelopment(),
 		}
 
 		// Protected routes
 		r.Group(func(rProtected chi.Router) {
-			rProtected.Use(middlewares.NewCorsMiddleware(corsCfg).Handler)
+			rProtected.Use(middlewares.NewCorsMiddleware(services.AppService, corsCfg).Handler)
 			rProtected.Options("/*", func(w http.ResponseWriter, r *http.Request) {})
 
 			userController := v1.NewUserController(services.UserService)
-			appController := v1.NewAppController(services.AppService, app.ControllerOptions{
+			appController := v1.NewAppController(services.AppService, services.AuthService, services.FederationService, services.OAuthService, app.ControllerOptions{
 				SecretKey: config.SecretKey,
 			})
-			authController := v1.NewAuthController(services.AuthService)
+			authController := v1.NewAuthController(services.AuthService, services.FederationService, services.OAuthService)
 
 			rProtected.Group(func(rAuthGroup chi.Router) {
-				rAuthGroup.Post("/register", authController.Register)
+				rAuthGroup.Use(rateLimitMiddleware.Limit("auth", authLimit, middlewares.KeyByIP))
+
+				rAuthGroup.With(appKeyMiddleware.RequireAppKey).Post("/register", authController.Register)
 				rAuthGroup.Post("/refresh", authController.RefreshToken)
-				rAuthGroup.Post("/login", authController.Login)
-				rAuthGroup.Post("/forget-password", authController.ForgetPassword)
+				rAuthGroup.With(appKeyMiddleware.RequireAppKey).Post("/login", authController.Login)
+				rAuthGroup.Get("/login/passwordless/verify", authController.VerifyPasswordless)
+				rAuthGroup.With(appKeyMiddleware.RequireAppKey).Post("/forget-password", authController.ForgetPassword)
+				rAuthGroup.Post("/reset-password", authController.ResetPassword)
+				rAuthGroup.With(appKeyMiddleware.RequireAppKey).Post("/verify-email/request", authController.RequestEmailVerification)
+				rAuthGroup.Post("/verify-email/confirm", authController.ConfirmEmailVerification)
+
+				rAuthGroup.Get("/federation/{provider}/start", authController.FederationStart)
+				rAuthGroup.Get("/federation/{provider}/callback", authController.FederationCallback)
+
+				rAuthGroup.Post("/oauth/authorize", authController.Authorize)
+				rAuthGroup.Post("/oauth/token", authController.Token)
+				rAuthGroup.Post("/oauth/introspect", authController.Introspect)
+				rAuthGroup.Post("/oauth/revoke", authController.Revoke)
 			})
 
 			rProtected.Route("/apps", func(rApps chi.Router) {
-				rApps.Get("/", appController.GetApps)
-				rApps.Post("/register", appController.RegisterApp)
+				rApps.Group(func(rAppsPublic chi.Router) {
+					rAppsPublic.Use(rateLimitMiddleware.Limit("apps_public", authLimit, middlewares.KeyByIP))
+
+					rAppsPublic.Get("/", appController.GetApps)
+					rAppsPublic.Post("/register", appController.RegisterApp)
+				})
+
+				// Managing an app takes a client_credentials token, which only
+				// its secret key gets; the publishable key is public.
+				rApps.With(authMiddleware.RequireClientAuth).Group(func(rApp chi.Router) {
+					rApp.Use(rateLimitMiddleware.Limit("apps", appLimit, middlewares.KeyByAppID))
+
+					rApp.Group(func(rRead chi.Router) {
+						rRead.Use(authMiddleware.RequireScope(models.ScopeAppsRead))
+
+						rRead.Get("/me", appController.CurrentApp)
+						rRead.Get("/{id}", appController.GetApp)
+						rRead.Get("/{id}/identity-providers", appController.GetIdentityProviders)
+						rRead.Get("/{id}/redirect-uris", appController.GetRedirectURIs)
+						rRead.Get("/{id}/allowed-origins", appController.GetAllowedOrigins)
+						rRead.Get("/{id}/settings", appController.GetAppSettings)
+						rRead.Get("/{id}/api-keys", appController.ListAPIKeys)
+					})
+
+					rApp.Group(func(rWrite chi.Router) {
+						rWrite.Use(authMiddleware.RequireScope(models.ScopeAppsWrite))
+
+						rWrite.Patch("/{id}", appController.UpdateApp)
+						rWrite.Delete("/{id}", appController.DeleteApp)
+
+						rWrite.Post("/{id}/identity-providers", appController.CreateIdentityProvider)
+						rWrite.Delete("/{id}/identity-providers/{provider}", appController.DeleteIdentityProvider)
+
+						rWrite.Post("/{id}/redirect-uris", appController.CreateRedirectURI)
+						rWrite.Delete("/{id}/redirect-uris/{redirectURIID}", appController.DeleteRedirectURI)
+
+						rWrite.Post("/{id}/allowed-origins", appController.CreateAllowedOrigin)
+						rWrite.Delete("/{id}/allowed-origins/{allowedOriginID}", appController.DeleteAllowedOrigin)
+
+						rWrite.Put("/{id}/settings", appController.UpdateAppSettings)
+
+						rWrite.Post("/{id}/login-lockouts/unlock", appController.UnlockLogin)
+
+						rWrite.Post("/{id}/api-keys/rotate", appController.RotateAPIKey)
+					})
+				})
 			})
 
 			rProtected.With(authMiddleware.RequireAuth).Group(func(rAuthed chi.Router) {
+				rAuthed.Use(rateLimitMiddleware.Limit("users", userLimit, middlewares.KeyByUserID))
+
 				rAuthed.Get("/users", userController.GetUsers)
 				rAuthed.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
 					if !utils.IsDevelopment() {
@@ -86,6 +158,13 @@ func RoutesV1(router chi.Router, options *RoutesOptions) {
 				})
 
 				rAuthed.Get("/profile", userController.Profile)
+
+				rAuthed.Post("/logout", authController.Logout)
+				rAuthed.Post("/logout-all", authController.LogoutAll)
+
+				rAuthed.Get("/sessions", authController.ListSessions)
+				rAuthed.Delete("/sessions", authController.RevokeOtherSessions)
+				rAuthed.Delete("/sessions/{jti}", authController.RevokeSession)
 			})
 
 		})
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/fransiscushermanto/backend/internal/controllers/wellknown"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/cors"
+)
+
+// RoutesWellKnown serves the documents verifiers use to discover our issuer
+// and signing keys. They are public and unversioned.
+func RoutesWellKnown(router chi.Router, options *RoutesOptions) {
+	wellKnownController := wellknown.NewController(options.AuthService, options.Cfg)
+
+	router.Route("/.well-known", func(r chi.Router) {
+		r.Use(cors.Default().Handler)
+		r.Get("/jwks.json", wellKnownController.JWKS)
+		r.Get("/openid-configuration", wellKnownController.OpenIDConfiguration)
+	})
+}
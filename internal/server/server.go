@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/middlewares"
 	"github.com/fransiscushermanto/backend/internal/server/routes"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/go-chi/chi/v5"
@@ -19,13 +21,13 @@ import (
 type APIServer struct {
 	cfg        *config.AppConfig
 	services   *routes.Services
-	keys       *config.CryptoKeys
+	keys       *keyring.KeyRing
 	httpServer *http.Server
 }
 
-func NewAPIServer(cfg *config.AppConfig, services *routes.Services, keys *config.CryptoKeys) *APIServer {
-	if !keys.IsValid() {
-		panic(fmt.Sprintf("Invalid crypto keys provided: %s", keys))
+func NewAPIServer(cfg *config.AppConfig, services *routes.Services, keys *keyring.KeyRing) *APIServer {
+	if _, err := keys.SigningKey(); err != nil {
+		panic(fmt.Sprintf("Invalid signing keys provided: %v", err))
 	}
 
 	return &APIServer{
@@ -38,12 +40,22 @@ func NewAPIServer(cfg *config.AppConfig, services *routes.Services, keys *config
 func (s *APIServer) Run() error {
 	router := chi.NewRouter()
 
+	trustedProxies, err := s.cfg.TrustedProxyNetworks()
+	if err != nil {
+		return err
+	}
+
 	router.Use(middleware.RequestID)
-	router.Use(middleware.RealIP)
+	router.Use(middlewares.RealIP(trustedProxies))
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Timeout(60 * time.Second))
 
+	routes.RoutesWellKnown(router, &routes.RoutesOptions{
+		Cfg:      s.cfg,
+		Services: s.services,
+	})
+
 	router.Route("/api", func(r chi.Router) {
 		fmt.Println("API")
 		routes.RoutesV1(r, &routes.RoutesOptions{
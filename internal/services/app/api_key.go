@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// RotateAPIKey issues a new key of kind for the app. Its active keys of that
+// kind keep working for gracePeriod, so clients can switch over without
+// downtime; a zero grace period revokes them right away. Keys already
+// winding down are never extended.
+func (s *AppService) RotateAPIKey(ctx context.Context, appID uuid.UUID, kind models.AppApiKeyKind, gracePeriod time.Duration) (*models.RotateAppApiKeyResponse, error) {
+	rotateAPIKeyLog := log("RotateAPIKey")
+
+	if gracePeriod < 0 || gracePeriod > constants.MAX_API_KEY_GRACE_PERIOD {
+		return nil, ErrInvalidGracePeriod
+	}
+
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	apiKey, appApiKey, err := s.GenerateAPIKey(appID, kind)
+	if err != nil {
+		rotateAPIKeyLog.Error().Err(err).Msg("Failed to generate API key for app")
+		return nil, utils.ErrInternalServerError
+	}
+
+	revokedAt := time.Now().Add(gracePeriod)
+
+	if err := s.repo.RegenerateAppApiKey(ctx, revokedAt, appApiKey); err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	rotateAPIKeyLog.Info().Str("app_id", appID.String()).Str("key_id", appApiKey.KeyID).Str("kind", string(kind)).Time("previous_keys_revoked_at", revokedAt).Msg("Rotated API key")
+
+	return &models.RotateAppApiKeyResponse{
+		ID:                    appApiKey.ID.String(),
+		KeyID:                 appApiKey.KeyID,
+		Kind:                  kind,
+		APIKey:                apiKey,
+		PreviousKeysRevokedAt: revokedAt,
+	}, nil
+}
+
+// ListAPIKeys returns the metadata of every key the app has had, newest
+// first.
+func (s *AppService) ListAPIKeys(ctx context.Context, appID uuid.UUID) ([]models.AppApiKeyResponse, error) {
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	apiKeys, err := s.repo.GetAppApiKeys(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	now := time.Now()
+	response := make([]models.AppApiKeyResponse, len(apiKeys))
+
+	for i, apiKey := range apiKeys {
+		response[i] = models.AppApiKeyResponse{
+			ID:         apiKey.ID.String(),
+			KeyID:      apiKey.KeyID,
+			Kind:       apiKey.Kind,
+			Active:     apiKey.IsActive && (apiKey.RevokedAt == nil || apiKey.RevokedAt.After(now)),
+			CreatedAt:  apiKey.CreatedAt,
+			LastUsedAt: apiKey.LastUsedAt,
+			RevokedAt:  apiKey.RevokedAt,
+		}
+	}
+
+	return response, nil
+}
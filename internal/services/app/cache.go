@@ -0,0 +1,63 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bounds a cache when requests name apps that do not exist.
+const maxAppCacheEntries = 10000
+
+type appCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// appCache keeps per-app data read on every request, like allowed origins
+// and settings. Changes made through this process invalidate it right away;
+// the TTL bounds how long other instances serve stale values.
+type appCache[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[uuid.UUID]appCacheEntry[V]
+}
+
+func newAppCache[V any](ttl time.Duration) *appCache[V] {
+	return &appCache[V]{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]appCacheEntry[V]),
+	}
+}
+
+func (c *appCache[V]) get(appID uuid.UUID) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[appID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+func (c *appCache[V]) set(appID uuid.UUID, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxAppCacheEntries {
+		c.entries = make(map[uuid.UUID]appCacheEntry[V])
+	}
+
+	c.entries[appID] = appCacheEntry[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (c *appCache[V]) invalidate(appID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, appID)
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// scope-token from RFC 6749 §3.3
+var scopeTokenPattern = regexp.MustCompile(`^[\x21\x23-\x5B\x5D-\x7E]+$`)
+
+// AuthenticateAPIKey returns the app a publishable API key belongs to and
+// records the key as used.
+func (s *AppService) AuthenticateAPIKey(ctx context.Context, apiKey string) (*models.App, error) {
+	app, _, err := s.authenticateKey(ctx, apiKey, models.AppApiKeyPublishable)
+	return app, err
+}
+
+// HasKeyID reports whether apiKey embeds a key id, i.e. was not issued
+// before key ids.
+func (s *AppService) HasKeyID(apiKey string) bool {
+	_, ok := s.parseAPIKey(apiKey)
+	return ok
+}
+
+// AuthenticateLegacyAPIKey accepts a key issued before key ids (migration
+// 000019) for the app it claims to belong to. Such keys only carry a bcrypt
+// hash, so they cannot be looked up by themselves; they keep working until
+// the app's publishable key is rotated (make seed type=apps_api_key), and no
+// longer than constants.LEGACY_API_KEY_SUNSET.
+func (s *AppService) AuthenticateLegacyAPIKey(ctx context.Context, appID uuid.UUID, apiKey string) (*models.App, error) {
+	authenticateLegacyAPIKeyLog := log("AuthenticateLegacyAPIKey")
+
+	if s.HasKeyID(apiKey) {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	if time.Now().After(constants.LEGACY_API_KEY_SUNSET) {
+		authenticateLegacyAPIKeyLog.Warn().Str("app_id", appID.String()).Msg("Legacy API keys are no longer accepted")
+		return nil, ErrInvalidClientCredentials
+	}
+
+	keys, err := s.repo.GetActiveLegacyAppApiKeys(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	for _, key := range keys {
+		if key.Kind != models.AppApiKeyPublishable || bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(apiKey)) != nil {
+			continue
+		}
+
+		app, err := s.repo.GetAppById(ctx, appID)
+		if err != nil {
+			return nil, utils.ErrInternalServerError
+		}
+
+		if app == nil {
+			return nil, ErrInvalidClientCredentials
+		}
+
+		if err := s.repo.TouchAppApiKey(ctx, key.ID); err != nil {
+			authenticateLegacyAPIKeyLog.Warn().Err(err).Str("app_id", appID.String()).Msg("Failed to record api key usage")
+		}
+
+		authenticateLegacyAPIKeyLog.Warn().Str("app_id", appID.String()).Msg("Legacy API key used; rotate it to issue a key with a key id")
+		return app, nil
+	}
+
+	return nil, ErrInvalidClientCredentials
+}
+
+func (s *AppService) authenticateKey(ctx context.Context, apiKey string, kind models.AppApiKeyKind) (*models.App, *models.AppApiKey, error) {
+	authenticateKeyLog := log("authenticateKey")
+
+	key, err := s.verifyAPIKey(ctx, apiKey, kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	app, err := s.repo.GetAppById(ctx, key.AppID)
+	if err != nil {
+		return nil, nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, nil, ErrInvalidClientCredentials
+	}
+
+	if err := s.repo.TouchAppApiKey(ctx, key.ID); err != nil {
+		authenticateKeyLog.Warn().Err(err).Str("app_id", app.ID.String()).Msg("Failed to record api key usage")
+	}
+
+	return app, key, nil
+}
+
+// AppIDForAPIKey returns the app a publishable API key belongs to without
+// recording its use, for checks that run ahead of the actual authentication.
+func (s *AppService) AppIDForAPIKey(ctx context.Context, apiKey string) (uuid.UUID, error) {
+	key, err := s.verifyAPIKey(ctx, apiKey, models.AppApiKeyPublishable)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return key.AppID, nil
+}
+
+// verifyAPIKey finds the stored hash by the key id embedded in the key and
+// compares it in constant time. Keys of another kind are rejected, so a
+// publishable key never passes for a secret one.
+func (s *AppService) verifyAPIKey(ctx context.Context, apiKey string, kind models.AppApiKeyKind) (*models.AppApiKey, error) {
+	keyID, ok := s.parseAPIKey(apiKey)
+	if !ok {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	key, err := s.repo.GetActiveAppApiKeyByKeyID(ctx, keyID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if key == nil || key.Kind != kind || !hmac.Equal([]byte(s.hashAPIKey(apiKey)), []byte(key.KeyHash)) {
+		log("verifyAPIKey").Warn().Str("key_id", keyID).Msg("API key did not match an active key")
+		return nil, ErrInvalidClientCredentials
+	}
+
+	return key, nil
+}
+
+// AuthenticateClient checks an app ID and secret key pair, as used by the
+// client_credentials grant. It also returns the key, whose key id the client
+// token is bound to.
+func (s *AppService) AuthenticateClient(ctx context.Context, appID uuid.UUID, secretKey string) (*models.App, *models.AppApiKey, error) {
+	app, key, err := s.authenticateKey(ctx, secretKey, models.AppApiKeySecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if app.ID != appID {
+		log("AuthenticateClient").Warn().Str("app_id", appID.String()).Msg("Secret key belongs to another app")
+		return nil, nil, ErrInvalidClientCredentials
+	}
+
+	return app, key, nil
+}
+
+// VerifyClientKey checks that the secret key a client token was issued
+// against is still active and that its app has not been deleted, so client
+// tokens stop working with the key rather than at their expiry.
+func (s *AppService) VerifyClientKey(ctx context.Context, appID uuid.UUID, keyID string) error {
+	key, err := s.repo.GetActiveAppApiKeyByKeyID(ctx, keyID)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	if key == nil || key.Kind != models.AppApiKeySecret || key.AppID != appID {
+		return ErrInvalidClientCredentials
+	}
+
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return ErrInvalidClientCredentials
+	}
+
+	return nil
+}
+
+// ResolveScopes returns the scopes to grant for a space separated scope
+// request. An empty request grants everything the app is allowed.
+func (s *AppService) ResolveScopes(app *models.App, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return app.GrantedScopes, nil
+	}
+
+	for _, scope := range requested {
+		if !slices.Contains(app.GrantedScopes, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	return requested, nil
+}
+
+// SetGrantedScopes replaces the scopes the app may request through the
+// client_credentials grant. Tokens already issued keep their scopes until
+// they expire.
+func (s *AppService) SetGrantedScopes(ctx context.Context, appID uuid.UUID, scopes []string) ([]string, error) {
+	grantedScopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateAppGrantedScopes(ctx, appID, grantedScopes)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if !updated {
+		return nil, utils.ErrNotFound
+	}
+
+	return grantedScopes, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := []string{}
+
+	for _, scope := range scopes {
+		if !scopeTokenPattern.MatchString(scope) {
+			return nil, ErrInvalidScope
+		}
+
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	return normalized, nil
+}
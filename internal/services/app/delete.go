@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// DeleteApp soft deletes the app: it stops authenticating right away, but
+// its users and tokens are only removed once the purge delay has passed.
+func (s *AppService) DeleteApp(ctx context.Context, appID uuid.UUID) (*models.DeleteAppResponse, error) {
+	deletedAt := time.Now()
+
+	deleted, err := s.repo.SoftDeleteApp(ctx, appID, deletedAt)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if !deleted {
+		return nil, utils.ErrNotFound
+	}
+
+	s.origins.invalidate(appID)
+	s.settings.invalidate(appID)
+
+	purgeAt := deletedAt.Add(s.purgeDelay)
+	log("DeleteApp").Info().Str("app_id", appID.String()).Time("purge_at", purgeAt).Msg("Deleted app")
+
+	return &models.DeleteAppResponse{
+		ID:        appID.String(),
+		DeletedAt: deletedAt,
+		PurgeAt:   purgeAt,
+	}, nil
+}
+
+// PurgeDeletedApps permanently removes the apps deleted longer than the purge
+// delay ago.
+func (s *AppService) PurgeDeletedApps(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeDeletedApps(ctx, time.Now().Add(-s.purgeDelay))
+	if err != nil {
+		return 0, utils.ErrInternalServerError
+	}
+
+	if purged > 0 {
+		log("PurgeDeletedApps").Info().Int64("purged_apps", purged).Msg("Purged deleted apps")
+	}
+
+	return purged, nil
+}
+
+// WatchDeletedApps purges deleted apps every interval until ctx is done.
+func (s *AppService) WatchDeletedApps(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeDeletedApps(ctx); err != nil {
+				log("WatchDeletedApps").Error().Err(err).Msg("Failed to purge deleted apps")
+			}
+		}
+	}
+}
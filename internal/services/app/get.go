@@ -54,3 +54,34 @@ func (s *AppService) GetApps(ctx context.Context) ([]*models.AppResponse, error)
 
 	return apps, nil
 }
+
+func (s *AppService) GetAppDetail(ctx context.Context, appID uuid.UUID) (*models.AppDetailResponse, error) {
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	settings, err := s.GetSettings(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.ParseAppName(string(app.Name))
+	if err != nil {
+		log("GetAppDetail").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to decrypt app name")
+		return nil, utils.ErrInternalServerError
+	}
+
+	return &models.AppDetailResponse{
+		ID:                       app.ID.String(),
+		Name:                     name,
+		RequireEmailVerification: settings.RequireEmailVerification,
+		GrantedScopes:            app.GrantedScopes,
+		CreatedAt:                app.CreatedAt,
+		UpdatedAt:                app.UpdatedAt,
+	}, nil
+}
@@ -1,6 +1,10 @@
 package app
 
 import (
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/rs/zerolog"
 )
@@ -10,6 +14,14 @@ func log(method string) *zerolog.Logger {
 	return &l
 }
 
-func NewAppService(repo AppRepository, prefixApiKey string, secretKey string) *AppService {
-	return &AppService{repo: repo, prefixApiKey: prefixApiKey, secretKey: secretKey}
+func NewAppService(repo AppRepository, prefixApiKey string, secretKey string, apiKeyPepper string, purgeDelay time.Duration) *AppService {
+	return &AppService{
+		repo:         repo,
+		prefixApiKey: prefixApiKey,
+		secretKey:    secretKey,
+		apiKeyPepper: []byte(apiKeyPepper),
+		purgeDelay:   purgeDelay,
+		origins:      newAppCache[[]string](constants.DEFAULT_APP_CACHE_TTL),
+		settings:     newAppCache[*models.AppSettings](constants.DEFAULT_APP_CACHE_TTL),
+	}
 }
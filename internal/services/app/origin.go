@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (s *AppService) CreateAllowedOrigin(ctx context.Context, appID uuid.UUID, req *models.AllowedOriginRequest) (*models.AppAllowedOrigin, error) {
+	if !isValidOriginEntry(req.Origin) {
+		return nil, ErrInvalidOrigin
+	}
+
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	allowedOrigin := &models.AppAllowedOrigin{
+		ID:     uuid.New(),
+		AppID:  appID,
+		Origin: req.Origin,
+	}
+
+	if err := s.repo.StoreAllowedOrigin(ctx, allowedOrigin); err != nil {
+		return nil, err
+	}
+
+	s.origins.invalidate(appID)
+
+	log("CreateAllowedOrigin").Info().Str("app_id", appID.String()).Str("origin", req.Origin).Msg("Origin allowed")
+	return allowedOrigin, nil
+}
+
+func (s *AppService) GetAllowedOrigins(ctx context.Context, appID uuid.UUID) ([]*models.AppAllowedOrigin, error) {
+	allowedOrigins, err := s.repo.GetAllowedOriginsByAppID(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	return allowedOrigins, nil
+}
+
+func (s *AppService) DeleteAllowedOrigin(ctx context.Context, appID, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteAllowedOrigin(ctx, appID, id)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	if !deleted {
+		return ErrOriginNotFound
+	}
+
+	s.origins.invalidate(appID)
+	return nil
+}
+
+// IsOriginAllowed reports whether a browser on origin may call the API for
+// the app. Lookups are cached, see appCache.
+func (s *AppService) IsOriginAllowed(ctx context.Context, appID uuid.UUID, origin string) (bool, error) {
+	origins, ok := s.origins.get(appID)
+	if !ok {
+		allowedOrigins, err := s.repo.GetAllowedOriginsByAppID(ctx, appID)
+		if err != nil {
+			return false, utils.ErrInternalServerError
+		}
+
+		origins = make([]string, len(allowedOrigins))
+		for i, allowedOrigin := range allowedOrigins {
+			origins[i] = allowedOrigin.Origin
+		}
+
+		s.origins.set(appID, origins)
+	}
+
+	for _, pattern := range origins {
+		if utils.MatchOrigin(pattern, origin) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// isValidOriginEntry accepts a bare http(s) origin, or one whose first label
+// is a wildcard.
+func isValidOriginEntry(origin string) bool {
+	if utils.IsWildcardOrigin(origin) {
+		return utils.IsValidWildcardOrigin(origin)
+	}
+
+	uri, err := url.Parse(origin)
+	if err != nil || (uri.Scheme != "https" && uri.Scheme != "http") || uri.Host == "" {
+		return false
+	}
+
+	return origin == uri.Scheme+"://"+uri.Host
+}
@@ -3,12 +3,12 @@ package app
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func (s *AppService) Register(ctx context.Context, req *models.RegisterAppRequest) (*models.RegisterAppResponse, error) {
@@ -18,24 +18,14 @@ func (s *AppService) Register(ctx context.Context, req *models.RegisterAppReques
 		return nil, utils.ErrBadRequest
 	}
 
+	grantedScopes := slices.Clone(models.DefaultAppScopes)
+
 	name, err := utils.Encrypt([]byte(s.secretKey), []byte(req.Name))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt app name: %w", err)
 	}
 
-	apiKey, err := s.GenerateAPIKey()
-	if err != nil {
-		registerLog.Error().Err(err).Msg("Failed to generate API key for app")
-		return nil, utils.ErrInternalServerError
-	}
-
-	hashedApiKey, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.DefaultCost)
-	if err != nil {
-		registerLog.Error().Err(err).Msg("Failed to hash API key for app")
-		return nil, utils.ErrInternalServerError
-	}
-
 	appID, err := uuid.NewV7()
 
 	if err != nil {
@@ -44,38 +34,41 @@ func (s *AppService) Register(ctx context.Context, req *models.RegisterAppReques
 	}
 
 	app := &models.App{
-		ID:        appID,
-		Name:      name,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:            appID,
+		Name:          name,
+		GrantedScopes: grantedScopes,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}
 
-	appAPIKeyID, err := uuid.NewV7()
+	settings := defaultSettings(app.ID)
+	settings.RequireEmailVerification = req.RequireEmailVerification
 
+	apiKey, appApiKey, err := s.GenerateAPIKey(app.ID, models.AppApiKeyPublishable)
 	if err != nil {
-		fmt.Println("Error generating appAPIKeyID:", err)
+		registerLog.Error().Err(err).Msg("Failed to generate API key for app")
 		return nil, utils.ErrInternalServerError
 	}
 
-	appApiKey := &models.AppApiKey{
-		ID:        appAPIKeyID,
-		AppID:     app.ID,
-		KeyHash:   string(hashedApiKey),
-		CreatedAt: time.Now(),
-		IsActive:  true,
+	secretKey, appSecretKey, err := s.GenerateAPIKey(app.ID, models.AppApiKeySecret)
+	if err != nil {
+		registerLog.Error().Err(err).Msg("Failed to generate secret key for app")
+		return nil, utils.ErrInternalServerError
 	}
 
 	opCtx, cancel := utils.ContextWithTimeout(5 * time.Second)
 	defer cancel()
 
-	if err := s.repo.RegisterApp(opCtx, app, appApiKey); err != nil {
+	if err := s.repo.RegisterApp(opCtx, app, []*models.AppApiKey{appApiKey, appSecretKey}, settings); err != nil {
 		registerLog.Error().Err(err).Msg("Failed to execute method RegisterApp")
 		return nil, utils.ErrInternalServerError
 	}
 
 	return &models.RegisterAppResponse{
-		ID:     app.ID.String(),
-		Name:   req.Name,
-		APIKey: apiKey,
+		ID:            app.ID.String(),
+		Name:          req.Name,
+		APIKey:        apiKey,
+		SecretKey:     secretKey,
+		GrantedScopes: grantedScopes,
 	}, nil
 }
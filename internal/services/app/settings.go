@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"slices"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// defaultSettings is the policy apps are registered with.
+func defaultSettings(appID uuid.UUID) *models.AppSettings {
+	return &models.AppSettings{
+		AppID:                  appID,
+		AccessTokenTTLSeconds:  int(constants.DEFAULT_ACCESS_TOKEN_EXPIRY.Seconds()),
+		RefreshTokenTTLSeconds: int(constants.DEFAULT_REFRESH_TOKEN_EXPIRY.Seconds()),
+		PasswordPolicy:         models.DefaultPasswordPolicy,
+	}
+}
+
+// GetSettings returns the app's security policy, or the default one for an
+// app that does not exist. It is read on every login and refresh, so lookups
+// are cached, see appCache.
+func (s *AppService) GetSettings(ctx context.Context, appID uuid.UUID) (*models.AppSettings, error) {
+	settings, ok := s.settings.get(appID)
+	if !ok {
+		storedSettings, err := s.repo.GetAppSettings(ctx, appID)
+		if err != nil {
+			return nil, utils.ErrInternalServerError
+		}
+
+		settings = storedSettings
+		if settings == nil {
+			settings = defaultSettings(appID)
+		}
+
+		s.settings.set(appID, settings)
+	}
+
+	// The cached value is shared, callers get their own copy.
+	copied := *settings
+	copied.GoogleClientIDs = slices.Clone(settings.GoogleClientIDs)
+	return &copied, nil
+}
+
+func (s *AppService) GetPasswordPolicy(ctx context.Context, appID uuid.UUID) (models.PasswordPolicy, error) {
+	settings, err := s.GetSettings(ctx, appID)
+	if err != nil {
+		return models.PasswordPolicy{}, err
+	}
+
+	return settings.PasswordPolicy, nil
+}
+
+// UpdateSettings replaces the app's policy. Tokens already issued keep their
+// expiry; the new TTLs and limits apply from their next refresh.
+func (s *AppService) UpdateSettings(ctx context.Context, appID uuid.UUID, req *models.UpdateAppSettingsRequest) (*models.AppSettings, error) {
+	if req.AccessTokenTTLSeconds > req.RefreshTokenTTLSeconds {
+		return nil, ErrInvalidSettings
+	}
+
+	app, err := s.repo.GetAppById(ctx, appID)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	err = s.repo.UpsertAppSettings(ctx, &models.AppSettings{
+		AppID:                    appID,
+		AccessTokenTTLSeconds:    req.AccessTokenTTLSeconds,
+		RefreshTokenTTLSeconds:   req.RefreshTokenTTLSeconds,
+		SessionLifetimeSeconds:   req.SessionLifetimeSeconds,
+		PasswordPolicy:           req.PasswordPolicy,
+		MaxSessions:              req.MaxSessions,
+		RequireEmailVerification: req.RequireEmailVerification,
+		GoogleClientIDs:          req.GoogleClientIDs,
+	})
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	s.settings.invalidate(appID)
+	log("UpdateSettings").Info().Str("app_id", appID.String()).Msg("Updated app settings")
+
+	return s.GetSettings(ctx, appID)
+}
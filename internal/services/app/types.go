@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/google/uuid"
@@ -11,11 +13,39 @@ type AppService struct {
 	repo         AppRepository
 	secretKey    string
 	prefixApiKey string
+	apiKeyPepper []byte
+	purgeDelay   time.Duration
+	origins      *appCache[[]string]
+	settings     *appCache[*models.AppSettings]
 }
 
 //go:generate mockgen -source=app.go -destination=app_mock.go -package=services
 type AppRepository interface {
-	RegisterApp(ctx context.Context, app *models.App, appApiKey *models.AppApiKey) error
+	RegisterApp(ctx context.Context, app *models.App, appApiKeys []*models.AppApiKey, settings *models.AppSettings) error
 	GetAllApps(ctx context.Context) ([]*models.App, error)
 	GetAppById(ctx context.Context, id uuid.UUID) (*models.App, error)
+	UpdateAppName(ctx context.Context, id uuid.UUID, name []byte) (bool, error)
+	UpdateAppGrantedScopes(ctx context.Context, id uuid.UUID, grantedScopes []string) (bool, error)
+	SoftDeleteApp(ctx context.Context, id uuid.UUID, deletedAt time.Time) (bool, error)
+	PurgeDeletedApps(ctx context.Context, deletedBefore time.Time) (int64, error)
+	RegenerateAppApiKey(ctx context.Context, revokedAt time.Time, appApiKey *models.AppApiKey) error
+	GetActiveAppApiKeyByKeyID(ctx context.Context, keyID string) (*models.AppApiKey, error)
+	GetActiveLegacyAppApiKeys(ctx context.Context, appID uuid.UUID) ([]*models.AppApiKey, error)
+	GetAppApiKeys(ctx context.Context, appID uuid.UUID) ([]*models.AppApiKey, error)
+	TouchAppApiKey(ctx context.Context, id uuid.UUID) error
+	StoreAllowedOrigin(ctx context.Context, allowedOrigin *models.AppAllowedOrigin) error
+	GetAllowedOriginsByAppID(ctx context.Context, appID uuid.UUID) ([]*models.AppAllowedOrigin, error)
+	DeleteAllowedOrigin(ctx context.Context, appID, id uuid.UUID) (bool, error)
+	GetAppSettings(ctx context.Context, appID uuid.UUID) (*models.AppSettings, error)
+	UpsertAppSettings(ctx context.Context, settings *models.AppSettings) error
 }
+
+var (
+	ErrInvalidClientCredentials = errors.New("invalid app id or api key")
+	ErrInvalidScope             = errors.New("invalid scope")
+	ErrInvalidGracePeriod       = errors.New("invalid grace period")
+	ErrInvalidOrigin            = errors.New("invalid origin")
+	ErrOriginExists             = errors.New("origin already allowed")
+	ErrOriginNotFound           = errors.New("origin not found")
+	ErrInvalidSettings          = errors.New("invalid app settings")
+)
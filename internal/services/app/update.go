@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (s *AppService) UpdateApp(ctx context.Context, appID uuid.UUID, req *models.UpdateAppRequest) (*models.AppDetailResponse, error) {
+	updateAppLog := log("UpdateApp")
+
+	name, err := utils.Encrypt([]byte(s.secretKey), []byte(req.Name))
+	if err != nil {
+		updateAppLog.Error().Err(err).Msg("Failed to encrypt app name")
+		return nil, utils.ErrInternalServerError
+	}
+
+	updated, err := s.repo.UpdateAppName(ctx, appID, name)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if !updated {
+		return nil, utils.ErrNotFound
+	}
+
+	return s.GetAppDetail(ctx, appID)
+}
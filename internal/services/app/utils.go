@@ -1,20 +1,72 @@
 package app
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
 )
 
-func (s *AppService) GenerateAPIKey() (string, error) {
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
+// GenerateAPIKey issues a new key of kind for the app, formatted as
+// <prefix>_<key id>_<secret>. Only the returned AppApiKey is stored.
+func (s *AppService) GenerateAPIKey(appID uuid.UUID, kind models.AppApiKeyKind) (string, *models.AppApiKey, error) {
+	keyIDBytes := make([]byte, 8)
+	if _, err := rand.Read(keyIDBytes); err != nil {
+		return "", nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", nil, err
 	}
 
-	return fmt.Sprintf("%s_%s", s.prefixApiKey, hex.EncodeToString(bytes)), nil
+	keyID := hex.EncodeToString(keyIDBytes)
+	apiKey := fmt.Sprintf("%s_%s_%s", strings.TrimSuffix(s.prefixApiKey, "_"), keyID, hex.EncodeToString(secret))
+
+	return apiKey, &models.AppApiKey{
+		ID:        id,
+		AppID:     appID,
+		KeyID:     keyID,
+		Kind:      kind,
+		KeyHash:   s.hashAPIKey(apiKey),
+		CreatedAt: time.Now(),
+		IsActive:  true,
+	}, nil
+}
+
+// parseAPIKey returns the key id of a well-formed key.
+func (s *AppService) parseAPIKey(apiKey string) (string, bool) {
+	rest, ok := strings.CutPrefix(apiKey, strings.TrimSuffix(s.prefixApiKey, "_")+"_")
+	if !ok {
+		return "", false
+	}
+
+	keyID, secret, ok := strings.Cut(rest, "_")
+	if !ok || keyID == "" || secret == "" {
+		return "", false
+	}
+
+	return keyID, true
+}
+
+// hashAPIKey is an HMAC rather than bcrypt: keys carry 256 random bits, so a
+// fast keyed hash is enough and keeps per-request checks cheap.
+func (s *AppService) hashAPIKey(apiKey string) string {
+	mac := hmac.New(sha256.New, s.apiKeyPepper)
+	mac.Write([]byte(apiKey))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 func (s *AppService) ParseAppName(rawName string) (string, error) {
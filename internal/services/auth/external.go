@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (s *AuthService) LoginWithExternalIdentity(ctx context.Context, appID uuid.UUID, identity *models.ExternalIdentity, options AuthOptions) (*models.LoginResponse, error) {
+	loginWithExternalIdentityLog := log("LoginWithExternalIdentity")
+
+	user, err := s.findOrCreateExternalUser(ctx, appID, identity)
+	if err != nil {
+		loginWithExternalIdentityLog.Error().Err(err).Str("provider", string(identity.Provider)).Msg("Failed to resolve external identity")
+		return FailedLoginResponse(options), err
+	}
+
+	if err := s.ensureEmailVerified(ctx, user); err != nil {
+		return FailedLoginResponse(options), err
+	}
+
+	res, err := s.completeLogin(ctx, user, options)
+	if err != nil && res == nil {
+		return FailedLoginResponse(options), err
+	}
+
+	return res, err
+}
+
+// findOrCreateExternalUser resolves the user behind an identity asserted by a
+// third-party provider. A user already linked to the provider subject wins;
+// otherwise an existing account with the same email is linked, but only when
+// the provider vouches for the address and is trusted to (see
+// models.IdentityProvider.TrustEmail).
+func (s *AuthService) findOrCreateExternalUser(ctx context.Context, appID uuid.UUID, identity *models.ExternalIdentity) (*models.User, error) {
+	findOrCreateExternalUserLog := log("findOrCreateExternalUser").With().Str("provider", string(identity.Provider)).Logger()
+
+	if identity.Subject == "" || identity.Email == "" {
+		return nil, ErrMissingRequiredClaim
+	}
+
+	user, err := s.userRepository.GetUserByAuthProvider(ctx, appID, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if user != nil {
+		return user, nil
+	}
+
+	user, err = s.userRepository.GetUserByEmail(ctx, appID, identity.Email)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if user != nil {
+		if !identity.EmailVerified {
+			findOrCreateExternalUserLog.Warn().Str("user_id", user.ID.String()).Msg("Refusing to link external account with unverified email")
+			return nil, ErrProviderAccountConflict
+		}
+
+		err = s.userRepository.CreateUserAuthProvider(ctx, &models.UserAuthProvider{
+			UserID:         user.ID,
+			AppID:          user.AppID,
+			Provider:       identity.Provider,
+			ProviderUserID: &identity.Subject,
+		})
+
+		if err != nil {
+			findOrCreateExternalUserLog.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to link external account")
+			return nil, utils.ErrInternalServerError
+		}
+
+		if !user.IsEmailVerified {
+			if err := s.userService.MarkEmailVerified(ctx, user.AppID, user.ID); err != nil {
+				return nil, utils.ErrInternalServerError
+			}
+
+			user.IsEmailVerified = true
+		}
+
+		findOrCreateExternalUserLog.Info().Str("user_id", user.ID.String()).Msg("Linked external account to existing user")
+		return user, nil
+	}
+
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	user, err = s.userService.CreateUser(ctx, &models.CreateUserRequest{
+		Provider:        identity.Provider,
+		AppID:           appID,
+		Name:            name,
+		Email:           identity.Email,
+		ProviderUserID:  &identity.Subject,
+		IsEmailVerified: identity.EmailVerified,
+	})
+
+	if err != nil {
+		findOrCreateExternalUserLog.Error().Err(err).Msg("Failed to create external user")
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+type googleIDTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// verifyGoogleIDToken only accepts tokens issued to one of the app's own
+// Google client ids, see models.AppSettings.
+func (s *AuthService) verifyGoogleIDToken(ctx context.Context, appID uuid.UUID, idToken string) (*googleIDTokenClaims, error) {
+	settings, err := s.appService.GetSettings(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(settings.GoogleClientIDs) == 0 {
+		return nil, ErrProviderNotConfigured
+	}
+
+	claims := &googleIDTokenClaims{}
+
+	_, err = jwt.ParseWithClaims(idToken, claims, s.googleJWKS.Keyfunc(ctx),
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProviderToken, err)
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidProviderToken, claims.Issuer)
+	}
+
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(settings.GoogleClientIDs, aud)
+	}) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidProviderToken)
+	}
+
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, fmt.Errorf("%w: sub or email", ErrMissingRequiredClaim)
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) findOrCreateGoogleUser(ctx context.Context, appID uuid.UUID, idToken string, name string) (*models.User, error) {
+	claims, err := s.verifyGoogleIDToken(ctx, appID, idToken)
+	if err != nil {
+		log("findOrCreateGoogleUser").Warn().Err(err).Msg("Failed to verify google id token")
+		return nil, err
+	}
+
+	if name == "" {
+		name = claims.Name
+	}
+
+	return s.findOrCreateExternalUser(ctx, appID, &models.ExternalIdentity{
+		Provider:      models.AuthProviderGoogle,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          name,
+	})
+}
@@ -1,7 +1,14 @@
 package auth
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/mailer"
+	"github.com/fransiscushermanto/backend/internal/services/app"
 	"github.com/fransiscushermanto/backend/internal/services/user"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/rs/zerolog"
@@ -12,16 +19,19 @@ func log(method string) *zerolog.Logger {
 	return &l
 }
 
-func NewAuthService(repo AuthRepository, userRepository user.UserRepository, userService *user.UserService, keys *config.CryptoKeys) *AuthService {
-	if !keys.IsValid() {
-		panic("AuthService requires valid keys")
+func NewAuthService(repo AuthRepository, userRepository user.UserRepository, userService *user.UserService, appService *app.AppService, mailer mailer.Mailer, cfg *config.AppConfig, keys *keyring.KeyRing) *AuthService {
+	if _, err := keys.SigningKey(); err != nil {
+		panic(fmt.Sprintf("AuthService requires a signing key: %v", err))
 	}
 
 	return &AuthService{
 		repo:           repo,
 		userRepository: userRepository,
 		userService:    userService,
-		privateKey:     keys.PrivateKey,
-		publicKey:      keys.PublicKey,
+		appService:     appService,
+		mailer:         mailer,
+		config:         cfg,
+		googleJWKS:     jwks.NewCache(cfg.GoogleJWKSURL, time.Hour),
+		keys:           keys,
 	}
 }
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Past this many doublings the delay is longer than any sane lockout.
+const maxLoginDelayDoublings = 20
+
+// AccountLockedError refuses a login for an email or from an IP with too
+// many recent failures, until RetryAfter has passed.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("login locked, retry after %s", e.RetryAfter)
+}
+
+// RetryAfterSeconds rounds up, as a client retrying early is refused again.
+func (e *AccountLockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+func loginEmailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// loginLockDuration is how long a subject is locked after failedAttempts:
+// from delayThreshold on it doubles with every failure, starting at
+// DEFAULT_LOGIN_BASE_DELAY, and from lockoutThreshold on it is the whole
+// lockout. A zero threshold turns that step off.
+func loginLockDuration(failedAttempts, delayThreshold, lockoutThreshold int, lockout time.Duration) time.Duration {
+	if lockoutThreshold > 0 && failedAttempts >= lockoutThreshold {
+		return lockout
+	}
+
+	if delayThreshold <= 0 || failedAttempts < delayThreshold {
+		return 0
+	}
+
+	doublings := failedAttempts - delayThreshold
+	if doublings > maxLoginDelayDoublings {
+		return lockout
+	}
+
+	return min(constants.DEFAULT_LOGIN_BASE_DELAY<<doublings, lockout)
+}
+
+// checkLoginLock refuses the login while the email or the IP is locked,
+// before the password is looked at.
+func (s *AuthService) checkLoginLock(ctx context.Context, appID uuid.UUID, email, ipAddress string) error {
+	lockedUntil, err := s.repo.GetLoginLockedUntil(ctx, appID, loginEmailKey(email), ipAddress)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	if lockedUntil == nil {
+		return nil
+	}
+
+	retryAfter := time.Until(*lockedUntil)
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	return &AccountLockedError{RetryAfter: retryAfter}
+}
+
+// recordLoginFailure counts a failed login against the email and the IP and
+// locks them once they reach their thresholds. IPs are not slowed down, as
+// many users may share one.
+func (s *AuthService) recordLoginFailure(ctx context.Context, appID uuid.UUID, email, ipAddress string) {
+	windowStart := time.Now().Add(-s.config.LoginLockoutDuration())
+
+	s.recordLoginFailureFor(ctx, appID, models.LoginSubjectEmail, loginEmailKey(email), windowStart, s.config.LoginDelayThreshold, s.config.LoginLockoutThreshold)
+
+	if ipAddress != "" {
+		s.recordLoginFailureFor(ctx, appID, models.LoginSubjectIP, ipAddress, windowStart, 0, s.config.LoginIPLockoutThreshold)
+	}
+}
+
+func (s *AuthService) recordLoginFailureFor(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string, windowStart time.Time, delayThreshold, lockoutThreshold int) {
+	failedAttempts, err := s.repo.RecordLoginFailure(ctx, appID, subjectType, subject, windowStart)
+	if err != nil {
+		return
+	}
+
+	lockout := s.config.LoginLockoutDuration()
+	lockDuration := loginLockDuration(failedAttempts, delayThreshold, lockoutThreshold, lockout)
+	if lockDuration == 0 {
+		return
+	}
+
+	if err := s.repo.LockLogin(ctx, appID, subjectType, subject, time.Now().Add(lockDuration)); err != nil {
+		return
+	}
+
+	if failedAttempts == lockoutThreshold {
+		appSecurityEvent(models.SecurityEventLoginLocked, appID).
+			Str("subject_type", string(subjectType)).
+			Str("subject", subject).
+			Int("failed_attempts", failedAttempts).
+			Dur("locked_for", lockout).
+			Msg("Too many failed logins, locking out")
+	}
+}
+
+func (s *AuthService) clearLoginFailures(ctx context.Context, appID uuid.UUID, email string) {
+	_, _ = s.repo.DeleteLoginFailures(ctx, appID, models.LoginSubjectEmail, loginEmailKey(email))
+}
+
+// UnlockLogin lifts the lockout and forgets the failed logins of an email,
+// an IP or both. It reports whether there was anything to unlock.
+func (s *AuthService) UnlockLogin(ctx context.Context, appID uuid.UUID, req *models.UnlockLoginRequest) (bool, error) {
+	unlocked := false
+
+	subjects := map[models.LoginSubjectType]string{
+		models.LoginSubjectEmail: loginEmailKey(req.Email),
+		models.LoginSubjectIP:    req.IPAddress,
+	}
+
+	for subjectType, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		deleted, err := s.repo.DeleteLoginFailures(ctx, appID, subjectType, subject)
+		if err != nil {
+			return false, utils.ErrInternalServerError
+		}
+
+		unlocked = unlocked || deleted
+	}
+
+	log("UnlockLogin").Info().Str("app_id", appID.String()).Bool("unlocked", unlocked).Msg("Unlocked login")
+	return unlocked, nil
+}
+
+// WatchLoginFailures forgets failed logins that no longer count every
+// interval until ctx is done.
+func (s *AuthService) WatchLoginFailures(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.repo.PurgeStaleLoginFailures(ctx, time.Now().Add(-s.config.LoginLockoutDuration())); err != nil {
+				log("WatchLoginFailures").Error().Err(err).Msg("Failed to purge stale login failures")
+			}
+		}
+	}
+}
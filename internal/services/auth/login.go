@@ -5,11 +5,62 @@ import (
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func (s *AuthService) LoginWithEmail(ctx context.Context, req *models.LoginWithEmailRequest, options AuthOptions) (*models.LoginResponse, error) {
-	loginWithEmailLog := log("LoginWithEmail")
+	user, err := s.AuthenticateWithEmail(ctx, req, options.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, options)
+}
+
+func (s *AuthService) LoginWithProvider(ctx context.Context, req *models.LoginWithOtherProviderRequest, options AuthOptions) (*models.LoginResponse, error) {
+	user, err := s.AuthenticateWithProvider(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, options)
+}
+
+// LoginByUserID issues a new session for a user that was authenticated
+// earlier, e.g. when an authorization code is redeemed.
+func (s *AuthService) LoginByUserID(ctx context.Context, appID, userID uuid.UUID, options AuthOptions) (*models.LoginResponse, error) {
+	user, err := s.userRepository.GetAppUserByID(ctx, appID, userID)
+	if err != nil {
+		log("LoginByUserID").Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get user")
+		return nil, utils.ErrInternalServerError
+	}
+
+	if user == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	return s.completeLogin(ctx, user, options)
+}
+
+func (s *AuthService) RevokeUserSessions(ctx context.Context, appID, userID uuid.UUID) error {
+	if err := s.repo.RevokeRefreshToken(ctx, appID, userID); err != nil {
+		log("RevokeUserSessions").Error().Err(err).Str("user_id", userID.String()).Msg("Failed to revoke refresh tokens")
+		return utils.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// AuthenticateWithEmail checks the user's password. Failures are counted
+// per email and per ipAddress, which get locked out after too many.
+func (s *AuthService) AuthenticateWithEmail(ctx context.Context, req *models.LoginWithEmailRequest, ipAddress string) (*models.User, error) {
+	loginWithEmailLog := log("AuthenticateWithEmail")
+
+	if err := s.checkLoginLock(ctx, req.AppID, req.Email, ipAddress); err != nil {
+		loginWithEmailLog.Warn().Err(err).Str("app_id", req.AppID.String()).Msg("Login locked")
+		return nil, err
+	}
 
 	user, err := s.userRepository.GetUserByEmail(ctx, req.AppID, req.Email)
 
@@ -22,6 +73,7 @@ func (s *AuthService) LoginWithEmail(ctx context.Context, req *models.LoginWithE
 
 	if user == nil || err != nil {
 		loginWithEmailLog.Error().Err(err).Msg("User not found")
+		s.recordLoginFailure(ctx, req.AppID, req.Email, ipAddress)
 		return nil, errUnauthorized
 	}
 
@@ -29,6 +81,7 @@ func (s *AuthService) LoginWithEmail(ctx context.Context, req *models.LoginWithE
 
 	if err != nil {
 		loginWithEmailLog.Error().Err(err).Msg("User Auth not found")
+		s.recordLoginFailure(ctx, req.AppID, req.Email, ipAddress)
 		return nil, errUnauthorized
 	}
 
@@ -36,23 +89,83 @@ func (s *AuthService) LoginWithEmail(ctx context.Context, req *models.LoginWithE
 
 	if err != nil {
 		loginWithEmailLog.Error().Err(err).Msg("Password not matched")
+		s.recordLoginFailure(ctx, req.AppID, req.Email, ipAddress)
 		return nil, errUnauthorized
 	}
 
-	err = s.repo.RevokeRefreshToken(ctx, user.AppID, user.ID)
+	s.clearLoginFailures(ctx, req.AppID, req.Email)
+
+	if err := s.ensureEmailVerified(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *AuthService) AuthenticateWithProvider(ctx context.Context, req *models.LoginWithOtherProviderRequest) (*models.User, error) {
+	loginWithProviderLog := log("AuthenticateWithProvider")
+
+	var user *models.User
+	var err error
+
+	switch req.Provider {
+	case models.AuthProviderGoogle:
+		user, err = s.findOrCreateGoogleUser(ctx, req.AppID, req.ProviderToken, "")
+	default:
+		err = ErrProviderNotConfigured
+	}
 
 	if err != nil {
-		loginWithEmailLog.Error().Err(err).Msg("Failed to execute RevokeRefreshToken")
+		loginWithProviderLog.Error().Err(err).Str("provider", string(req.Provider)).Msg("Failed to authenticate with provider")
+		return nil, err
+	}
+
+	if err := s.ensureEmailVerified(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *AuthService) ensureEmailVerified(ctx context.Context, user *models.User) error {
+	ensureEmailVerifiedLog := log("ensureEmailVerified")
+
+	settings, err := s.appService.GetSettings(ctx, user.AppID)
+
+	if err != nil {
+		ensureEmailVerifiedLog.Error().Err(err).Str("app_id", user.AppID.String()).Msg("Failed to get app settings")
+		return utils.ErrInternalServerError
+	}
+
+	if settings.RequireEmailVerification && !user.IsEmailVerified {
+		ensureEmailVerifiedLog.Warn().Str("user_id", user.ID.String()).Msg("Email not verified")
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, options AuthOptions) (*models.LoginResponse, error) {
+	completeLoginLog := log("completeLogin")
+
+	options.DeviceID = deviceIDOrNew(options.DeviceID)
+
+	// Only the session of the device logging in is replaced; the user stays
+	// signed in everywhere else.
+	err := s.repo.RevokeDeviceRefreshTokens(ctx, user.AppID, user.ID, options.DeviceID)
+
+	if err != nil {
+		completeLoginLog.Error().Err(err).Msg("Failed to execute RevokeDeviceRefreshTokens")
 		return nil, utils.ErrInternalServerError
 	}
 
-	tokens, err := s.GenerateUserAuthTokens(ctx, user)
+	tokens, err := s.GenerateUserAuthTokens(ctx, user, options)
 
 	if err != nil {
-		loginWithEmailLog.Error().Err(err).Msg("Failed to generate tokens")
+		completeLoginLog.Error().Err(err).Msg("Failed to generate tokens")
 
 		if options.CallbackURL != "" {
-			return &models.LoginResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, nil, false)}, err
+			return &models.LoginResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, false)}, err
 		}
 
 		if options.RedirectURL != "" {
@@ -62,13 +175,18 @@ func (s *AuthService) LoginWithEmail(ctx context.Context, req *models.LoginWithE
 		return nil, err
 	}
 
+	s.sendLoginAlertMail(ctx, user, options)
+
 	loginResponse := &models.LoginResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        expiresIn(tokens.AccessTokenExpiresAt),
+		RefreshExpiresIn: expiresIn(tokens.RefreshTokenExpiresAt),
+		DeviceID:         options.DeviceID,
 	}
 
 	if options.CallbackURL != "" {
-		loginResponse.CallbackURL = buildCallbackURL(options.CallbackURL, tokens, user, true)
+		loginResponse.CallbackURL = buildCallbackURL(options.CallbackURL, user, true)
 	}
 
 	if options.RedirectURL != "" {
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func (s *AuthService) Logout(ctx context.Context, accessToken string) error {
+	return s.logout(ctx, "Logout", accessToken, false)
+}
+
+func (s *AuthService) LogoutAll(ctx context.Context, accessToken string) error {
+	return s.logout(ctx, "LogoutAll", accessToken, true)
+}
+
+func (s *AuthService) logout(ctx context.Context, method string, accessToken string, allSessions bool) error {
+	logoutLog := log(method)
+
+	token, err := s.VerifyAccessToken(ctx, accessToken)
+	if err != nil {
+		logoutLog.Warn().Err(err).Msg("Failed to verify access token during logout")
+		return err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return jwt.ErrTokenInvalidClaims
+	}
+
+	jti, _ := claims["jti"].(string)
+	refreshJTI, _ := claims["refresh_jti"].(string)
+	strUserID, _ := claims["user_id"].(string)
+	strAppID, _ := claims["app_id"].(string)
+
+	userID, errUserID := uuid.Parse(strUserID)
+	appID, errAppID := uuid.Parse(strAppID)
+
+	if errUserID != nil || errAppID != nil {
+		logoutLog.Error().Str("user_id", strUserID).Str("app_id", strAppID).Msg("Failed to parse userID or appID")
+		return fmt.Errorf("%w: user_id or app_id", ErrMissingRequiredClaim)
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return fmt.Errorf("%w: exp", ErrMissingRequiredClaim)
+	}
+
+	err = s.repo.BlacklistToken(ctx, &models.BlacklistToken{
+		JTI:       jti,
+		Token:     hashToken(accessToken),
+		ExpiresAt: expiresAt.Time,
+		Reason:    models.BlacklistReasonLogout,
+	})
+
+	if err != nil {
+		logoutLog.Error().Err(err).Str("jti", jti).Msg("Failed to blacklist access token")
+		return utils.ErrInternalServerError
+	}
+
+	if allSessions {
+		err = s.repo.RevokeRefreshToken(ctx, appID, userID)
+	} else {
+		err = s.repo.RevokeRefreshTokenByJTI(ctx, appID, refreshJTI)
+	}
+
+	if err != nil {
+		logoutLog.Error().Err(err).Str("refresh_jti", refreshJTI).Msg("Failed to revoke refresh token")
+		return utils.ErrInternalServerError
+	}
+
+	return nil
+}
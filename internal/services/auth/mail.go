@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/mailer"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func (s *AuthService) resolveAppName(ctx context.Context, appID uuid.UUID) string {
+	app, err := s.appService.GetApp(ctx, appID.String())
+	if err != nil || app == nil {
+		log("resolveAppName").Warn().Err(err).Str("app_id", appID.String()).Msg("Failed to get app for mail")
+		return ""
+	}
+
+	name, err := s.appService.ParseAppName(string(app.Name))
+	if err != nil {
+		log("resolveAppName").Warn().Err(err).Str("app_id", appID.String()).Msg("Failed to decrypt app name for mail")
+		return ""
+	}
+
+	return name
+}
+
+func buildLink(baseURL string, path string, query url.Values) string {
+	return strings.TrimRight(baseURL, "/") + path + "?" + query.Encode()
+}
+
+func (s *AuthService) buildMailLink(path string, token string) string {
+	query := url.Values{}
+	query.Set("token", token)
+
+	return buildLink(s.config.MailLinkBaseURL, path, query)
+}
+
+func (s *AuthService) sendMail(ctx context.Context, template string, to string, data any) error {
+	msg, err := mailer.Render(template, to, data)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
+}
+
+func (s *AuthService) sendResetPasswordMail(ctx context.Context, user *models.User, token string, expiresAt time.Time) error {
+	return s.sendMail(ctx, mailer.TemplateResetPassword, user.Email, mailer.ResetPasswordData{
+		Name:      user.Name,
+		AppName:   s.resolveAppName(ctx, user.AppID),
+		Link:      s.buildMailLink("/reset-password", token),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *AuthService) sendEmailVerificationMail(ctx context.Context, user *models.User, token string, expiresAt time.Time) error {
+	return s.sendMail(ctx, mailer.TemplateEmailVerification, user.Email, mailer.EmailVerificationData{
+		Name:      user.Name,
+		AppName:   s.resolveAppName(ctx, user.AppID),
+		Link:      s.buildMailLink("/verify-email", token),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *AuthService) sendMagicLinkMail(ctx context.Context, user *models.User, link string, expiresAt time.Time) error {
+	return s.sendMail(ctx, mailer.TemplateMagicLink, user.Email, mailer.MagicLinkData{
+		Name:      user.Name,
+		AppName:   s.resolveAppName(ctx, user.AppID),
+		Link:      link,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// sendLoginAlertMail is fire-and-forget: a failing mail server must never
+// block or fail a successful login.
+func (s *AuthService) sendLoginAlertMail(ctx context.Context, user *models.User, options AuthOptions) {
+	mailCtx := context.WithoutCancel(ctx)
+	loginAt := time.Now()
+
+	go func() {
+		err := s.sendMail(mailCtx, mailer.TemplateLoginAlert, user.Email, mailer.LoginAlertData{
+			Name:      user.Name,
+			AppName:   s.resolveAppName(mailCtx, user.AppID),
+			IPAddress: options.IPAddress,
+			UserAgent: options.UserAgent,
+			LoginAt:   loginAt,
+		})
+
+		if err != nil {
+			log("sendLoginAlertMail").Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send login alert mail")
+		}
+	}()
+}
@@ -2,12 +2,17 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/fransiscushermanto/backend/internal/constants"
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/services/user"
+	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func (s *AuthService) ForgetPassword(ctx context.Context, req *models.ForgetPasswordRequest) error {
@@ -17,7 +22,17 @@ func (s *AuthService) ForgetPassword(ctx context.Context, req *models.ForgetPass
 		Email: req.Email,
 	})
 
-	resetPasswordTokenExpiryTime := constants.DEFAULT_JWT_EXPIRY_HOURS
+	if errors.Is(err, utils.ErrNotFound) {
+		forgetPasswordLog.Info().Msg("No user registered with the given email")
+		return nil
+	}
+
+	if err != nil {
+		forgetPasswordLog.Error().Err(err).Msg("Failed to get user")
+		return err
+	}
+
+	resetPasswordTokenExpiryTime := time.Now().Add(constants.DEFAULT_RESET_PASSWORD_TOKEN_EXPIRY)
 	resetPasswordTokenJTI := generateTokenID()
 	resetPasswordTokenClaims := jwt.MapClaims{
 		"jti":     resetPasswordTokenJTI,
@@ -53,6 +68,78 @@ func (s *AuthService) ForgetPassword(ctx context.Context, req *models.ForgetPass
 		return err
 	}
 
-	forgetPasswordLog.Info().Str("token", *resetToken).Interface("user", user).Msg("Successfully request reset password")
+	if err := s.sendResetPasswordMail(ctx, user, *resetToken, resetPasswordTokenExpiryTime); err != nil {
+		forgetPasswordLog.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send reset password mail")
+		return err
+	}
+
+	forgetPasswordLog.Info().Str("user_id", user.ID.String()).Msg("Successfully request reset password")
+	return nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, req *models.ResetPasswordRequest) error {
+	resetPasswordLog := log("ResetPassword")
+
+	claims, err := s.parseToken(req.Token, "reset-password")
+	if err != nil {
+		resetPasswordLog.Warn().Err(err).Msg("Failed to verify reset password token")
+		return err
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
+	strAppID, _ := claims["app_id"].(string)
+	appID, err := uuid.Parse(strAppID)
+	if err != nil {
+		return fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
+	}
+
+	storedToken, err := s.repo.GetResetPasswordTokenByJTI(ctx, appID, jti)
+	if err != nil {
+		resetPasswordLog.Error().Err(err).Str("jti", jti).Msg("Failed to get reset password token")
+		return utils.ErrInternalServerError
+	}
+
+	if storedToken == nil {
+		return ErrTokenNotFound
+	}
+
+	if storedToken.Token != hashToken(req.Token) {
+		return ErrTokenMismatch
+	}
+
+	if !storedToken.IsActive {
+		return ErrTokenRevoked
+	}
+
+	// The app is only known from the token, so its policy is checked here
+	// rather than when the request is validated.
+	policy, err := s.appService.GetPasswordPolicy(ctx, appID)
+	if err != nil {
+		resetPasswordLog.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to get password policy")
+		return utils.ErrInternalServerError
+	}
+
+	if violation := policy.Violation(req.Password); violation != "" {
+		return utils.NewValidationError([]utils.FieldError{
+			{Field: "password", Message: "Password " + violation},
+		})
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost+2)
+	if err != nil {
+		resetPasswordLog.Error().Err(err).Msg("Failed to hash password")
+		return utils.ErrInternalServerError
+	}
+
+	if err := s.repo.ResetPassword(ctx, storedToken, string(hashedPassword)); err != nil {
+		resetPasswordLog.Error().Err(err).Str("jti", jti).Msg("Failed to reset password")
+		return err
+	}
+
+	resetPasswordLog.Info().Str("user_id", storedToken.UserID.String()).Msg("Successfully reset password")
 	return nil
 }
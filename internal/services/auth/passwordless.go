@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/user"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const magicLinkVerifyPath = "/api/v1/login/passwordless/verify"
+
+func (s *AuthService) RequestMagicLink(ctx context.Context, req *models.LoginWithPasswordlessRequest, linkParams url.Values) error {
+	requestMagicLinkLog := log("RequestMagicLink")
+
+	user, err := s.userService.GetUser(ctx, req.AppID, user.UserIdentifier{
+		Email: &req.Email,
+	})
+
+	if errors.Is(err, utils.ErrNotFound) {
+		requestMagicLinkLog.Info().Msg("No user registered with the given email")
+		return nil
+	}
+
+	if err != nil {
+		requestMagicLinkLog.Error().Err(err).Msg("Failed to get user")
+		return err
+	}
+
+	magicLinkExpiryTime := time.Now().Add(constants.DEFAULT_MAGIC_LINK_EXPIRY)
+	magicLinkJTI := generateTokenID()
+	magicLinkClaims := jwt.MapClaims{
+		"jti":     magicLinkJTI,
+		"user_id": user.ID,
+		"app_id":  user.AppID,
+		"exp":     magicLinkExpiryTime.Unix(),
+		"type":    "magic-link",
+		"iat":     time.Now().Unix(),
+	}
+	magicLinkToken, err := s.GenerateToken(constants.DEFAULT_JWT_SIGNING_METHOD, magicLinkClaims)
+	if err != nil {
+		requestMagicLinkLog.Error().Err(err).Msg("Failed to generate magic link token")
+		return err
+	}
+
+	err = s.repo.RevokeMagicLinkToken(ctx, user.AppID, user.ID)
+
+	if err != nil {
+		requestMagicLinkLog.Error().Err(err).Msg("Failed to revoke magic link token")
+		return err
+	}
+
+	err = s.repo.StoreMagicLinkToken(ctx, &models.MagicLinkToken{
+		JTI:       magicLinkJTI,
+		AppID:     user.AppID,
+		UserID:    user.ID,
+		Token:     hashToken(*magicLinkToken),
+		ExpiresAt: magicLinkExpiryTime,
+	})
+
+	if err != nil {
+		requestMagicLinkLog.Error().Err(err).Msg("Failed to store magic link token")
+		return err
+	}
+
+	query := url.Values{}
+	for key, values := range linkParams {
+		query[key] = values
+	}
+	query.Set("token", *magicLinkToken)
+
+	// The session is opened for the device that asked for the link.
+	if req.DeviceID != "" {
+		query.Set("device_id", req.DeviceID)
+	}
+
+	if req.DeviceName != "" {
+		query.Set("device_name", req.DeviceName)
+	}
+
+	link := buildLink(s.config.PublicURL, magicLinkVerifyPath, query)
+
+	if err := s.sendMagicLinkMail(ctx, user, link, magicLinkExpiryTime); err != nil {
+		requestMagicLinkLog.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send magic link mail")
+		return err
+	}
+
+	requestMagicLinkLog.Info().Str("user_id", user.ID.String()).Msg("Successfully request magic link")
+	return nil
+}
+
+func (s *AuthService) VerifyMagicLink(ctx context.Context, token string, options AuthOptions) (*models.LoginResponse, error) {
+	verifyMagicLinkLog := log("VerifyMagicLink")
+
+	user, err := s.consumeMagicLink(ctx, token)
+	if err != nil {
+		verifyMagicLinkLog.Warn().Err(err).Msg("Failed to consume magic link")
+		return FailedLoginResponse(options), err
+	}
+
+	// Following the link proves ownership of the mailbox, so it doubles as
+	// email verification for apps that require it.
+	if !user.IsEmailVerified {
+		if err := s.userService.MarkEmailVerified(ctx, user.AppID, user.ID); err != nil {
+			verifyMagicLinkLog.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to mark email as verified")
+			return FailedLoginResponse(options), utils.ErrInternalServerError
+		}
+	}
+
+	res, err := s.completeLogin(ctx, user, options)
+	if err != nil && res == nil {
+		return FailedLoginResponse(options), err
+	}
+
+	return res, err
+}
+
+// MagicLinkAppID returns the app a magic link was issued for without using
+// it up, so the request around it can be checked against the app first.
+func (s *AuthService) MagicLinkAppID(token string) (uuid.UUID, error) {
+	return s.tokenAppID(token, "magic-link")
+}
+
+func (s *AuthService) consumeMagicLink(ctx context.Context, token string) (*models.User, error) {
+	claims, err := s.parseToken(token, "magic-link")
+	if err != nil {
+		return nil, err
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
+	strAppID, _ := claims["app_id"].(string)
+	appID, err := uuid.Parse(strAppID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
+	}
+
+	storedToken, err := s.repo.GetMagicLinkTokenByJTI(ctx, appID, jti)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if storedToken == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	if storedToken.Token != hashToken(token) {
+		return nil, ErrTokenMismatch
+	}
+
+	if !storedToken.IsActive {
+		return nil, ErrTokenRevoked
+	}
+
+	if err := s.repo.UseMagicLinkToken(ctx, storedToken.AppID, storedToken.JTI); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetUser(ctx, storedToken.AppID, user.UserIdentifier{
+		ID: &storedToken.UserID,
+	})
+
+	if errors.Is(err, utils.ErrNotFound) {
+		return nil, utils.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	return user, nil
+}
@@ -9,22 +9,30 @@ import (
 func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest, options AuthOptions) (*models.RegisterResponse, error) {
 	registerLog := log("Register")
 
-	createUserReq := &models.CreateUserRequest{
-		AppID:         req.AppID,
-		Name:          req.Name,
-		Provider:      req.Provider,
-		ProviderToken: req.ProviderToken,
-		Email:         req.Email,
-		Password:      req.Password,
-	}
+	var user *models.User
+	var err error
+
+	if req.Provider == models.AuthProviderGoogle {
+		// The verified Google email is authoritative over the submitted one.
+		user, err = s.findOrCreateGoogleUser(ctx, req.AppID, req.ProviderToken, req.Name)
+	} else {
+		createUserReq := &models.CreateUserRequest{
+			AppID:         req.AppID,
+			Name:          req.Name,
+			Provider:      req.Provider,
+			ProviderToken: req.ProviderToken,
+			Email:         req.Email,
+			Password:      req.Password,
+		}
 
-	user, err := s.userService.CreateUser(ctx, createUserReq)
+		user, err = s.userService.CreateUser(ctx, createUserReq)
+	}
 
 	if err != nil {
 		registerLog.Error().Err(err).Msg("Failed to create user")
 
 		if options.CallbackURL != "" {
-			return &models.RegisterResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, nil, false)}, err
+			return &models.RegisterResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, false)}, err
 		}
 
 		if options.RedirectURL != "" {
@@ -34,12 +42,14 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest,
 		return nil, err
 	}
 
-	tokens, err := s.GenerateUserAuthTokens(ctx, user)
+	options.DeviceID = deviceIDOrNew(options.DeviceID)
+
+	tokens, err := s.GenerateUserAuthTokens(ctx, user, options)
 	if err != nil {
 		registerLog.Error().Err(err).Msg("Failed to generate tokens")
 
 		if options.CallbackURL != "" {
-			return &models.RegisterResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, nil, false)}, err
+			return &models.RegisterResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, false)}, err
 		}
 
 		if options.RedirectURL != "" {
@@ -50,12 +60,15 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest,
 	}
 
 	registerResponse := &models.RegisterResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        expiresIn(tokens.AccessTokenExpiresAt),
+		RefreshExpiresIn: expiresIn(tokens.RefreshTokenExpiresAt),
+		DeviceID:         options.DeviceID,
 	}
 
 	if options.CallbackURL != "" {
-		registerResponse.CallbackURL = buildCallbackURL(options.CallbackURL, tokens, user, true)
+		registerResponse.CallbackURL = buildCallbackURL(options.CallbackURL, user, true)
 	}
 
 	if options.RedirectURL != "" {
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// RevokeToken revokes a single token issued to appID (RFC 7009). A refresh
+// token deactivates its session, which also invalidates the access tokens
+// minted from it; an access or client token is blacklisted until it expires.
+// Expired tokens are already unusable and are left alone.
+func (s *AuthService) RevokeToken(ctx context.Context, appID uuid.UUID, token string) error {
+	revokeTokenLog := log("RevokeToken")
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, s.keyFunc); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil
+		}
+
+		return err
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
+	if tokenAppID, _ := claims["app_id"].(string); tokenAppID != appID.String() {
+		return ErrTokenAppMismatch
+	}
+
+	switch tokenType, _ := claims["type"].(string); tokenType {
+	case "refresh":
+		if err := s.repo.RevokeRefreshTokenByJTI(ctx, appID, jti); err != nil {
+			revokeTokenLog.Error().Err(err).Str("jti", jti).Msg("Failed to revoke refresh token")
+			return utils.ErrInternalServerError
+		}
+	case "access", "client":
+		expiresAt, err := claims.GetExpirationTime()
+		if err != nil || expiresAt == nil {
+			return fmt.Errorf("%w: exp", ErrMissingRequiredClaim)
+		}
+
+		err = s.repo.BlacklistToken(ctx, &models.BlacklistToken{
+			JTI:       jti,
+			Token:     hashToken(token),
+			ExpiresAt: expiresAt.Time,
+			Reason:    models.BlacklistReasonRevoke,
+		})
+
+		if err != nil {
+			revokeTokenLog.Error().Err(err).Str("jti", jti).Msg("Failed to blacklist token")
+			return utils.ErrInternalServerError
+		}
+	default:
+		return ErrInvalidTokenType
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// securityEvent logs events that may mean an account is under attack under
+// a stable security_event field, so they can be alerted on.
+func securityEvent(event models.SecurityEventType, appID, userID uuid.UUID) *zerolog.Event {
+	return appSecurityEvent(event, appID).Str("user_id", userID.String())
+}
+
+// appSecurityEvent is securityEvent for events not tied to a known user.
+func appSecurityEvent(event models.SecurityEventType, appID uuid.UUID) *zerolog.Event {
+	return utils.Log().Warn().
+		Str("service", "Auth").
+		Str("security_event", string(event)).
+		Str("app_id", appID.String())
+}
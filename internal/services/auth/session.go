@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// ListSessions returns the user's signed-in devices, most recent first.
+// currentJTI is the refresh JTI of the caller's access token.
+func (s *AuthService) ListSessions(ctx context.Context, appID, userID uuid.UUID, currentJTI string) ([]models.Session, error) {
+	refreshTokens, err := s.repo.GetUserActiveRefreshTokens(ctx, appID, &userID, nil)
+	if err != nil {
+		log("ListSessions").Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get active refresh tokens")
+		return nil, utils.ErrInternalServerError
+	}
+
+	now := time.Now()
+	sessions := make([]models.Session, 0, len(*refreshTokens))
+
+	for _, token := range *refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			continue
+		}
+
+		sessions = append(sessions, models.Session{
+			ID:         token.JTI,
+			DeviceID:   token.DeviceID,
+			DeviceName: token.DeviceName,
+			IPAddress:  token.IPAddress,
+			UserAgent:  token.UserAgent,
+			CreatedAt:  token.CreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.JTI == currentJTI,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's devices out. Access tokens of that
+// session stop working right away, as they are checked against it.
+func (s *AuthService) RevokeSession(ctx context.Context, appID, userID uuid.UUID, jti string) error {
+	revoked, err := s.repo.RevokeUserRefreshTokenByJTI(ctx, appID, userID, jti)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	if !revoked {
+		return utils.ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions signs every device out except the caller's.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, appID, userID uuid.UUID, currentJTI string) error {
+	if err := s.repo.RevokeOtherRefreshTokens(ctx, appID, userID, currentJTI); err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// enforceMaxSessions signs the user's oldest devices out once they have more
+// than maxSessions sessions.
+func (s *AuthService) enforceMaxSessions(ctx context.Context, appID, userID uuid.UUID, maxSessions int) error {
+	enforceMaxSessionsLog := log("enforceMaxSessions")
+
+	refreshTokens, err := s.repo.GetUserActiveRefreshTokens(ctx, appID, &userID, nil)
+	if err != nil {
+		enforceMaxSessionsLog.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get active refresh tokens")
+		return utils.ErrInternalServerError
+	}
+
+	now := time.Now()
+	sessions := 0
+
+	// Most recent first, so the sessions past the limit are the oldest.
+	for _, token := range *refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			continue
+		}
+
+		sessions++
+		if sessions <= maxSessions {
+			continue
+		}
+
+		if _, err := s.repo.RevokeUserRefreshTokenByJTI(ctx, appID, userID, token.JTI); err != nil {
+			return utils.ErrInternalServerError
+		}
+
+		enforceMaxSessionsLog.Info().Str("user_id", userID.String()).Str("jti", token.JTI).Int("max_sessions", maxSessions).Msg("Signed out oldest session over the limit")
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
@@ -9,45 +11,84 @@ import (
 	"github.com/google/uuid"
 )
 
-func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString string) (*models.RefreshTokenResponse, error) {
+// RefreshToken rotates a refresh token: the presented token is marked as
+// rotated and a new pair is minted in the same family. Presenting a rotated
+// token again means it was copied, so the whole family is revoked. The token
+// can only be refreshed from the device it was issued to.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString string, options AuthOptions) (*models.RefreshTokenResponse, error) {
 	log := log("RefreshToken")
 
-	token, err := s.VerifyRefreshToken(ctx, refreshTokenString)
+	_, storedToken, err := s.verifyRefreshToken(ctx, refreshTokenString)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.revokeReusedTokenFamily(ctx, storedToken)
+	}
+
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to verify refresh token during refresh")
 		return nil, jwt.ErrTokenExpired
 	}
 
-	claims, _ := token.Claims.(jwt.MapClaims)
-	jti := claims["jti"].(string)
-	strUserID := claims["user_id"].(string)
-	strAppID := claims["app_id"].(string)
-
-	userID, errUserID := uuid.Parse(strUserID)
-	appID, errAppID := uuid.Parse(strAppID)
+	if storedToken.DeviceID != options.DeviceID {
+		securityEvent(models.SecurityEventRefreshTokenDeviceMismatch, storedToken.AppID, storedToken.UserID).
+			Str("jti", storedToken.JTI).
+			Str("device_id", options.DeviceID).
+			Str("token_device_id", storedToken.DeviceID).
+			Msg("Refresh token presented from another device")
+		return nil, ErrDeviceMismatch
+	}
 
-	if errUserID != nil || errAppID != nil {
-		log.Error().Err(err).Str("user_id", strUserID).Str("app_id", strAppID).Msg("Failed to parse userID or appID")
-		return nil, utils.ErrInternalServerError
+	settings, err := s.appService.GetSettings(ctx, storedToken.AppID)
+	if err != nil {
+		log.Error().Err(err).Str("app_id", storedToken.AppID.String()).Msg("Failed to get app settings")
+		return nil, err
 	}
 
-	if err := s.repo.RevokeRefreshToken(ctx, appID, userID); err != nil {
-		log.Error().Err(err).Str("jti", jti).Msg("Failed to revoke used refresh token")
-		return nil, utils.ErrInternalServerError
+	// Tokens are capped to the session lifetime when issued; this catches
+	// sessions started before the app shortened it.
+	if lifetime := settings.SessionLifetime(); lifetime > 0 && time.Now().After(storedToken.CreatedAt.Add(lifetime)) {
+		log.Info().Str("jti", storedToken.JTI).Msg("Session reached its lifetime")
+		return nil, jwt.ErrTokenExpired
 	}
 
-	user, err := s.userRepository.GetAppUserByID(ctx, appID, userID)
+	user, err := s.userRepository.GetAppUserByID(ctx, storedToken.AppID, storedToken.UserID)
 	if err != nil || user == nil {
-		log.Error().Err(err).Str("user_id", userID.String()).Msg("User not found for refresh token")
+		log.Error().Err(err).Str("user_id", storedToken.UserID.String()).Msg("User not found for refresh token")
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	tokens, err := s.GenerateUserAuthTokens(ctx, user)
+	tokens, err := s.generateUserAuthTokens(ctx, user, options, storedToken)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.revokeReusedTokenFamily(ctx, storedToken)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RefreshTokenResponse{
+		AccessToken:      tokens.AccessToken,
+		RefreshToken:     tokens.RefreshToken,
+		ExpiresIn:        expiresIn(tokens.AccessTokenExpiresAt),
+		RefreshExpiresIn: expiresIn(tokens.RefreshTokenExpiresAt),
+	}, nil
+}
+
+// RefreshTokenAppID returns the app a refresh token was issued to without
+// rotating it, so the client presenting it can be checked first.
+func (s *AuthService) RefreshTokenAppID(token string) (uuid.UUID, error) {
+	return s.tokenAppID(token, "refresh")
+}
+
+func (s *AuthService) revokeReusedTokenFamily(ctx context.Context, storedToken *models.RefreshToken) error {
+	securityEvent(models.SecurityEventRefreshTokenReused, storedToken.AppID, storedToken.UserID).
+		Str("family_id", storedToken.FamilyID).
+		Str("jti", storedToken.JTI).
+		Msg("Rotated refresh token presented again, revoking its family")
 
-	newTokens := &models.RefreshTokenResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
+	if err := s.repo.RevokeRefreshTokenFamily(ctx, storedToken.AppID, storedToken.FamilyID); err != nil {
+		log("RefreshToken").Error().Err(err).Str("family_id", storedToken.FamilyID).Msg("Failed to revoke refresh token family")
+		return utils.ErrInternalServerError
 	}
 
-	return newTokens, err
+	return ErrRefreshTokenReused
 }
@@ -2,10 +2,15 @@ package auth
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
+	"time"
 
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/mailer"
 	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/app"
 	"github.com/fransiscushermanto/backend/internal/services/user"
 	"github.com/google/uuid"
 )
@@ -15,26 +20,58 @@ type AuthRepository interface {
 	GetRefreshTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.RefreshToken, error)
 	GetUserActiveRefreshTokens(ctx context.Context, appID uuid.UUID, userID *uuid.UUID, jti *string) (*[]models.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, appID, userID uuid.UUID) error
+	RevokeRefreshTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) error
+	RotateRefreshToken(ctx context.Context, parent, token *models.RefreshToken) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, appID uuid.UUID, familyID string) error
+	RevokeDeviceRefreshTokens(ctx context.Context, appID, userID uuid.UUID, deviceID string) error
+	RevokeUserRefreshTokenByJTI(ctx context.Context, appID, userID uuid.UUID, jti string) (bool, error)
+	RevokeOtherRefreshTokens(ctx context.Context, appID, userID uuid.UUID, jti string) error
+	BlacklistToken(ctx context.Context, token *models.BlacklistToken) error
+	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error)
 	StoreResetPasswordToken(ctx context.Context, token *models.ResetPasswordToken) error
 	RevokeResetPasswordToken(ctx context.Context, appID, userID uuid.UUID) error
+	GetResetPasswordTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.ResetPasswordToken, error)
+	ResetPassword(ctx context.Context, token *models.ResetPasswordToken, hashedPassword string) error
+	StoreEmailVerificationToken(ctx context.Context, token *models.EmailVerificationToken) error
+	RevokeEmailVerificationToken(ctx context.Context, appID, userID uuid.UUID) error
+	GetEmailVerificationTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.EmailVerificationToken, error)
+	ConfirmEmailVerification(ctx context.Context, token *models.EmailVerificationToken) error
+	StoreMagicLinkToken(ctx context.Context, token *models.MagicLinkToken) error
+	RevokeMagicLinkToken(ctx context.Context, appID, userID uuid.UUID) error
+	GetMagicLinkTokenByJTI(ctx context.Context, appID uuid.UUID, jti string) (*models.MagicLinkToken, error)
+	UseMagicLinkToken(ctx context.Context, appID uuid.UUID, jti string) error
+	RecordLoginFailure(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string, windowStart time.Time) (int, error)
+	LockLogin(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string, lockedUntil time.Time) error
+	GetLoginLockedUntil(ctx context.Context, appID uuid.UUID, email, ipAddress string) (*time.Time, error)
+	DeleteLoginFailures(ctx context.Context, appID uuid.UUID, subjectType models.LoginSubjectType, subject string) (bool, error)
+	PurgeStaleLoginFailures(ctx context.Context, lastFailedBefore time.Time) (int64, error)
 }
 
 type AuthService struct {
 	repo           AuthRepository
 	userRepository user.UserRepository
 	userService    *user.UserService
-	privateKey     *ecdsa.PrivateKey
-	publicKey      *ecdsa.PublicKey
+	appService     *app.AppService
+	mailer         mailer.Mailer
+	config         *config.AppConfig
+	googleJWKS     *jwks.Cache
+	keys           *keyring.KeyRing
 }
 
 type AuthOptions struct {
 	CallbackURL string
 	RedirectURL string
+	IPAddress   string
+	UserAgent   string
+	DeviceID    string
+	DeviceName  string
 }
 
 type AuthTokens struct {
-	AccessToken  string
-	RefreshToken string
+	AccessToken           string
+	RefreshToken          string
+	AccessTokenExpiresAt  time.Time
+	RefreshTokenExpiresAt time.Time
 }
 
 var (
@@ -43,4 +80,13 @@ var (
 	ErrTokenNotFound           = errors.New("token not found in storage")
 	ErrTokenMismatch           = errors.New("stored token does not match provided token")
 	ErrMissingRequiredClaim    = errors.New("token is missing a required claim")
+	ErrTokenBlacklisted        = errors.New("token has been blacklisted")
+	ErrInvalidTokenType        = errors.New("unexpected token type")
+	ErrTokenAppMismatch        = errors.New("token was issued to another app")
+	ErrRefreshTokenReused      = errors.New("refresh token has already been rotated")
+	ErrDeviceMismatch          = errors.New("refresh token belongs to another device")
+	ErrEmailNotVerified        = errors.New("email address has not been verified")
+	ErrProviderNotConfigured   = errors.New("auth provider is not configured")
+	ErrInvalidProviderToken    = errors.New("invalid provider token")
+	ErrProviderAccountConflict = errors.New("an account with this email already exists")
 )
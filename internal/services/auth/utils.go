@@ -2,7 +2,6 @@ package auth
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
@@ -11,17 +10,75 @@ import (
 	"time"
 
 	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/jwks"
 	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/fransiscushermanto/backend/internal/utils"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-func (s *AuthService) GenerateUserAuthTokens(ctx context.Context, user *models.User) (*AuthTokens, error) {
+// GenerateUserAuthTokens starts a new session, i.e. a new refresh token
+// family, on the device given in options.
+func (s *AuthService) GenerateUserAuthTokens(ctx context.Context, user *models.User, options AuthOptions) (*AuthTokens, error) {
+	return s.generateUserAuthTokens(ctx, user, options, nil)
+}
+
+// deviceIDOrNew returns deviceID, or a fresh one for clients that did not
+// send any. The client has to present it again when refreshing.
+func deviceIDOrNew(deviceID string) string {
+	if deviceID != "" {
+		return deviceID
+	}
+
+	return uuid.NewString()
+}
+
+// expiresIn is the lifetime left of a token expiring at expiresAt, in
+// seconds as OAuth's expires_in.
+func expiresIn(expiresAt time.Time) int {
+	return int(time.Until(expiresAt).Round(time.Second).Seconds())
+}
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// generateUserAuthTokens mints an access and refresh token pair bound to the
+// device in options, with the TTLs of the user's app. With a parent, the
+// refresh token joins the parent's family and keeps its device and start
+// time, so neither token outlives the app's session lifetime.
+func (s *AuthService) generateUserAuthTokens(ctx context.Context, user *models.User, options AuthOptions, parent *models.RefreshToken) (*AuthTokens, error) {
 	generateUserAuthTokensLog := log("GenerateUserAuthTokens")
 
-	accessTokenExpireTime := time.Now().Add(time.Minute * 30)     // 30 minutes
-	refreshTokenExpireTime := time.Now().Add(time.Hour * 24 * 30) // 30 days
+	settings, err := s.appService.GetSettings(ctx, user.AppID)
+	if err != nil {
+		generateUserAuthTokensLog.Error().Err(err).Str("app_id", user.AppID.String()).Msg("Failed to get app settings")
+		return nil, err
+	}
+
+	now := time.Now()
+	sessionStartedAt := now
+	if parent != nil {
+		sessionStartedAt = parent.CreatedAt
+	}
+
+	accessTokenExpireTime := now.Add(settings.AccessTokenTTL())
+	refreshTokenExpireTime := now.Add(settings.RefreshTokenTTL())
+
+	if lifetime := settings.SessionLifetime(); lifetime > 0 {
+		sessionEndsAt := sessionStartedAt.Add(lifetime)
+		if sessionEndsAt.Before(refreshTokenExpireTime) {
+			refreshTokenExpireTime = sessionEndsAt
+		}
+	}
+
+	if refreshTokenExpireTime.Before(accessTokenExpireTime) {
+		accessTokenExpireTime = refreshTokenExpireTime
+	}
 
 	refreshJTI := generateTokenID()
 	refreshTokenClaims := jwt.MapClaims{
@@ -57,30 +114,76 @@ func (s *AuthService) GenerateUserAuthTokens(ctx context.Context, user *models.U
 		}, err
 	}
 
-	err = s.repo.StoreRefreshToken(ctx, &models.RefreshToken{
-		JTI:       refreshJTI,
-		AppID:     user.AppID,
-		UserID:    user.ID,
-		Token:     hashToken(*refreshToken),
-		ExpiresAt: refreshTokenExpireTime,
-		CreatedAt: time.Now(),
-		IsActive:  true,
-	})
+	storedRefreshToken := &models.RefreshToken{
+		JTI:        refreshJTI,
+		AppID:      user.AppID,
+		UserID:     user.ID,
+		Token:      hashToken(*refreshToken),
+		ExpiresAt:  refreshTokenExpireTime,
+		CreatedAt:  time.Now(),
+		IsActive:   true,
+		FamilyID:   refreshJTI,
+		DeviceID:   options.DeviceID,
+		DeviceName: nilIfEmpty(options.DeviceName),
+		IPAddress:  nilIfEmpty(options.IPAddress),
+		UserAgent:  nilIfEmpty(options.UserAgent),
+	}
 
-	if err != nil {
+	if parent != nil {
+		storedRefreshToken.FamilyID = parent.FamilyID
+		storedRefreshToken.ParentJTI = &parent.JTI
+		storedRefreshToken.DeviceID = parent.DeviceID
+		storedRefreshToken.DeviceName = parent.DeviceName
+		storedRefreshToken.CreatedAt = parent.CreatedAt
+
+		// Two requests racing with the same token can both pass verification;
+		// only the one that rotates it wins, the other is a reuse.
+		rotated, err := s.repo.RotateRefreshToken(ctx, parent, storedRefreshToken)
+		if err != nil {
+			generateUserAuthTokensLog.Error().Err(err).Str("jti", parent.JTI).Msg("Failed to rotate refresh token")
+			return nil, utils.ErrInternalServerError
+		}
+
+		if !rotated {
+			return nil, ErrRefreshTokenReused
+		}
+	} else if err := s.repo.StoreRefreshToken(ctx, storedRefreshToken); err != nil {
 		generateUserAuthTokensLog.Error().Err(err).Msg("Failed to store refresh token")
 		return nil, err
 	}
 
+	if parent == nil && settings.MaxSessions > 0 {
+		if err := s.enforceMaxSessions(ctx, user.AppID, user.ID, settings.MaxSessions); err != nil {
+			return nil, err
+		}
+	}
+
 	return &AuthTokens{
-		AccessToken:  *accessToken,
-		RefreshToken: *refreshToken,
+		AccessToken:           *accessToken,
+		RefreshToken:          *refreshToken,
+		AccessTokenExpiresAt:  accessTokenExpireTime,
+		RefreshTokenExpiresAt: refreshTokenExpireTime,
 	}, nil
 }
 
+// GenerateToken signs claims with the current signing key. The kid header
+// and iss claim let verifiers pick the right key from /.well-known/jwks.json.
 func (s *AuthService) GenerateToken(signingMethod jwt.SigningMethod, claims jwt.Claims) (*string, error) {
+	signingKey, err := s.keys.SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if mapClaims, ok := claims.(jwt.MapClaims); ok {
+		if _, exists := mapClaims["iss"]; !exists {
+			mapClaims["iss"] = s.config.Issuer
+		}
+	}
+
 	token := jwt.NewWithClaims(signingMethod, claims)
-	tokenString, err := token.SignedString(s.privateKey)
+	token.Header["kid"] = signingKey.Kid
+
+	tokenString, err := token.SignedString(signingKey.PrivateKey)
 
 	if err != nil {
 		return nil, err
@@ -90,62 +193,61 @@ func (s *AuthService) GenerateToken(signingMethod jwt.SigningMethod, claims jwt.
 }
 
 func (s *AuthService) VerifyRefreshToken(ctx context.Context, token string) (*jwt.Token, error) {
-	jwtToken, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
-		if _, ok := jwtToken.Method.(*jwt.SigningMethodECDSA); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
+	jwtToken, _, err := s.verifyRefreshToken(ctx, token)
+	return jwtToken, err
+}
 
-		return s.publicKey, nil
-	})
+// verifyRefreshToken also returns the stored token, when there is one, even
+// if it is no longer active, so callers can act on its family.
+func (s *AuthService) verifyRefreshToken(ctx context.Context, token string) (*jwt.Token, *models.RefreshToken, error) {
+	jwtToken, err := jwt.Parse(token, s.keyFunc)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	claims, ok := jwtToken.Claims.(jwt.MapClaims)
 	if !ok {
-		return nil, jwt.ErrTokenInvalidClaims
+		return nil, nil, jwt.ErrTokenInvalidClaims
 	}
 
 	jti, ok := claims["jti"].(string)
 	if !ok {
-		return nil, fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+		return nil, nil, fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
 	}
 
 	strAppID, ok := claims["app_id"].(string)
 	appID, err := uuid.Parse(strAppID)
 	if !ok || err != nil {
-		return nil, fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
+		return nil, nil, fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
 	}
 
 	storedToken, err := s.repo.GetRefreshTokenByJTI(ctx, appID, jti)
 	if err != nil {
-		return nil, utils.ErrInternalServerError
+		return nil, nil, utils.ErrInternalServerError
 	}
 
 	if storedToken == nil {
-		return nil, ErrTokenNotFound
+		return nil, nil, ErrTokenNotFound
 	}
 
 	if storedToken.Token != hashToken(token) {
-		return nil, ErrTokenMismatch
+		return nil, nil, ErrTokenMismatch
 	}
 
 	if !storedToken.IsActive {
-		return nil, ErrTokenRevoked
+		if storedToken.RotatedAt != nil {
+			return nil, storedToken, ErrRefreshTokenReused
+		}
+
+		return nil, storedToken, ErrTokenRevoked
 	}
 
-	return jwtToken, nil
+	return jwtToken, storedToken, nil
 }
 
 func (s *AuthService) VerifyAccessToken(ctx context.Context, token string) (*jwt.Token, error) {
-	jwtToken, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
-		if _, ok := jwtToken.Method.(*jwt.SigningMethodECDSA); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-
-		return s.publicKey, nil
-	})
+	jwtToken, err := jwt.Parse(token, s.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -157,6 +259,16 @@ func (s *AuthService) VerifyAccessToken(ctx context.Context, token string) (*jwt
 		return nil, jwt.ErrTokenInvalidClaims
 	}
 
+	if claimType, _ := claims["type"].(string); claimType != "access" {
+		return nil, ErrInvalidTokenType
+	}
+
+	jti, ok := claims["jti"].(string)
+
+	if !ok {
+		return nil, fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
 	refreshJTI, ok := claims["refresh_jti"].(string)
 
 	if !ok {
@@ -170,6 +282,16 @@ func (s *AuthService) VerifyAccessToken(ctx context.Context, token string) (*jwt
 		return nil, fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
 	}
 
+	blacklisted, err := s.repo.IsTokenBlacklisted(ctx, jti)
+
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if blacklisted {
+		return nil, ErrTokenBlacklisted
+	}
+
 	activeRefreshTokens, err := s.repo.GetUserActiveRefreshTokens(ctx, appID, nil, &refreshJTI)
 
 	if err != nil {
@@ -183,12 +305,66 @@ func (s *AuthService) VerifyAccessToken(ctx context.Context, token string) (*jwt
 	return jwtToken, nil
 }
 
-func (s *AuthService) GetPublicKey() *ecdsa.PublicKey {
-	return s.publicKey
+func (s *AuthService) parseToken(token string, tokenType string) (jwt.MapClaims, error) {
+	jwtToken, err := jwt.Parse(token, s.keyFunc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if claimType, _ := claims["type"].(string); claimType != tokenType {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
+// tokenAppID returns the app_id claim of a token of tokenType once its
+// signature is verified.
+func (s *AuthService) tokenAppID(token string, tokenType string) (uuid.UUID, error) {
+	claims, err := s.parseToken(token, tokenType)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	strAppID, _ := claims["app_id"].(string)
+	appID, err := uuid.Parse(strAppID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
+	}
+
+	return appID, nil
+}
+
+// keyFunc picks the verification key named by the token's kid header.
+// Tokens issued before kids were stamped are tried against every key that
+// has not been retired.
+func (s *AuthService) keyFunc(jwtToken *jwt.Token) (interface{}, error) {
+	if _, ok := jwtToken.Method.(*jwt.SigningMethodECDSA); !ok {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	kid, _ := jwtToken.Header["kid"].(string)
+	if kid == "" {
+		keySet := jwt.VerificationKeySet{}
+		for _, publicKey := range s.keys.VerificationKeys() {
+			keySet.Keys = append(keySet.Keys, publicKey)
+		}
+
+		return keySet, nil
+	}
+
+	return s.keys.VerificationKey(kid)
 }
 
-func (s *AuthService) GetPrivateKey() *ecdsa.PrivateKey {
-	return s.privateKey
+// JWKS returns the public half of every key that still verifies tokens.
+func (s *AuthService) JWKS() jwks.JSONWebKeySet {
+	return s.keys.JWKS()
 }
 
 func hashToken(token string) string {
@@ -204,7 +380,10 @@ func generateTokenID() string {
 
 var errorCode = "registration_failed"
 
-func buildCallbackURL(baseURL string, tokens *AuthTokens, user *models.User, success bool) string {
+// buildCallbackURL never carries tokens: URLs end up in browser history and
+// access logs. Clients read the tokens from cookies (set_cookie=true) or use
+// the /oauth/authorize flow instead.
+func buildCallbackURL(baseURL string, user *models.User, success bool) string {
 	callbackURL, err := url.Parse(baseURL)
 	if err != nil {
 		log("buildCallbackURL").Error().Err(err).Msg("Invalid callback URL")
@@ -215,8 +394,6 @@ func buildCallbackURL(baseURL string, tokens *AuthTokens, user *models.User, suc
 
 	if success {
 		query.Set("success", "true")
-		query.Set("access_token", tokens.AccessToken)
-		query.Set("refresh_token", tokens.RefreshToken)
 		query.Set("user_id", user.ID.String())
 	} else {
 		query.Set("success", "false")
@@ -245,3 +422,22 @@ func buildRedirectURL(baseURL string, success bool) string {
 	redirectURL.RawQuery = query.Encode()
 	return redirectURL.String()
 }
+
+// FailedLoginResponse builds the failure callback or redirect URL for flows
+// that answer with a browser redirect, or nil for plain JSON responses.
+func FailedLoginResponse(options AuthOptions) *models.LoginResponse {
+	if options.CallbackURL != "" {
+		return &models.LoginResponse{CallbackURL: buildCallbackURL(options.CallbackURL, nil, false)}
+	}
+
+	if options.RedirectURL != "" {
+		return &models.LoginResponse{RedirectURL: buildRedirectURL(options.RedirectURL, false)}
+	}
+
+	return nil
+}
+
+// GetPasswordPolicy returns the rules passwords of the app's users follow.
+func (s *AuthService) GetPasswordPolicy(ctx context.Context, appID uuid.UUID) (models.PasswordPolicy, error) {
+	return s.appService.GetPasswordPolicy(ctx, appID)
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/user"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func (s *AuthService) RequestEmailVerification(ctx context.Context, req *models.VerifyEmailRequest) error {
+	requestEmailVerificationLog := log("RequestEmailVerification")
+
+	user, err := s.userService.GetUser(ctx, *req.AppID, user.UserIdentifier{
+		Email: req.Email,
+	})
+
+	if errors.Is(err, utils.ErrNotFound) {
+		requestEmailVerificationLog.Info().Msg("No user registered with the given email")
+		return nil
+	}
+
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Failed to get user")
+		return err
+	}
+
+	if user.IsEmailVerified {
+		requestEmailVerificationLog.Info().Str("user_id", user.ID.String()).Msg("Email already verified")
+		return nil
+	}
+
+	verificationTokenExpiryTime := time.Now().Add(time.Hour * 24)
+	verificationTokenJTI := generateTokenID()
+	verificationTokenClaims := jwt.MapClaims{
+		"jti":     verificationTokenJTI,
+		"user_id": user.ID,
+		"app_id":  user.AppID,
+		"email":   user.Email,
+		"exp":     verificationTokenExpiryTime.Unix(),
+		"type":    "email-verification",
+		"iat":     time.Now().Unix(),
+	}
+	verificationToken, err := s.GenerateToken(constants.DEFAULT_JWT_SIGNING_METHOD, verificationTokenClaims)
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Failed to generate email verification token")
+		return err
+	}
+
+	err = s.repo.RevokeEmailVerificationToken(ctx, user.AppID, user.ID)
+
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Failed to revoke email verification token")
+		return err
+	}
+
+	err = s.repo.StoreEmailVerificationToken(ctx, &models.EmailVerificationToken{
+		JTI:       verificationTokenJTI,
+		AppID:     user.AppID,
+		UserID:    user.ID,
+		Token:     hashToken(*verificationToken),
+		ExpiresAt: verificationTokenExpiryTime,
+	})
+
+	if err != nil {
+		requestEmailVerificationLog.Error().Err(err).Msg("Failed to store email verification token")
+		return err
+	}
+
+	if err := s.sendEmailVerificationMail(ctx, user, *verificationToken, verificationTokenExpiryTime); err != nil {
+		requestEmailVerificationLog.Error().Err(err).Str("user_id", user.ID.String()).Msg("Failed to send email verification mail")
+		return err
+	}
+
+	requestEmailVerificationLog.Info().Str("user_id", user.ID.String()).Msg("Successfully request email verification")
+	return nil
+}
+
+func (s *AuthService) ConfirmEmailVerification(ctx context.Context, req *models.ConfirmEmailVerificationRequest) error {
+	confirmEmailVerificationLog := log("ConfirmEmailVerification")
+
+	claims, err := s.parseToken(req.Token, "email-verification")
+	if err != nil {
+		confirmEmailVerificationLog.Warn().Err(err).Msg("Failed to verify email verification token")
+		return err
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return fmt.Errorf("%w: jti", ErrMissingRequiredClaim)
+	}
+
+	strAppID, _ := claims["app_id"].(string)
+	appID, err := uuid.Parse(strAppID)
+	if err != nil {
+		return fmt.Errorf("%w: app_id", ErrMissingRequiredClaim)
+	}
+
+	storedToken, err := s.repo.GetEmailVerificationTokenByJTI(ctx, appID, jti)
+	if err != nil {
+		confirmEmailVerificationLog.Error().Err(err).Str("jti", jti).Msg("Failed to get email verification token")
+		return utils.ErrInternalServerError
+	}
+
+	if storedToken == nil {
+		return ErrTokenNotFound
+	}
+
+	if storedToken.Token != hashToken(req.Token) {
+		return ErrTokenMismatch
+	}
+
+	if !storedToken.IsActive {
+		return ErrTokenRevoked
+	}
+
+	if err := s.repo.ConfirmEmailVerification(ctx, storedToken); err != nil {
+		confirmEmailVerificationLog.Error().Err(err).Str("jti", jti).Msg("Failed to confirm email verification")
+		return err
+	}
+
+	confirmEmailVerificationLog.Info().Str("user_id", storedToken.UserID.String()).Msg("Successfully verified email")
+	return nil
+}
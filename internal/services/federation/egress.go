@@ -0,0 +1,96 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var errDisallowedAddress = errors.New("address is not publicly routable")
+
+// newEgressClient returns the client used for every request to an identity
+// provider. Issuers are registered per app, so their URLs (and whatever their
+// discovery document points at) are attacker-controlled: only https is
+// followed, and connections to loopback, private, link-local and other
+// non-public addresses are refused after DNS resolution, so a hostname that
+// resolves (or re-resolves) to an internal address is caught as well.
+func newEgressClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", errDisallowedAddress, host)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// No proxy: the dialer must see the real destination.
+			Proxy: nil,
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+
+			return checkEndpoint(req.URL.String())
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		isSharedAddressSpace(ip))
+}
+
+// isSharedAddressSpace reports whether ip is in 100.64.0.0/10 (RFC 6598),
+// which net.IP.IsPrivate does not cover.
+func isSharedAddressSpace(ip net.IP) bool {
+	ip4 := ip.To4()
+	return ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64
+}
+
+// checkEndpoint rejects provider URLs that are not absolute https URLs, or
+// whose host is a literal non-public IP address.
+func checkEndpoint(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("%w: %s", ErrInsecureEndpoint, raw)
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", errDisallowedAddress, raw)
+	}
+
+	return nil
+}
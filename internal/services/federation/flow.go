@@ -0,0 +1,187 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+const FederationStateTTL = 10 * time.Minute
+
+// Start returns the provider's authorization URL and the state it carries.
+// Callers must bind the state to the user agent so Callback is only reached
+// from the browser that started the flow.
+func (s *FederationService) Start(ctx context.Context, appID uuid.UUID, provider string, options FederationOptions) (string, string, error) {
+	startLog := log("Start")
+
+	idp, err := s.repo.GetIdentityProvider(ctx, appID, provider)
+	if err != nil {
+		return "", "", utils.ErrInternalServerError
+	}
+
+	if idp == nil {
+		return "", "", ErrIdentityProviderNotFound
+	}
+
+	metadata, err := s.discover(ctx, idp.Issuer)
+	if err != nil {
+		startLog.Error().Err(err).Str("issuer", idp.Issuer).Msg("Failed to discover identity provider")
+		return "", "", err
+	}
+
+	state, errState := randomString()
+	nonce, errNonce := randomString()
+	codeVerifier, errVerifier := randomString()
+
+	if errState != nil || errNonce != nil || errVerifier != nil {
+		startLog.Error().Msg("Failed to generate federation secrets")
+		return "", "", utils.ErrInternalServerError
+	}
+
+	federationState := &models.FederationState{
+		State:        state,
+		AppID:        appID,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ResponseType: options.ResponseType,
+		SetCookie:    options.SetCookie,
+		ExpiresAt:    time.Now().Add(FederationStateTTL),
+	}
+
+	if options.CallbackURL != "" {
+		federationState.CallbackURL = &options.CallbackURL
+	}
+
+	if options.RedirectURL != "" {
+		federationState.RedirectURL = &options.RedirectURL
+	}
+
+	if options.CookieDomain != "" {
+		federationState.CookieDomain = &options.CookieDomain
+	}
+
+	if err := s.repo.StoreFederationState(ctx, federationState); err != nil {
+		return "", "", utils.ErrInternalServerError
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", idp.ClientID)
+	query.Set("redirect_uri", s.redirectURI(provider))
+	query.Set("scope", strings.Join(idp.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// Callback completes the redirect started by Start. The returned options are
+// the ones given to Start, and are empty when the state is unknown.
+func (s *FederationService) Callback(ctx context.Context, provider string, params url.Values, authOptions auth.AuthOptions) (*models.LoginResponse, FederationOptions, error) {
+	callbackLog := log("Callback")
+
+	state, err := s.repo.ConsumeFederationState(ctx, params.Get("state"))
+	if err != nil {
+		return nil, FederationOptions{}, utils.ErrInternalServerError
+	}
+
+	if state == nil {
+		return nil, FederationOptions{}, ErrInvalidState
+	}
+
+	if state.Provider != provider {
+		return nil, FederationOptions{}, ErrProviderMismatch
+	}
+
+	options := FederationOptions{
+		ResponseType: state.ResponseType,
+		SetCookie:    state.SetCookie,
+	}
+
+	if state.CallbackURL != nil {
+		options.CallbackURL = *state.CallbackURL
+		authOptions.CallbackURL = *state.CallbackURL
+	}
+
+	if state.RedirectURL != nil {
+		options.RedirectURL = *state.RedirectURL
+		authOptions.RedirectURL = *state.RedirectURL
+	}
+
+	if state.CookieDomain != nil {
+		options.CookieDomain = *state.CookieDomain
+	}
+
+	fail := func(err error) (*models.LoginResponse, FederationOptions, error) {
+		return auth.FailedLoginResponse(authOptions), options, err
+	}
+
+	if idpError := params.Get("error"); idpError != "" {
+		callbackLog.Warn().Str("error", idpError).Str("description", params.Get("error_description")).Msg("Identity provider returned an error")
+		return fail(fmt.Errorf("%w: %s", ErrProviderError, idpError))
+	}
+
+	code := params.Get("code")
+	if code == "" {
+		return fail(fmt.Errorf("%w: missing code", ErrProviderError))
+	}
+
+	idp, err := s.repo.GetIdentityProvider(ctx, state.AppID, provider)
+	if err != nil {
+		return fail(utils.ErrInternalServerError)
+	}
+
+	if idp == nil {
+		return fail(ErrIdentityProviderNotFound)
+	}
+
+	clientSecret, err := utils.Decrypt([]byte(s.config.SecretKey), idp.ClientSecret)
+	if err != nil {
+		callbackLog.Error().Err(err).Str("provider", provider).Msg("Failed to decrypt client secret")
+		return fail(utils.ErrInternalServerError)
+	}
+
+	metadata, err := s.discover(ctx, idp.Issuer)
+	if err != nil {
+		callbackLog.Error().Err(err).Str("issuer", idp.Issuer).Msg("Failed to discover identity provider")
+		return fail(err)
+	}
+
+	idToken, err := s.exchangeCode(ctx, metadata, idp, string(clientSecret), code, state.CodeVerifier)
+	if err != nil {
+		callbackLog.Error().Err(err).Str("provider", provider).Msg("Failed to exchange authorization code")
+		return fail(err)
+	}
+
+	claims, err := s.verifyIDToken(ctx, metadata, idp, idToken, state.Nonce)
+	if err != nil {
+		callbackLog.Warn().Err(err).Str("provider", provider).Msg("Failed to verify id token")
+		return fail(err)
+	}
+
+	identity := mapIdentity(provider, idp.ClaimMappings, claims)
+
+	// An untrusted provider could claim any address as verified and take
+	// over the account behind it, so its claim is not relied on.
+	if !idp.TrustEmail {
+		identity.EmailVerified = false
+	}
+
+	res, err := s.authService.LoginWithExternalIdentity(ctx, state.AppID, identity, authOptions)
+	return res, options, err
+}
@@ -0,0 +1,27 @@
+package federation
+
+import (
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("service", "Federation").Str("method", method).Logger()
+	return &l
+}
+
+func NewFederationService(repo FederationRepository, appService *app.AppService, authService *auth.AuthService, cfg *config.AppConfig) *FederationService {
+	return &FederationService{
+		repo:        repo,
+		appService:  appService,
+		authService: authService,
+		config:      cfg,
+		httpClient:  newEgressClient(),
+		discovery:   map[string]*discoveryEntry{},
+		jwksCaches:  map[string]*jwks.Cache{},
+	}
+}
@@ -0,0 +1,226 @@
+package federation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const discoveryTTL = time.Hour
+
+var defaultSigningAlgs = []string{"RS256"}
+
+type providerMetadata struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+type discoveryEntry struct {
+	metadata  *providerMetadata
+	expiresAt time.Time
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (s *FederationService) discover(ctx context.Context, issuer string) (*providerMetadata, error) {
+	s.mu.Lock()
+	entry, ok := s.discovery[issuer]
+	s.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.metadata, nil
+	}
+
+	if err := checkEndpoint(issuer); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscoveryFailed, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscoveryFailed, err)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscoveryFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrDiscoveryFailed, res.StatusCode)
+	}
+
+	var metadata providerMetadata
+	if err := json.NewDecoder(res.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscoveryFailed, err)
+	}
+
+	// OpenID Connect Discovery 1.0 §4.3: the issuer must match exactly.
+	if strings.TrimRight(metadata.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("%w: issuer mismatch %q", ErrDiscoveryFailed, metadata.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata", ErrDiscoveryFailed)
+	}
+
+	for _, endpoint := range []string{metadata.AuthorizationEndpoint, metadata.TokenEndpoint, metadata.JWKSURI} {
+		if err := checkEndpoint(endpoint); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDiscoveryFailed, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.discovery[issuer] = &discoveryEntry{metadata: &metadata, expiresAt: time.Now().Add(discoveryTTL)}
+	s.mu.Unlock()
+
+	return &metadata, nil
+}
+
+func (s *FederationService) jwksCache(uri string) *jwks.Cache {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cache, ok := s.jwksCaches[uri]
+	if !ok {
+		cache = jwks.NewCacheWithClient(uri, time.Hour, s.httpClient)
+		s.jwksCaches[uri] = cache
+	}
+
+	return cache
+}
+
+func (s *FederationService) exchangeCode(ctx context.Context, metadata *providerMetadata, idp *models.IdentityProvider, clientSecret string, code string, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", s.redirectURI(idp.Provider))
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCodeExchangeFailed, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749 §2.3.1: credentials are form-encoded before basic auth.
+	req.SetBasicAuth(url.QueryEscape(idp.ClientID), url.QueryEscape(clientSecret))
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCodeExchangeFailed, err)
+	}
+	defer res.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCodeExchangeFailed, err)
+	}
+
+	if res.StatusCode != http.StatusOK || token.Error != "" {
+		return "", fmt.Errorf("%w: status %d %s %s", ErrCodeExchangeFailed, res.StatusCode, token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return "", fmt.Errorf("%w: missing id_token", ErrCodeExchangeFailed)
+	}
+
+	return token.IDToken, nil
+}
+
+func (s *FederationService) verifyIDToken(ctx context.Context, metadata *providerMetadata, idp *models.IdentityProvider, idToken string, nonce string) (jwt.MapClaims, error) {
+	algs := metadata.IDTokenSigningAlgValuesSupported
+	if len(algs) == 0 {
+		algs = defaultSigningAlgs
+	}
+
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(idToken, claims, s.jwksCache(metadata.JWKSURI).Keyfunc(ctx),
+		jwt.WithValidMethods(algs),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(idp.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	// OpenID Connect Core §3.1.3.7: with several audiences, azp must be us.
+	if audiences, _ := claims.GetAudience(); len(audiences) > 1 {
+		if azp, _ := claims["azp"].(string); azp != idp.ClientID {
+			return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+		}
+	}
+
+	return claims, nil
+}
+
+func mapIdentity(provider string, mappings models.ClaimMappings, claims jwt.MapClaims) *models.ExternalIdentity {
+	claimName := func(mapped, fallback string) string {
+		if mapped != "" {
+			return mapped
+		}
+		return fallback
+	}
+
+	identity := &models.ExternalIdentity{
+		Provider: models.AuthProvider(provider),
+	}
+
+	identity.Subject, _ = claims[claimName(mappings.Subject, "sub")].(string)
+	identity.Email, _ = claims[claimName(mappings.Email, "email")].(string)
+	identity.Name, _ = claims[claimName(mappings.Name, "name")].(string)
+
+	switch verified := claims[claimName(mappings.EmailVerified, "email_verified")].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+
+	return identity
+}
+
+func (s *FederationService) redirectURI(provider string) string {
+	return strings.TrimRight(s.config.PublicURL, "/") + "/api/v1/federation/" + url.PathEscape(provider) + "/callback"
+}
+
+func randomString() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
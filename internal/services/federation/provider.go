@@ -0,0 +1,103 @@
+package federation
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+// Provider names that already have a built-in meaning in core.user_auth_providers.
+var reservedProviderNames = []models.AuthProvider{
+	models.AuthProviderLocal,
+	models.AuthProviderPasswordless,
+	models.AuthProviderGoogle,
+}
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+func (s *FederationService) CreateIdentityProvider(ctx context.Context, appID uuid.UUID, req *models.CreateIdentityProviderRequest) (*models.IdentityProvider, error) {
+	createIdentityProviderLog := log("CreateIdentityProvider")
+
+	if !providerNamePattern.MatchString(req.Provider) || slices.Contains(reservedProviderNames, models.AuthProvider(req.Provider)) {
+		return nil, ErrInvalidProviderName
+	}
+
+	if err := checkEndpoint(req.Issuer); err != nil {
+		return nil, ErrInsecureEndpoint
+	}
+
+	app, err := s.appService.GetApp(ctx, appID.String())
+	if err != nil {
+		createIdentityProviderLog.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to get app")
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	clientSecret, err := utils.Encrypt([]byte(s.config.SecretKey), []byte(req.ClientSecret))
+	if err != nil {
+		createIdentityProviderLog.Error().Err(err).Msg("Failed to encrypt client secret")
+		return nil, utils.ErrInternalServerError
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	} else if !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	provider := &models.IdentityProvider{
+		AppID:        appID,
+		Provider:     req.Provider,
+		Issuer:       strings.TrimRight(req.Issuer, "/"),
+		ClientID:     req.ClientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		TrustEmail:   req.TrustEmail,
+	}
+
+	if req.ClaimMappings != nil {
+		provider.ClaimMappings = *req.ClaimMappings
+	}
+
+	if err := s.repo.StoreIdentityProvider(ctx, provider); err != nil {
+		return nil, err
+	}
+
+	createIdentityProviderLog.Info().Str("app_id", appID.String()).Str("provider", provider.Provider).Bool("trust_email", provider.TrustEmail).Msg("Identity provider registered")
+	return provider, nil
+}
+
+func (s *FederationService) GetIdentityProviders(ctx context.Context, appID uuid.UUID) ([]*models.IdentityProvider, error) {
+	providers, err := s.repo.GetIdentityProvidersByAppID(ctx, appID)
+	if err != nil {
+		log("GetIdentityProviders").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to get identity providers")
+		return nil, utils.ErrInternalServerError
+	}
+
+	return providers, nil
+}
+
+func (s *FederationService) DeleteIdentityProvider(ctx context.Context, appID uuid.UUID, provider string) error {
+	deleted, err := s.repo.DeleteIdentityProvider(ctx, appID, provider)
+	if err != nil {
+		log("DeleteIdentityProvider").Error().Err(err).Str("app_id", appID.String()).Str("provider", provider).Msg("Failed to delete identity provider")
+		return utils.ErrInternalServerError
+	}
+
+	if !deleted {
+		return ErrIdentityProviderNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/jwks"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/google/uuid"
+)
+
+type FederationRepository interface {
+	StoreIdentityProvider(ctx context.Context, provider *models.IdentityProvider) error
+	GetIdentityProvider(ctx context.Context, appID uuid.UUID, provider string) (*models.IdentityProvider, error)
+	GetIdentityProvidersByAppID(ctx context.Context, appID uuid.UUID) ([]*models.IdentityProvider, error)
+	DeleteIdentityProvider(ctx context.Context, appID uuid.UUID, provider string) (bool, error)
+	StoreFederationState(ctx context.Context, state *models.FederationState) error
+	ConsumeFederationState(ctx context.Context, state string) (*models.FederationState, error)
+}
+
+type FederationService struct {
+	repo        FederationRepository
+	appService  *app.AppService
+	authService *auth.AuthService
+	config      *config.AppConfig
+	httpClient  *http.Client
+
+	mu         sync.Mutex
+	discovery  map[string]*discoveryEntry
+	jwksCaches map[string]*jwks.Cache
+}
+
+type FederationOptions struct {
+	ResponseType models.AuthResponseType
+	CallbackURL  string
+	RedirectURL  string
+	SetCookie    bool
+	CookieDomain string
+}
+
+var (
+	ErrIdentityProviderNotFound = errors.New("identity provider not found")
+	ErrIdentityProviderExists   = errors.New("identity provider already exists")
+	ErrInvalidProviderName      = errors.New("invalid identity provider name")
+	ErrInvalidState             = errors.New("federation state is invalid or expired")
+	ErrProviderMismatch         = errors.New("federation state belongs to a different provider")
+	ErrDiscoveryFailed          = errors.New("failed to discover identity provider configuration")
+	ErrCodeExchangeFailed       = errors.New("failed to exchange authorization code")
+	ErrInvalidIDToken           = errors.New("invalid id token")
+	ErrProviderError            = errors.New("identity provider returned an error")
+	ErrInsecureEndpoint         = errors.New("identity provider endpoints must be public https urls")
+)
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"time"
+
 	"github.com/fransiscushermanto/backend/internal/config"
+	"github.com/fransiscushermanto/backend/internal/keyring"
+	"github.com/fransiscushermanto/backend/internal/mailer"
 	"github.com/fransiscushermanto/backend/internal/services/app"
 	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/services/federation"
+	"github.com/fransiscushermanto/backend/internal/services/oauth"
 	"github.com/fransiscushermanto/backend/internal/services/user"
 )
 
@@ -16,14 +22,28 @@ type UserRepository = user.UserRepository
 type AuthService = auth.AuthService
 type AuthRepository = auth.AuthRepository
 
-func NewAppService(repo app.AppRepository, prefixApiKey string, secretKey string) *app.AppService {
-	return app.NewAppService(repo, prefixApiKey, secretKey)
+type FederationService = federation.FederationService
+type FederationRepository = federation.FederationRepository
+
+type OAuthService = oauth.OAuthService
+type OAuthRepository = oauth.OAuthRepository
+
+func NewAppService(repo app.AppRepository, prefixApiKey string, secretKey string, apiKeyPepper string, purgeDelay time.Duration) *app.AppService {
+	return app.NewAppService(repo, prefixApiKey, secretKey, apiKeyPepper, purgeDelay)
 }
 
 func NewUserService(repo user.UserRepository, appService *app.AppService) *user.UserService {
 	return user.NewUserService(repo, appService)
 }
 
-func NewAuthService(repo auth.AuthRepository, userRepository user.UserRepository, userService *user.UserService, keys *config.CryptoKeys) *auth.AuthService {
-	return auth.NewAuthService(repo, userRepository, userService, keys)
+func NewAuthService(repo auth.AuthRepository, userRepository user.UserRepository, userService *user.UserService, appService *app.AppService, mailer mailer.Mailer, cfg *config.AppConfig, keys *keyring.KeyRing) *auth.AuthService {
+	return auth.NewAuthService(repo, userRepository, userService, appService, mailer, cfg, keys)
+}
+
+func NewFederationService(repo federation.FederationRepository, appService *app.AppService, authService *auth.AuthService, cfg *config.AppConfig) *federation.FederationService {
+	return federation.NewFederationService(repo, appService, authService, cfg)
+}
+
+func NewOAuthService(repo oauth.OAuthRepository, appService *app.AppService, authService *auth.AuthService) *oauth.OAuthService {
+	return oauth.NewOAuthService(repo, appService, authService)
 }
@@ -0,0 +1,134 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+)
+
+// A S256 challenge is the unpadded base64url SHA-256 of the verifier.
+var codeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+
+// ValidateAuthorizeRequest checks the client and its redirect uri before the
+// user is asked for credentials. Errors other than ErrInvalidClient and
+// ErrInvalidRedirectURI may be reported back to the redirect uri.
+func (s *OAuthService) ValidateAuthorizeRequest(ctx context.Context, req *models.AuthorizeRequest) error {
+	validateAuthorizeRequestLog := log("ValidateAuthorizeRequest")
+
+	app, err := s.appService.GetApp(ctx, req.ClientID.String())
+	if err != nil {
+		validateAuthorizeRequestLog.Error().Err(err).Str("client_id", req.ClientID.String()).Msg("Failed to get app")
+		return utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return ErrInvalidClient
+	}
+
+	// Wildcard entries only cover callback_url and redirect_url; codes are
+	// only ever sent to an exactly registered uri.
+	registered := false
+	if !utils.IsWildcardOrigin(req.RedirectURI) {
+		registered, err = s.repo.IsRedirectURIRegistered(ctx, req.ClientID, req.RedirectURI)
+		if err != nil {
+			return utils.ErrInternalServerError
+		}
+	}
+
+	if !registered {
+		validateAuthorizeRequestLog.Warn().Str("client_id", req.ClientID.String()).Str("redirect_uri", req.RedirectURI).Msg("Unregistered redirect uri")
+		return ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return ErrUnsupportedResponseType
+	}
+
+	if req.CodeChallengeMethod != models.CodeChallengeMethodS256 || !codeChallengePattern.MatchString(req.CodeChallenge) {
+		return ErrInvalidCodeChallenge
+	}
+
+	return nil
+}
+
+// Authorize issues a single-use authorization code for an already
+// authenticated user and returns the redirect uri to send the browser to.
+func (s *OAuthService) Authorize(ctx context.Context, req *models.AuthorizeRequest, user *models.User) (*models.AuthorizeResponse, error) {
+	authorizeLog := log("Authorize")
+
+	if err := s.ValidateAuthorizeRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if user.AppID != req.ClientID {
+		return nil, ErrInvalidClient
+	}
+
+	code, err := randomString()
+	if err != nil {
+		authorizeLog.Error().Err(err).Msg("Failed to generate authorization code")
+		return nil, utils.ErrInternalServerError
+	}
+
+	err = s.repo.StoreAuthorizationCode(ctx, &models.AuthorizationCode{
+		CodeHash:            hashCode(code),
+		AppID:               req.ClientID,
+		UserID:              user.ID,
+		RedirectURI:         req.RedirectURI,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		ExpiresAt:           time.Now().Add(constants.DEFAULT_AUTHORIZATION_CODE_EXPIRY),
+	})
+
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	query := url.Values{}
+	query.Set("code", code)
+
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	return &models.AuthorizeResponse{RedirectURL: appendQuery(req.RedirectURI, query)}, nil
+}
+
+// appendQuery adds params to rawURL while keeping the query it already has.
+func appendQuery(rawURL string, params url.Values) string {
+	uri, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := uri.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+
+	uri.RawQuery = query.Encode()
+	return uri.String()
+}
+
+func randomString() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,21 @@
+package oauth
+
+import (
+	"github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/rs/zerolog"
+)
+
+func log(method string) *zerolog.Logger {
+	l := utils.Log().With().Str("service", "OAuth").Str("method", method).Logger()
+	return &l
+}
+
+func NewOAuthService(repo OAuthRepository, appService *app.AppService, authService *auth.AuthService) *OAuthService {
+	return &OAuthService{
+		repo:        repo,
+		appService:  appService,
+		authService: authService,
+	}
+}
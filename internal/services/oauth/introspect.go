@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	appTypes "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Introspect implements RFC 7662 for resource servers authenticated with
+// their app's API key. Tokens that fail verification, have been revoked or
+// belong to another app are all reported as inactive, without saying why.
+func (s *OAuthService) Introspect(ctx context.Context, req *models.IntrospectRequest) (*models.IntrospectResponse, error) {
+	introspectLog := log("Introspect")
+
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil || req.ClientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+
+	app, _, err := s.appService.AuthenticateClient(ctx, clientID, req.ClientSecret)
+	if err != nil {
+		if errors.Is(err, appTypes.ErrInvalidClientCredentials) {
+			return nil, ErrInvalidClient
+		}
+
+		return nil, err
+	}
+
+	if req.Token == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	inactive := &models.IntrospectResponse{Active: false}
+
+	claims, err := s.authService.IntrospectToken(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, utils.ErrInternalServerError) {
+			return nil, err
+		}
+
+		introspectLog.Debug().Err(err).Str("client_id", app.ID.String()).Msg("Token is not active")
+		return inactive, nil
+	}
+
+	appID, _ := claims["app_id"].(string)
+	if appID != app.ID.String() {
+		introspectLog.Warn().Str("client_id", app.ID.String()).Str("token_app_id", appID).Msg("Introspection of a token issued to another app")
+		return inactive, nil
+	}
+
+	res := &models.IntrospectResponse{
+		Active: true,
+		AppID:  appID,
+	}
+
+	res.Sub, _ = claims["user_id"].(string)
+	res.Jti, _ = claims["jti"].(string)
+	res.Scope, _ = claims["scope"].(string)
+	res.TokenType, _ = claims["type"].(string)
+
+	// A client token represents the app itself.
+	if res.Sub == "" {
+		res.Sub = appID
+	}
+
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		res.Exp = exp.Unix()
+	}
+
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		res.Iat = iat.Unix()
+	}
+
+	return res, nil
+}
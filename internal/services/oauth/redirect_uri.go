@@ -0,0 +1,170 @@
+package oauth
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (s *OAuthService) CreateRedirectURI(ctx context.Context, appID uuid.UUID, req *models.CreateRedirectURIRequest) (*models.AppRedirectURI, error) {
+	createRedirectURILog := log("CreateRedirectURI")
+
+	if !isAllowedRedirectURI(req.RedirectURI) && !utils.IsValidWildcardOrigin(req.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	app, err := s.appService.GetApp(ctx, appID.String())
+	if err != nil {
+		createRedirectURILog.Error().Err(err).Str("app_id", appID.String()).Msg("Failed to get app")
+		return nil, utils.ErrInternalServerError
+	}
+
+	if app == nil {
+		return nil, utils.ErrNotFound
+	}
+
+	redirectURI := &models.AppRedirectURI{
+		ID:          uuid.New(),
+		AppID:       appID,
+		RedirectURI: req.RedirectURI,
+	}
+
+	if err := s.repo.StoreRedirectURI(ctx, redirectURI); err != nil {
+		return nil, err
+	}
+
+	createRedirectURILog.Info().Str("app_id", appID.String()).Str("redirect_uri", redirectURI.RedirectURI).Msg("Redirect uri registered")
+	return redirectURI, nil
+}
+
+func (s *OAuthService) GetRedirectURIs(ctx context.Context, appID uuid.UUID) ([]*models.AppRedirectURI, error) {
+	redirectURIs, err := s.repo.GetRedirectURIsByAppID(ctx, appID)
+	if err != nil {
+		log("GetRedirectURIs").Error().Err(err).Str("app_id", appID.String()).Msg("Failed to get redirect uris")
+		return nil, utils.ErrInternalServerError
+	}
+
+	return redirectURIs, nil
+}
+
+func (s *OAuthService) DeleteRedirectURI(ctx context.Context, appID, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteRedirectURI(ctx, appID, id)
+	if err != nil {
+		log("DeleteRedirectURI").Error().Err(err).Str("app_id", appID.String()).Str("id", id.String()).Msg("Failed to delete redirect uri")
+		return utils.ErrInternalServerError
+	}
+
+	if !deleted {
+		return ErrRedirectURINotFound
+	}
+
+	return nil
+}
+
+// CheckRedirectURLs makes sure the callback and redirect urls of an auth
+// request are registered for the app, and that a requested cookie domain
+// belongs to one of them, so tokens and sessions are never handed to a site
+// the app did not register.
+func (s *OAuthService) CheckRedirectURLs(ctx context.Context, appID uuid.UUID, callbackURL, redirectURL, cookieDomain string) error {
+	checkRedirectURLsLog := log("CheckRedirectURLs")
+
+	if callbackURL == "" && redirectURL == "" && cookieDomain == "" {
+		return nil
+	}
+
+	redirectURIs, err := s.repo.GetRedirectURIsByAppID(ctx, appID)
+	if err != nil {
+		return utils.ErrInternalServerError
+	}
+
+	for _, rawURL := range []string{callbackURL, redirectURL} {
+		if rawURL != "" && !matchRedirectURL(redirectURIs, rawURL) {
+			checkRedirectURLsLog.Warn().Str("app_id", appID.String()).Str("url", rawURL).Msg("Rejected unregistered redirect url")
+			return ErrRedirectURLNotAllowed
+		}
+	}
+
+	if cookieDomain != "" && !matchCookieDomain(redirectURIs, cookieDomain) {
+		checkRedirectURLsLog.Warn().Str("app_id", appID.String()).Str("cookie_domain", cookieDomain).Msg("Rejected cookie domain of no registered redirect url")
+		return ErrCookieDomainNotAllowed
+	}
+
+	return nil
+}
+
+// matchRedirectURL accepts rawURL when it is registered exactly, or when its
+// origin is covered by a wildcard entry (https://*.example.com).
+func matchRedirectURL(redirectURIs []*models.AppRedirectURI, rawURL string) bool {
+	uri, err := url.Parse(rawURL)
+	if err != nil || !uri.IsAbs() || uri.User != nil {
+		return false
+	}
+
+	origin := uri.Scheme + "://" + uri.Host
+
+	for _, redirectURI := range redirectURIs {
+		if redirectURI.RedirectURI == rawURL {
+			return true
+		}
+
+		if utils.IsWildcardOrigin(redirectURI.RedirectURI) && utils.MatchOrigin(redirectURI.RedirectURI, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCookieDomain accepts the host of a registered uri, or any domain
+// under a wildcard entry.
+func matchCookieDomain(redirectURIs []*models.AppRedirectURI, domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(domain, "*"), "."))
+	if domain == "" {
+		return false
+	}
+
+	for _, redirectURI := range redirectURIs {
+		if utils.IsWildcardOrigin(redirectURI.RedirectURI) {
+			_, host, _ := strings.Cut(redirectURI.RedirectURI, "://*.")
+			if domain == host || strings.HasSuffix(domain, "."+host) {
+				return true
+			}
+			continue
+		}
+
+		if uri, err := url.Parse(redirectURI.RedirectURI); err == nil && strings.EqualFold(uri.Hostname(), domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAllowedRedirectURI follows RFC 8252: https anywhere, plain http only on
+// loopback, and private-use schemes in reverse domain form for native apps.
+func isAllowedRedirectURI(rawURI string) bool {
+	uri, err := url.Parse(rawURI)
+	if err != nil || !uri.IsAbs() || uri.Fragment != "" || uri.User != nil {
+		return false
+	}
+
+	switch uri.Scheme {
+	case "https":
+		return uri.Host != ""
+	case "http":
+		host := uri.Hostname()
+		if host == "localhost" {
+			return true
+		}
+
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	default:
+		return strings.Contains(uri.Scheme, ".")
+	}
+}
@@ -0,0 +1,64 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	appTypes "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// Revoke implements RFC 7009. Public clients identify themselves with their
+// client_id only; a client_secret, when sent, must be a valid API key. Since
+// every token we issue says what it is, token_type_hint is not needed to find
+// it, and invalid or already revoked tokens succeed silently (RFC 7009 §2.2).
+func (s *OAuthService) Revoke(ctx context.Context, req *models.RevokeRequest) error {
+	revokeLog := log("Revoke")
+
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return ErrInvalidClient
+	}
+
+	if req.ClientSecret != "" {
+		if _, _, err := s.appService.AuthenticateClient(ctx, clientID, req.ClientSecret); err != nil {
+			if errors.Is(err, appTypes.ErrInvalidClientCredentials) {
+				return ErrInvalidClient
+			}
+
+			return err
+		}
+	} else {
+		app, err := s.appService.GetApp(ctx, clientID.String())
+		if err != nil {
+			revokeLog.Error().Err(err).Str("client_id", clientID.String()).Msg("Failed to get app")
+			return utils.ErrInternalServerError
+		}
+
+		if app == nil {
+			return ErrInvalidClient
+		}
+	}
+
+	if req.Token == "" {
+		return ErrInvalidRequest
+	}
+
+	err = s.authService.RevokeToken(ctx, clientID, req.Token)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, auth.ErrTokenAppMismatch):
+		revokeLog.Warn().Str("client_id", clientID.String()).Msg("Revocation of a token issued to another app")
+		return ErrUnauthorizedClient
+	case errors.Is(err, utils.ErrInternalServerError):
+		return err
+	default:
+		revokeLog.Debug().Err(err).Str("client_id", clientID.String()).Str("token_type_hint", req.TokenTypeHint).Msg("Ignoring revocation of an invalid token")
+		return nil
+	}
+}
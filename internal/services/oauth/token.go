@@ -0,0 +1,201 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/constants"
+	"github.com/fransiscushermanto/backend/internal/models"
+	appTypes "github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// RFC 7636 §4.1: 43-128 characters from the unreserved set.
+var codeVerifierPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+
+const tokenTypeBearer = "Bearer"
+
+func (s *OAuthService) Token(ctx context.Context, req *models.TokenRequest, options auth.AuthOptions) (*models.TokenResponse, error) {
+	switch req.GrantType {
+	case models.GrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, req, options)
+	case models.GrantTypeRefreshToken:
+		return s.exchangeRefreshToken(ctx, req, options)
+	case models.GrantTypeClientCredentials:
+		return s.exchangeClientCredentials(ctx, req)
+	case "":
+		return nil, ErrInvalidRequest
+	default:
+		return nil, ErrUnsupportedGrantType
+	}
+}
+
+func (s *OAuthService) exchangeAuthorizationCode(ctx context.Context, req *models.TokenRequest, options auth.AuthOptions) (*models.TokenResponse, error) {
+	exchangeLog := log("exchangeAuthorizationCode")
+
+	if req.Code == "" || req.RedirectURI == "" || req.ClientID == "" || req.CodeVerifier == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	codeHash := hashCode(req.Code)
+
+	code, err := s.repo.GetAuthorizationCode(ctx, codeHash)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if code == nil || code.AppID != clientID {
+		return nil, ErrInvalidGrant
+	}
+
+	if code.UsedAt != nil {
+		// A replayed code means it leaked; drop every session it may have
+		// produced (RFC 6749 §4.1.2).
+		exchangeLog.Warn().Str("app_id", code.AppID.String()).Str("user_id", code.UserID.String()).Msg("Authorization code reused, revoking sessions")
+
+		if err := s.authService.RevokeUserSessions(ctx, code.AppID, code.UserID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrInvalidGrant
+	}
+
+	if time.Now().After(code.ExpiresAt) {
+		return nil, ErrInvalidGrant
+	}
+
+	// Mark the code used before anything else so a failed attempt burns it too.
+	used, err := s.repo.UseAuthorizationCode(ctx, codeHash)
+	if err != nil {
+		return nil, utils.ErrInternalServerError
+	}
+
+	if !used {
+		return nil, ErrInvalidGrant
+	}
+
+	if code.RedirectURI != req.RedirectURI || !verifyCodeChallenge(code.CodeChallenge, req.CodeVerifier) {
+		return nil, ErrInvalidGrant
+	}
+
+	// Without a device_id the login gets a device of its own rather than
+	// replacing the user's other sessions on this app.
+	options.DeviceID = req.DeviceID
+
+	res, err := s.authService.LoginByUserID(ctx, code.AppID, code.UserID, options)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil, ErrInvalidGrant
+		}
+
+		exchangeLog.Error().Err(err).Str("user_id", code.UserID.String()).Msg("Failed to issue tokens")
+		return nil, utils.ErrInternalServerError
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  res.AccessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    res.ExpiresIn,
+		RefreshToken: res.RefreshToken,
+		DeviceID:     res.DeviceID,
+	}, nil
+}
+
+func (s *OAuthService) exchangeRefreshToken(ctx context.Context, req *models.TokenRequest, options auth.AuthOptions) (*models.TokenResponse, error) {
+	if req.RefreshToken == "" || req.ClientID == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	// RFC 6749 §6: the token has to be issued to the client refreshing it.
+	appID, err := s.authService.RefreshTokenAppID(req.RefreshToken)
+	if err != nil || appID != clientID {
+		return nil, ErrInvalidGrant
+	}
+
+	options.DeviceID = req.DeviceID
+
+	tokens, err := s.authService.RefreshToken(ctx, req.RefreshToken, options)
+	if err != nil {
+		log("exchangeRefreshToken").Warn().Err(err).Msg("Failed to refresh token")
+
+		if errors.Is(err, auth.ErrRefreshTokenReused) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGrant, err)
+		}
+
+		return nil, ErrInvalidGrant
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    tokens.ExpiresIn,
+		RefreshToken: tokens.RefreshToken,
+		DeviceID:     options.DeviceID,
+	}, nil
+}
+
+func (s *OAuthService) exchangeClientCredentials(ctx context.Context, req *models.TokenRequest) (*models.TokenResponse, error) {
+	exchangeLog := log("exchangeClientCredentials")
+
+	clientID, err := uuid.Parse(req.ClientID)
+	if err != nil || req.ClientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+
+	app, key, err := s.appService.AuthenticateClient(ctx, clientID, req.ClientSecret)
+	if err != nil {
+		if errors.Is(err, appTypes.ErrInvalidClientCredentials) {
+			return nil, ErrInvalidClient
+		}
+
+		return nil, err
+	}
+
+	scopes, err := s.appService.ResolveScopes(app, strings.Fields(req.Scope))
+	if err != nil {
+		return nil, ErrInvalidScope
+	}
+
+	accessToken, err := s.authService.GenerateClientToken(ctx, app, key.KeyID, scopes)
+	if err != nil {
+		exchangeLog.Error().Err(err).Str("client_id", clientID.String()).Msg("Failed to issue client token")
+		return nil, utils.ErrInternalServerError
+	}
+
+	return &models.TokenResponse{
+		AccessToken: *accessToken,
+		TokenType:   tokenTypeBearer,
+		ExpiresIn:   int(constants.DEFAULT_CLIENT_TOKEN_EXPIRY.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if !codeVerifierPattern.MatchString(verifier) {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
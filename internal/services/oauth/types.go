@@ -0,0 +1,43 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fransiscushermanto/backend/internal/models"
+	"github.com/fransiscushermanto/backend/internal/services/app"
+	"github.com/fransiscushermanto/backend/internal/services/auth"
+	"github.com/google/uuid"
+)
+
+type OAuthRepository interface {
+	StoreRedirectURI(ctx context.Context, redirectURI *models.AppRedirectURI) error
+	GetRedirectURIsByAppID(ctx context.Context, appID uuid.UUID) ([]*models.AppRedirectURI, error)
+	IsRedirectURIRegistered(ctx context.Context, appID uuid.UUID, redirectURI string) (bool, error)
+	DeleteRedirectURI(ctx context.Context, appID, id uuid.UUID) (bool, error)
+	StoreAuthorizationCode(ctx context.Context, code *models.AuthorizationCode) error
+	GetAuthorizationCode(ctx context.Context, codeHash string) (*models.AuthorizationCode, error)
+	UseAuthorizationCode(ctx context.Context, codeHash string) (bool, error)
+}
+
+type OAuthService struct {
+	repo        OAuthRepository
+	appService  *app.AppService
+	authService *auth.AuthService
+}
+
+var (
+	ErrInvalidRequest          = errors.New("oauth request is missing or has an invalid parameter")
+	ErrInvalidClient           = errors.New("unknown oauth client")
+	ErrInvalidGrant            = errors.New("authorization grant is invalid, expired or already used")
+	ErrUnauthorizedClient      = errors.New("token was not issued to this client")
+	ErrInvalidRedirectURI      = errors.New("redirect uri is not registered for this client")
+	ErrInvalidCodeChallenge    = errors.New("a S256 code challenge is required")
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+	ErrUnsupportedGrantType    = errors.New("unsupported grant type")
+	ErrInvalidScope            = errors.New("requested scope exceeds the scopes granted to the client")
+	ErrRedirectURIExists       = errors.New("redirect uri already registered")
+	ErrRedirectURINotFound     = errors.New("redirect uri not found")
+	ErrRedirectURLNotAllowed   = errors.New("redirect url is not allowed for this app")
+	ErrCookieDomainNotAllowed  = errors.New("cookie domain is not allowed for this app")
+)
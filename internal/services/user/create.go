@@ -10,6 +10,11 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// GetPasswordPolicy returns the rules passwords of the app's users follow.
+func (s *UserService) GetPasswordPolicy(ctx context.Context, appID uuid.UUID) (models.PasswordPolicy, error) {
+	return s.appService.GetPasswordPolicy(ctx, appID)
+}
+
 func (s *UserService) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
 	createUserLog := log("CreateUser")
 
@@ -39,11 +44,17 @@ func (s *UserService) CreateUser(ctx context.Context, req *models.CreateUserRequ
 		EmailVerifiedAt: nil,
 	}
 
+	if req.IsEmailVerified {
+		verifiedAt := time.Now()
+		user.IsEmailVerified = true
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
 	userAuthentication := &models.UserAuthProvider{
 		UserID:         user.ID,
 		AppID:          user.AppID,
 		Provider:       req.Provider,
-		ProviderUserID: nil,
+		ProviderUserID: req.ProviderUserID,
 	}
 
 	if req.Provider == models.AuthProviderLocal {
@@ -16,6 +16,9 @@ type UserRepository interface {
 	GetAllUsersByAppID(ctx context.Context, appID uuid.UUID) ([]*models.User, error)
 	GetAppUserByID(ctx context.Context, appID uuid.UUID, id uuid.UUID) (*models.User, error)
 	GetUserByEmail(ctx context.Context, appID uuid.UUID, email string) (*models.User, error)
+	MarkEmailVerified(ctx context.Context, appID, id uuid.UUID) (bool, error)
+	GetUserByAuthProvider(ctx context.Context, appID uuid.UUID, provider models.AuthProvider, providerUserID string) (*models.User, error)
+	CreateUserAuthProvider(ctx context.Context, auth *models.UserAuthProvider) error
 }
 
 type UserService struct {
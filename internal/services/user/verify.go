@@ -0,0 +1,31 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fransiscushermanto/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (s *UserService) MarkEmailVerified(ctx context.Context, appID, userID uuid.UUID) error {
+	markEmailVerifiedLog := log("MarkEmailVerified")
+
+	opCtx, cancel := utils.ContextWithTimeout(5 * time.Second)
+	defer cancel()
+
+	updated, err := s.repo.MarkEmailVerified(opCtx, appID, userID)
+
+	if err != nil {
+		markEmailVerifiedLog.Error().Err(err).Msg("Failed to execute repository method MarkEmailVerified")
+		return fmt.Errorf("failed to mark user email as verified: %w", err)
+	}
+
+	if !updated {
+		markEmailVerifiedLog.Error().Str("user_id", userID.String()).Msg("No user found")
+		return utils.ErrNotFound
+	}
+
+	return nil
+}
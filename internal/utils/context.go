@@ -5,16 +5,21 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/fransiscushermanto/backend/internal/models"
 	"github.com/google/uuid"
 )
 
 type ContextKey string
 
 const (
-	UserIDContextKey    ContextKey = "user_id"
-	AppIDContextKey     ContextKey = "app_id"
-	TokenTypeContextKey ContextKey = "token_type"
-	JTIContextKey       ContextKey = "jti"
+	UserIDContextKey         ContextKey = "user_id"
+	AppIDContextKey          ContextKey = "app_id"
+	TokenTypeContextKey      ContextKey = "token_type"
+	JTIContextKey            ContextKey = "jti"
+	RefreshJTIContextKey     ContextKey = "refresh_jti"
+	AccessTokenContextKey    ContextKey = "access_token"
+	ScopesContextKey         ContextKey = "scopes"
+	PasswordPolicyContextKey ContextKey = "password_policy"
 )
 
 func ContextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
@@ -69,6 +74,44 @@ func GetJtiFromContext(ctx context.Context) (string, error) {
 	return jti, nil
 }
 
+func GetRefreshJtiFromContext(ctx context.Context) (string, error) {
+	refreshJTI, ok := ctx.Value(RefreshJTIContextKey).(string)
+	if !ok {
+		return "", fmt.Errorf("missing %s in context", string(RefreshJTIContextKey))
+	}
+	return refreshJTI, nil
+}
+
+func GetAccessTokenFromContext(ctx context.Context) (string, error) {
+	accessToken, ok := ctx.Value(AccessTokenContextKey).(string)
+	if !ok {
+		return "", fmt.Errorf("missing %s in context", string(AccessTokenContextKey))
+	}
+	return accessToken, nil
+}
+
+func GetScopesFromContext(ctx context.Context) ([]string, error) {
+	scopes, ok := ctx.Value(ScopesContextKey).([]string)
+	if !ok {
+		return nil, fmt.Errorf("missing %s in context", string(ScopesContextKey))
+	}
+	return scopes, nil
+}
+
+func ContextWithPasswordPolicy(ctx context.Context, policy models.PasswordPolicy) context.Context {
+	return context.WithValue(ctx, PasswordPolicyContextKey, policy)
+}
+
+// GetPasswordPolicyFromContext falls back to the default policy, as the
+// password-pattern validation runs on requests not bound to an app too.
+func GetPasswordPolicyFromContext(ctx context.Context) models.PasswordPolicy {
+	policy, ok := ctx.Value(PasswordPolicyContextKey).(models.PasswordPolicy)
+	if !ok {
+		return models.DefaultPasswordPolicy
+	}
+	return policy
+}
+
 func DebugContextValue(ctx context.Context, key ContextKey) {
 	value := ctx.Value(key)
 	if value == nil {
@@ -0,0 +1,65 @@
+package utils
+
+import "strings"
+
+// MatchOrigin reports whether origin (scheme://host[:port]) matches pattern,
+// either exactly or through a leading wildcard label such as
+// https://*.example.com. Wildcard patterns ignore the port.
+func MatchOrigin(pattern, origin string) bool {
+	if pattern == origin {
+		return true
+	}
+	if strings.Contains(pattern, "*") {
+		// Handle wildcard matching
+		// Split both pattern and origin by "://" to separate protocol
+		patternParts := strings.SplitN(pattern, "://", 2)
+		originParts := strings.SplitN(origin, "://", 2)
+
+		if len(patternParts) != 2 || len(originParts) != 2 {
+			return false
+		}
+
+		// Protocol must match exactly
+		if patternParts[0] != originParts[0] {
+			return false
+		}
+
+		// Check host part (remove port from origin if present)
+		patternHost := patternParts[1]
+		originHost := originParts[1]
+
+		// Remove port from origin host
+		if portIndex := strings.LastIndex(originHost, ":"); portIndex != -1 {
+			originHost = originHost[:portIndex]
+		}
+
+		// Simple wildcard matching
+		if strings.HasPrefix(patternHost, "*.") {
+			suffix := patternHost[2:] // Remove "*."
+			if strings.HasSuffix(originHost, "."+suffix) || originHost == suffix {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsWildcardOrigin reports whether rawURL is a pattern with a wildcard first
+// label, such as https://*.example.com.
+func IsWildcardOrigin(rawURL string) bool {
+	return strings.Contains(rawURL, "://*.")
+}
+
+// IsValidWildcardOrigin accepts https://*.example.com style patterns, but not
+// a wildcard directly above a top level domain. Plain http is refused: it
+// would cover every subdomain served without TLS.
+func IsValidWildcardOrigin(pattern string) bool {
+	scheme, host, _ := strings.Cut(pattern, "://")
+	if scheme != "https" {
+		return false
+	}
+
+	suffix, ok := strings.CutPrefix(host, "*.")
+	return ok && strings.Contains(suffix, ".") && !strings.ContainsAny(suffix, "*/?#@:")
+}
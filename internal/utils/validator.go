@@ -24,6 +24,38 @@ var errorMessages = map[string]string{
 	"eqfield":  "%s must match the %s field.",
 }
 
+// PasswordPattern is the password-pattern validation. It checks against the
+// policy set with ContextWithPasswordPolicy, so it has to be registered with
+// RegisterValidationCtx and run through StructCtx.
+func PasswordPattern(ctx context.Context, fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	if field.Kind() != reflect.String {
+		return false
+	}
+
+	return GetPasswordPolicyFromContext(ctx).Violation(field.String()) == ""
+}
+
+// RenderPasswordErrorMessage explains a password-pattern failure with the
+// policy the password was validated against.
+func RenderPasswordErrorMessage(ctx context.Context) RenderErrorMessageFunc {
+	policy := GetPasswordPolicyFromContext(ctx)
+
+	return func(fieldError validator.FieldError) (string, bool) {
+		if fieldError.Tag() != "password-pattern" {
+			return "", false
+		}
+
+		password, ok := fieldError.Value().(string)
+		if !ok {
+			return fmt.Sprintf("%s must be a string.", fieldError.Field()), true
+		}
+
+		return fmt.Sprintf("%s %s", fieldError.Field(), policy.Violation(password)), true
+	}
+}
+
 type FieldError struct {
 	Field   string
 	Message string
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.email_verification_tokens;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE
+    IF NOT EXISTS core.email_verification_tokens (
+        jti VARCHAR(255) NOT NULL PRIMARY KEY,
+        user_id UUID NOT NULL,
+        app_id UUID NOT NULL,
+        token VARCHAR(255) NOT NULL,
+        is_active BOOLEAN NOT NULL DEFAULT TRUE,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        expires_at TIMESTAMPTZ NOT NULL,
+        CONSTRAINT fk_email_verification_token_user FOREIGN KEY (user_id, app_id) REFERENCES core.users (id, app_id) ON DELETE CASCADE,
+        CONSTRAINT fk_email_verification_token_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_token_expiry ON core.email_verification_tokens (expires_at);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_token_user_app ON core.email_verification_tokens (user_id, app_id);
\ No newline at end of file
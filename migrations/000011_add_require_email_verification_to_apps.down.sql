@@ -0,0 +1,2 @@
+ALTER TABLE core.apps
+DROP COLUMN require_email_verification;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- When enabled, users of the app must verify their email before logging in with email and password
+ALTER TABLE core.apps
+ADD COLUMN require_email_verification BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
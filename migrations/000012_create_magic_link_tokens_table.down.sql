@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.magic_link_tokens;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS core.federation_states;
+
+DROP TABLE IF EXISTS core.app_identity_providers;
\ No newline at end of file
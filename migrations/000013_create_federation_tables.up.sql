@@ -0,0 +1,36 @@
+-- OpenID Connect identity providers an app federates with.
+-- provider is the slug used in /federation/{provider} routes and stored as
+-- core.user_auth_providers.provider for linked users.
+CREATE TABLE
+    IF NOT EXISTS core.app_identity_providers (
+        app_id UUID NOT NULL,
+        provider VARCHAR(50) NOT NULL,
+        issuer VARCHAR(255) NOT NULL,
+        client_id VARCHAR(255) NOT NULL,
+        -- Encrypted with the application secret key
+        client_secret BYTEA NOT NULL,
+        scopes TEXT[] NOT NULL DEFAULT ARRAY['openid', 'email', 'profile'],
+        claim_mappings JSONB NOT NULL DEFAULT '{}'::JSONB,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT pk_app_identity_providers PRIMARY KEY (app_id, provider),
+        CONSTRAINT fk_app_identity_provider_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+-- Short-lived state of an in-flight authorization-code redirect
+CREATE TABLE
+    IF NOT EXISTS core.federation_states (
+        state VARCHAR(255) NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        provider VARCHAR(50) NOT NULL,
+        nonce VARCHAR(255) NOT NULL,
+        code_verifier VARCHAR(255) NOT NULL,
+        response_type VARCHAR(20) NOT NULL,
+        callback_url TEXT NULL,
+        redirect_url TEXT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        expires_at TIMESTAMPTZ NOT NULL,
+        CONSTRAINT fk_federation_state_identity_provider FOREIGN KEY (app_id, provider) REFERENCES core.app_identity_providers (app_id, provider) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_federation_state_expiry ON core.federation_states (expires_at);
\ No newline at end of file
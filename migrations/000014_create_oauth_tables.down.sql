@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS core.authorization_codes;
+
+DROP TABLE IF EXISTS core.app_redirect_uris;
\ No newline at end of file
@@ -0,0 +1,31 @@
+-- Redirect URIs an app is allowed to receive authorization codes on.
+-- Matched exactly against the redirect_uri of /oauth/authorize requests.
+CREATE TABLE
+    IF NOT EXISTS core.app_redirect_uris (
+        id UUID NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        redirect_uri TEXT NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT unique_redirect_uri_per_app UNIQUE (app_id, redirect_uri),
+        CONSTRAINT fk_app_redirect_uri_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+-- Single-use authorization codes issued by /oauth/authorize.
+-- Only the SHA-256 hash of the code is stored.
+CREATE TABLE
+    IF NOT EXISTS core.authorization_codes (
+        code_hash VARCHAR(64) NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        user_id UUID NOT NULL,
+        redirect_uri TEXT NOT NULL,
+        code_challenge VARCHAR(128) NOT NULL,
+        code_challenge_method VARCHAR(10) NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        expires_at TIMESTAMPTZ NOT NULL,
+        used_at TIMESTAMPTZ NULL DEFAULT NULL,
+        CONSTRAINT fk_authorization_code_user FOREIGN KEY (user_id, app_id) REFERENCES core.users (id, app_id) ON DELETE CASCADE,
+        CONSTRAINT fk_authorization_code_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_authorization_code_expiry ON core.authorization_codes (expires_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE core.apps
+DROP COLUMN granted_scopes;
\ No newline at end of file
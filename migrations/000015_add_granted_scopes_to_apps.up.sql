@@ -0,0 +1,3 @@
+-- Scopes an app may request for itself through the client_credentials grant
+ALTER TABLE core.apps
+ADD COLUMN granted_scopes TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.signing_keys;
\ No newline at end of file
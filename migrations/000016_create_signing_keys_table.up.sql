@@ -0,0 +1,14 @@
+-- Keys tokens are signed with. A key without activates_at is staged: it is
+-- published in the JWKS but does not sign yet. The private key is AES-GCM
+-- encrypted with the app secret key and is NULL for keys we only verify with.
+CREATE TABLE
+    IF NOT EXISTS core.signing_keys (
+        kid VARCHAR(64) NOT NULL PRIMARY KEY,
+        algorithm VARCHAR(16) NOT NULL,
+        private_key BYTEA NULL DEFAULT NULL,
+        public_key TEXT NOT NULL,
+        activates_at TIMESTAMPTZ NULL DEFAULT NULL,
+        retires_at TIMESTAMPTZ NULL DEFAULT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS core.idx_refresh_token_family;
+
+ALTER TABLE core.refresh_tokens
+DROP COLUMN rotated_at,
+DROP COLUMN parent_jti,
+DROP COLUMN family_id;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Refresh tokens rotated from the same login form a family. A rotated token
+-- keeps its row, marked by rotated_at, so a replay of it can be told apart
+-- from a token revoked by logout and the whole family revoked.
+ALTER TABLE core.refresh_tokens
+ADD COLUMN family_id VARCHAR(255) NULL,
+ADD COLUMN parent_jti VARCHAR(255) NULL,
+ADD COLUMN rotated_at TIMESTAMPTZ NULL DEFAULT NULL;
+
+UPDATE core.refresh_tokens
+SET family_id = jti
+WHERE family_id IS NULL;
+
+ALTER TABLE core.refresh_tokens
+ALTER COLUMN family_id
+SET
+    NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_family ON core.refresh_tokens (app_id, family_id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE core.refresh_tokens
+DROP COLUMN user_agent,
+DROP COLUMN ip_address,
+DROP COLUMN last_used_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- A session is a refresh token family; its active token carries when the
+-- session was last refreshed and from where, for the sessions API.
+ALTER TABLE core.refresh_tokens
+ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN ip_address VARCHAR(45) NULL,
+ADD COLUMN user_agent TEXT NULL;
+
+UPDATE core.refresh_tokens
+SET last_used_at = created_at;
\ No newline at end of file
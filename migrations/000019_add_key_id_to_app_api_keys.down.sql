@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS core.idx_app_api_keys_key_id;
+
+ALTER TABLE core.app_api_keys
+DROP COLUMN key_id;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- API keys are looked up by the public key id embedded in them
+-- (<prefix>_<key_id>_<secret>), and key_hash becomes an HMAC of the key.
+-- Older keys have no key id and keep their bcrypt hash; they are still
+-- accepted when the request names their app (X-App-Id or app_id), until
+-- they are re-issued with the apps_api_key seed.
+ALTER TABLE core.app_api_keys
+ADD COLUMN key_id VARCHAR(32) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_app_api_keys_key_id ON core.app_api_keys (key_id);
\ No newline at end of file
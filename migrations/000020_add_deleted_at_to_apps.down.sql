@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS core.idx_apps_deleted_at;
+
+ALTER TABLE core.apps
+DROP COLUMN deleted_at;
\ No newline at end of file
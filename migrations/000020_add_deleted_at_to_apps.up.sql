@@ -0,0 +1,6 @@
+-- Deleting an app only marks it; its users, tokens and keys are removed by
+-- the ON DELETE CASCADEs once the app is purged after APP_PURGE_DELAY_HOURS.
+ALTER TABLE core.apps
+ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_apps_deleted_at ON core.apps (deleted_at) WHERE deleted_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.app_allowed_redirect_urls;
\ No newline at end of file
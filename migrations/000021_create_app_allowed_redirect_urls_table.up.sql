@@ -0,0 +1,13 @@
+-- URLs an app may send users back to after login, register or federation
+-- (the callback_url and redirect_url query params). Entries match exactly, or
+-- per origin when they use a leading wildcard label (https://*.example.com).
+CREATE TABLE
+    IF NOT EXISTS core.app_allowed_redirect_urls (
+        id UUID NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        url TEXT NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT unique_allowed_redirect_url_per_app UNIQUE (app_id, url),
+        CONSTRAINT fk_app_allowed_redirect_url_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
\ No newline at end of file
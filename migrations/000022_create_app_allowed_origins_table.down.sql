@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.app_allowed_origins;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Browser origins allowed to call the API on behalf of an app, on top of the
+-- global ALLOWED_ORIGINS. Same format as ALLOWED_ORIGINS: an exact origin or
+-- one with a leading wildcard label (https://*.example.com).
+CREATE TABLE
+    IF NOT EXISTS core.app_allowed_origins (
+        id UUID NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        origin TEXT NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT unique_allowed_origin_per_app UNIQUE (app_id, origin),
+        CONSTRAINT fk_app_allowed_origin_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE core.apps
+ADD COLUMN require_email_verification BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE core.apps a
+SET require_email_verification = s.require_email_verification
+FROM core.app_settings s
+WHERE s.app_id = a.id;
+
+DROP TABLE IF EXISTS core.app_settings;
\ No newline at end of file
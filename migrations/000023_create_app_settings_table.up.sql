@@ -0,0 +1,27 @@
+-- Per-app security policy. Every app has exactly one row, created with the
+-- app; require_email_verification moves here from core.apps. A zero
+-- session_lifetime_seconds or max_sessions means no limit.
+CREATE TABLE
+    IF NOT EXISTS core.app_settings (
+        app_id UUID NOT NULL PRIMARY KEY,
+        access_token_ttl_seconds INTEGER NOT NULL DEFAULT 1800,
+        refresh_token_ttl_seconds INTEGER NOT NULL DEFAULT 2592000,
+        session_lifetime_seconds INTEGER NOT NULL DEFAULT 0,
+        password_min_length INTEGER NOT NULL DEFAULT 8,
+        password_require_uppercase BOOLEAN NOT NULL DEFAULT TRUE,
+        password_require_lowercase BOOLEAN NOT NULL DEFAULT FALSE,
+        password_require_digit BOOLEAN NOT NULL DEFAULT TRUE,
+        password_require_symbol BOOLEAN NOT NULL DEFAULT TRUE,
+        max_sessions INTEGER NOT NULL DEFAULT 0,
+        require_email_verification BOOLEAN NOT NULL DEFAULT FALSE,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_app_settings_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+INSERT INTO core.app_settings (app_id, require_email_verification)
+SELECT id, require_email_verification FROM core.apps
+ON CONFLICT (app_id) DO NOTHING;
+
+ALTER TABLE core.apps
+DROP COLUMN require_email_verification;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- Failed logins per app, counted per email and per client IP. Shared by
+-- every replica so a lockout holds whichever one serves the next attempt.
+-- A count is forgotten once no attempt failed for LOGIN_LOCKOUT_MINUTES.
+CREATE TABLE
+    IF NOT EXISTS core.login_failures (
+        app_id UUID NOT NULL,
+        subject_type VARCHAR(16) NOT NULL,
+        subject VARCHAR(255) NOT NULL,
+        failed_attempts INTEGER NOT NULL DEFAULT 0,
+        last_failed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        locked_until TIMESTAMPTZ NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (app_id, subject_type, subject),
+        CONSTRAINT check_login_failure_subject_type CHECK (subject_type IN ('email', 'ip')),
+        CONSTRAINT fk_login_failure_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_last_failed_at ON core.login_failures (last_failed_at);
\ No newline at end of file
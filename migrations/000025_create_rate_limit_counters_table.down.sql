@@ -0,0 +1 @@
+DROP TABLE IF EXISTS core.rate_limit_counters;
\ No newline at end of file
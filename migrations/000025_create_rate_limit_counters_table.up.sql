@@ -0,0 +1,12 @@
+-- Hits per rate limit key and fixed window, for limits shared by every
+-- replica (RATE_LIMIT_STORE=db). Unlogged, as losing the counts on a crash
+-- only lets a few more requests through.
+CREATE UNLOGGED TABLE
+    IF NOT EXISTS core.rate_limit_counters (
+        key VARCHAR(255) NOT NULL,
+        window_start TIMESTAMPTZ NOT NULL,
+        hits INTEGER NOT NULL DEFAULT 0,
+        PRIMARY KEY (key, window_start)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_window_start ON core.rate_limit_counters (window_start);
\ No newline at end of file
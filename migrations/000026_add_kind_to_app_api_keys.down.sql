@@ -0,0 +1,3 @@
+ALTER TABLE core.app_api_keys
+DROP CONSTRAINT IF EXISTS check_app_api_key_kind,
+DROP COLUMN IF EXISTS kind;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Publishable keys identify the app to the auth endpoints and end up in
+-- browsers; secret keys are kept server side and authenticate the app for
+-- the client_credentials grant and with it the /apps/{id} management API.
+-- Existing keys stay publishable; issue a secret key with
+-- `appctl rotate-key -app <app id> -kind secret`.
+ALTER TABLE core.app_api_keys
+ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'publishable',
+ADD CONSTRAINT check_app_api_key_kind CHECK (kind IN ('publishable', 'secret'));
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE core.app_identity_providers
+DROP COLUMN IF EXISTS trust_email;
\ No newline at end of file
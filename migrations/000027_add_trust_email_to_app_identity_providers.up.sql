@@ -0,0 +1,6 @@
+-- Whether an identity provider's email_verified claim is taken at its word.
+-- Only then is a federated login linked to an existing account with the same
+-- email; anyone managing the app can register a provider, and an untrusted
+-- one could otherwise claim any address and take the account over.
+ALTER TABLE core.app_identity_providers
+ADD COLUMN trust_email BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE core.federation_states
+DROP COLUMN IF EXISTS cookie_domain,
+DROP COLUMN IF EXISTS set_cookie;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- Keep set_cookie and cookie_domain from the start of a federated login so
+-- the callback can set the auth cookies; the provider redirects back without
+-- the client's original query parameters.
+ALTER TABLE core.federation_states
+ADD COLUMN set_cookie BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN cookie_domain VARCHAR(255);
\ No newline at end of file
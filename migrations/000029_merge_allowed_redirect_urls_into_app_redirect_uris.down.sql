@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS core.app_allowed_redirect_urls (
+        id UUID NOT NULL PRIMARY KEY,
+        app_id UUID NOT NULL,
+        url TEXT NOT NULL,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT unique_allowed_redirect_url_per_app UNIQUE (app_id, url),
+        CONSTRAINT fk_app_allowed_redirect_url_app FOREIGN KEY (app_id) REFERENCES core.apps (id) ON DELETE CASCADE
+    );
+
+-- Wildcard entries can only have come from the allowlist.
+INSERT INTO core.app_allowed_redirect_urls (id, app_id, url, created_at, updated_at)
+SELECT id, app_id, redirect_uri, created_at, updated_at
+FROM core.app_redirect_uris
+WHERE redirect_uri LIKE '%://*.%';
+
+DELETE FROM core.app_redirect_uris
+WHERE redirect_uri LIKE '%://*.%';
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- Redirect URLs for login, register and federation (callback_url and
+-- redirect_url) share the registered redirect uris of the OAuth flow; move
+-- the separate allowlist over. Wildcard entries (https://*.example.com) are
+-- only matched against callback_url and redirect_url, never /oauth/authorize.
+INSERT INTO core.app_redirect_uris (id, app_id, redirect_uri, created_at, updated_at)
+SELECT id, app_id, url, created_at, updated_at
+FROM core.app_allowed_redirect_urls
+ON CONFLICT (app_id, redirect_uri) DO NOTHING;
+
+DROP TABLE IF EXISTS core.app_allowed_redirect_urls;
\ No newline at end of file
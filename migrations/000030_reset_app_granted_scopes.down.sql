@@ -0,0 +1,4 @@
+-- Scopes granted before the reset are not kept; this only removes the
+-- defaults again.
+UPDATE core.apps
+SET granted_scopes = array_remove(array_remove(granted_scopes, 'apps:read'), 'apps:write'), updated_at = now();
\ No newline at end of file
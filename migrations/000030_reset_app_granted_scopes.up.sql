@@ -0,0 +1,5 @@
+-- Scopes used to be picked by whoever registered the app. Reset every app
+-- to the defaults of a new registration; operators grant anything else
+-- with appctl set-scopes.
+UPDATE core.apps
+SET granted_scopes = ARRAY['apps:read', 'apps:write'], updated_at = now();
\ No newline at end of file
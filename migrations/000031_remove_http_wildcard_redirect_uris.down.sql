@@ -0,0 +1,2 @@
+-- The removed entries are not restored.
+SELECT 1;
\ No newline at end of file
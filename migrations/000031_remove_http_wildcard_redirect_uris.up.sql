@@ -0,0 +1,4 @@
+-- Wildcard redirect uris have to be https; plain http entries would let
+-- callbacks and cookies go to any subdomain served without TLS.
+DELETE FROM core.app_redirect_uris
+WHERE redirect_uri LIKE 'http://*.%';
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE core.app_settings
+DROP COLUMN IF EXISTS google_client_ids;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Google ID tokens are accepted only when their aud is one of the app's own
+-- OAuth client ids, so one tenant's tokens cannot sign in to another app.
+ALTER TABLE core.app_settings
+ADD COLUMN google_client_ids TEXT[] NOT NULL DEFAULT '{}';
\ No newline at end of file